@@ -0,0 +1,199 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// kinds of aliases that are replaced when the document is written
+const (
+	aliasNbPages    = "nb"
+	aliasPageNumber = "pn"
+)
+
+// aliasChars are added to the font of any text containing an alias, so the
+// replacement can be rendered with the subset font
+const aliasChars = "0123456789"
+
+// aliasMarker starts the comment line that stands in for an alias inside the
+// content of a template. It is replaced when the template is written.
+const aliasMarker = "%gofpdf-alias"
+
+var aliasPlaceholder = regexp.MustCompile(`%gofpdf-alias (nb|pn) /(\S+)\n`)
+
+// AliasNbPages defines an alias for the total number of pages. It will be
+// substituted as the document is written, in the content of the pages and of
+// the templates used in the document. An empty string selects the default
+// alias "{nb}".
+func (gp *Fpdf) AliasNbPages(alias string) {
+	if alias == "" {
+		alias = "{nb}"
+	}
+	gp.setAlias(alias, aliasNbPages)
+}
+
+// AliasPageNumber defines an alias for the number of the page the text is
+// drawn on, its label if the pages are labeled with SetPageLabel. It will be
+// substituted as the document is written. Templates
+// containing the alias are written once for every page that uses them. An
+// empty string selects the default alias "{pn}".
+func (gp *Fpdf) AliasPageNumber(alias string) {
+	if alias == "" {
+		alias = "{pn}"
+	}
+	gp.setAlias(alias, aliasPageNumber)
+}
+
+func (gp *Fpdf) setAlias(alias string, kind string) {
+	if gp.aliases == nil {
+		gp.aliases = make(map[string]string)
+	}
+
+	for a, k := range gp.aliases {
+		if k == kind {
+			delete(gp.aliases, a)
+		}
+	}
+	gp.aliases[alias] = kind
+}
+
+// hasAlias returns true when the text contains any of the aliases
+func (gp *Fpdf) hasAlias(text string) bool {
+	for alias := range gp.aliases {
+		if strings.Contains(text, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// addAliasChars adds the characters needed to render the alias replacements
+// to the font
+func (gp *Fpdf) addAliasChars(f *SubsetFontObj, text string) error {
+	if !gp.hasAlias(text) {
+		return nil
+	}
+	return f.AddChars(aliasChars)
+}
+
+// aliasValue returns the replacement of an alias for the page at index
+func (gp *Fpdf) aliasValue(kind string, pageIndex int) string {
+	switch kind {
+	case aliasNbPages:
+		return strconv.Itoa(gp.PageCount())
+	case aliasPageNumber:
+		return gp.pageLabel(pageIndex)
+	}
+	return ""
+}
+
+// textSegment is part of a text that is either plain text or an alias
+type textSegment struct {
+	text  string
+	alias string // the kind of alias, empty for plain text
+}
+
+// splitAliases splits the text at the aliases
+func (gp *Fpdf) splitAliases(text string) []textSegment {
+	segments := []textSegment{{text: text}}
+
+	for alias, kind := range gp.aliases {
+		split := make([]textSegment, 0, len(segments))
+		for _, s := range segments {
+			if s.alias != "" {
+				split = append(split, s)
+				continue
+			}
+
+			parts := strings.Split(s.text, alias)
+			for x := 0; x < len(parts); x++ {
+				if x > 0 {
+					split = append(split, textSegment{text: alias, alias: kind})
+				}
+				if parts[x] != "" {
+					split = append(split, textSegment{text: parts[x]})
+				}
+			}
+		}
+		segments = split
+	}
+
+	return segments
+}
+
+// replaceAliases returns the text with all aliases replaced for the page at index
+func (gp *Fpdf) replaceAliases(text string, pageIndex int) string {
+	var buff strings.Builder
+	for _, s := range gp.splitAliases(text) {
+		if s.alias != "" {
+			buff.WriteString(gp.aliasValue(s.alias, pageIndex))
+		} else {
+			buff.WriteString(s.text)
+		}
+	}
+	return buff.String()
+}
+
+// writeAliasPlaceholder writes the comment that stands in for an alias in the
+// content of a template
+func writeAliasPlaceholder(w io.Writer, kind string, fontObjID string) {
+	fmt.Fprintf(w, "%s %s /%s\n", aliasMarker, kind, fontObjID)
+}
+
+// hasAliasPlaceholder returns true if the template content contains an alias of kind
+func hasAliasPlaceholder(b []byte, kind string) bool {
+	return bytes.Contains(b, []byte(fmt.Sprintf("%s %s ", aliasMarker, kind)))
+}
+
+// resolveAliasPlaceholders replaces the alias placeholders in the template
+// content with the glyphs of their value for the page at index
+func (gp *Fpdf) resolveAliasPlaceholders(b []byte, fonts []*SubsetFontObj, pageIndex int) ([]byte, error) {
+	if !bytes.Contains(b, []byte(aliasMarker)) {
+		return b, nil
+	}
+
+	var err error
+	resolved := aliasPlaceholder.ReplaceAllFunc(b, func(m []byte) []byte {
+		match := aliasPlaceholder.FindSubmatch(m)
+		kind, fontObjID := string(match[1]), string(match[2])
+
+		var font *SubsetFontObj
+		for x := 0; x < len(fonts); x++ {
+			if fonts[x].procsetIdentifier() == fontObjID {
+				font = fonts[x]
+			}
+		}
+		if font == nil {
+			err = fmt.Errorf("font %s of alias %s not found", fontObjID, kind)
+			return nil
+		}
+
+		buff := new(bytes.Buffer)
+		if e := writeGlyphs(buff, font, gp.aliasValue(kind, pageIndex)); e != nil {
+			err = e
+		}
+		return buff.Bytes()
+	})
+
+	return resolved, err
+}
+
+// templateHasPageAlias returns true if the template or any of the templates
+// it uses contain the page number alias
+func templateHasPageAlias(t Template) bool {
+	if hasAliasPlaceholder(t.Bytes(), aliasPageNumber) {
+		return true
+	}
+
+	templates := t.Templates()
+	for x := 0; x < len(templates); x++ {
+		if hasAliasPlaceholder(templates[x].Bytes(), aliasPageNumber) {
+			return true
+		}
+	}
+	return false
+}
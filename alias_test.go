@@ -0,0 +1,132 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAliasNbPages(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionMargin(10, 10, 10, 10))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf.AliasNbPages("")
+	pdf.AliasPageNumber("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(180)
+		pdf.Cell(100, 10, "Page {pn} of {nb}")
+	})
+
+	for x := 0; x < 3; x++ {
+		pdf.AddPage()
+		if err := pdf.SetFont("times", "", 12); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	font := pdf.curr.Font_ISubset
+	for _, text := range []string{"Page 1 of 3", "Page 2 of 3", "Page 3 of 3"} {
+		buff := new(bytes.Buffer)
+		if err := writeGlyphs(buff, font, text); err != nil {
+			t.Error(err)
+			return
+		}
+		if !bytes.Contains(b, buff.Bytes()) {
+			t.Errorf("%q not found in the document", text)
+		}
+	}
+}
+
+func TestAliasInTemplate(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf.AliasNbPages("{total}")
+	pdf.AliasPageNumber("{page}")
+	tpl, err := pdf.CreateTemplate(func(tp *Fpdf) error {
+		if err := tp.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+			return err
+		}
+		if err := tp.SetFont("times", "", 12); err != nil {
+			return err
+		}
+		return tp.Text(20, 20, "{page}/{total}")
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !hasAliasPlaceholder(tpl.Bytes(), aliasPageNumber) || !hasAliasPlaceholder(tpl.Bytes(), aliasNbPages) {
+		t.Error("the template should contain the alias placeholders")
+		return
+	}
+
+	for x := 0; x < 2; x++ {
+		if x > 0 {
+			pdf.AddPage()
+		}
+		if err := pdf.UseTemplate(tpl); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if bytes.Contains(b, []byte(aliasMarker)) {
+		t.Error("the alias placeholders should be replaced")
+	}
+
+	// the page number alias is written for every page using the template
+	for x, index := range pdf.pdfObjs.typeMap[pageType] {
+		if !bytes.Contains(b, []byte(templatePageID(tpl.ID(), index))) {
+			t.Errorf("template of page %d not found in the document", x+1)
+		}
+	}
+
+	font := tpl.Fonts()[0]
+	for _, text := range []string{"1", "2", "/"} {
+		buff := new(bytes.Buffer)
+		if err := writeGlyphs(buff, font, text); err != nil {
+			t.Error(err)
+			return
+		}
+		if !bytes.Contains(b, buff.Bytes()) {
+			t.Errorf("%q not found in the document", text)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package gofpdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const annotationType = "Annotation"
+
+// kinds of text markup annotations
+const (
+	MarkupHighlight = iota
+	MarkupUnderline
+	MarkupStrikeOut
+	MarkupSquiggly
+)
+
+var markupSubtypes = map[int]string{
+	MarkupHighlight: "Highlight",
+	MarkupUnderline: "Underline",
+	MarkupStrikeOut: "StrikeOut",
+	MarkupSquiggly:  "Squiggly",
+}
+
+// standard names of rubber stamps
+const (
+	StampApproved            = "Approved"
+	StampExperimental        = "Experimental"
+	StampNotApproved         = "NotApproved"
+	StampAsIs                = "AsIs"
+	StampExpired             = "Expired"
+	StampNotForPublicRelease = "NotForPublicRelease"
+	StampConfidential        = "Confidential"
+	StampFinal               = "Final"
+	StampSold                = "Sold"
+	StampDepartmental        = "Departmental"
+	StampForComment          = "ForComment"
+	StampTopSecret           = "TopSecret"
+	StampDraft               = "Draft"
+	StampForPublicRelease    = "ForPublicRelease"
+)
+
+// size of the icon of a note in points
+const noteSize = 20
+
+// AnnotationOption options of an annotation
+type AnnotationOption struct {
+	Contents string    // the text of notes and free text, the comment of the other annotations
+	Color    Color     // gray, RGB or CMYK, yellow for notes and markup and red for the others if nil
+	Interior Color     // the fill color of squares, circles and free text, not filled if nil
+	Opacity  float64   // between 0 and 1, opaque if 0
+	Author   string    // the author shown by the reader
+	Date     time.Time // the creation date, the current time if zero
+}
+
+// AnnotationObj an annotation shown on a page, links and the widgets of form
+// fields are written separately
+type AnnotationObj struct { //impl IObj
+	subtype  string
+	rect     [4]float64 // lower left and upper right corners
+	page     int
+	contents string
+	color    Color
+	interior Color
+	opacity  float64
+	author   string
+	date     time.Time
+	// name is the icon of a note or a file attachment or the name of a stamp
+	name string
+	// line holds the end points of a line, quadPoints the corners of the
+	// boxes of marked up text
+	line       []float64
+	quadPoints []float64
+	// font and fontSize of the text of free text annotations
+	font     *SubsetFontObj
+	fontSize float64
+	// fileSpec is the index of the attached file of a file attachment
+	fileSpec   int
+	appearance int
+	getRoot    func() *Fpdf
+}
+
+func (a *AnnotationObj) init(funcGetRoot func() *Fpdf) {
+	a.getRoot = funcGetRoot
+}
+
+func (a *AnnotationObj) getType() string {
+	return annotationType
+}
+
+func (a *AnnotationObj) write(w io.Writer, objID int) error {
+	protection := a.getRoot().protection()
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /Annot\n")
+	fmt.Fprintf(w, "  /Subtype /%s\n", a.subtype)
+	fmt.Fprintf(w, "  /Rect [%.2f %.2f %.2f %.2f]\n", a.rect[0], a.rect[1], a.rect[2], a.rect[3])
+	fmt.Fprintf(w, "  /P %d 0 R\n", a.page+1)
+	io.WriteString(w, "  /F 4\n")
+
+	strs := []struct {
+		key   string
+		value string
+	}{
+		{"Contents", a.contents},
+		{"T", a.author},
+	}
+	for _, s := range strs {
+		if s.value == "" {
+			continue
+		}
+		value, err := encodeTextString(s.value, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /%s %s\n", s.key, value)
+	}
+	date, err := encodeString([]byte("D:"+infodate(a.date)), protection, objID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "  /M %s\n", date)
+	fmt.Fprintf(w, "  /CreationDate %s\n", date)
+
+	if c := colorComponents(a.color); c != "" {
+		fmt.Fprintf(w, "  /C [%s]\n", c)
+	}
+	if c := colorComponents(a.interior); c != "" {
+		fmt.Fprintf(w, "  /IC [%s]\n", c)
+	}
+	if a.opacity > 0 && a.opacity < 1 {
+		fmt.Fprintf(w, "  /CA %.2f\n", a.opacity)
+	}
+	if a.name != "" {
+		fmt.Fprintf(w, "  /Name /%s\n", escapeName(a.name))
+	}
+	if a.line != nil {
+		fmt.Fprintf(w, "  /L [%s]\n", formatNumbers(a.line))
+	}
+	if a.quadPoints != nil {
+		fmt.Fprintf(w, "  /QuadPoints [%s]\n", formatNumbers(a.quadPoints))
+	}
+	switch a.subtype {
+	case "Square", "Circle", "Line", "FreeText":
+		io.WriteString(w, "  /BS << /W 1 >>\n")
+	}
+	if a.font != nil {
+		da, err := encodeString([]byte(fmt.Sprintf("/%s %.2f Tf", a.font.procsetIdentifier(), a.fontSize)), protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /DA %s\n", da)
+	}
+	if a.fileSpec >= 0 {
+		fmt.Fprintf(w, "  /FS %d 0 R\n", a.fileSpec+1)
+	}
+	if a.appearance >= 0 {
+		fmt.Fprintf(w, "  /AP << /N %d 0 R >>\n", a.appearance+1)
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// AddTextAnnotation adds a note to the current page, the reader shows the
+// contents of the options when the icon at x, y is opened
+func (gp *Fpdf) AddTextAnnotation(x, y float64, opt AnnotationOption) error {
+	rect := gp.pageRect(x, y, 0, 0)
+	rect[1] -= noteSize
+	rect[2] += noteSize
+	annot, err := gp.newAnnotation("Text", rect, opt, RGB{R: 255, G: 255})
+	if err != nil {
+		return err
+	}
+	annot.name = "Comment"
+
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%s\n0 G\n0.5 w\n0.25 0.25 %.2f %.2f re B\n", colorOperator(annot.color, false), float64(noteSize)-0.5, float64(noteSize)-0.5)
+	for x := 1; x <= 3; x++ {
+		y := float64(noteSize*x) / 4
+		fmt.Fprintf(&buff, "4 %.2f m %d %.2f l S\n", y, noteSize-4, y)
+	}
+	gp.addAnnotation(annot, buff.Bytes())
+	return nil
+}
+
+// AddFreeTextAnnotation adds the contents of the options as text written
+// directly on the current page, wrapped at the width of the box. x, y is the
+// upper left corner of the box, w and h its size. The text is drawn in the
+// current font, font size and text color, the border in the color of the
+// annotation.
+func (gp *Fpdf) AddFreeTextAnnotation(x, y, w, h float64, opt AnnotationOption) error {
+	if gp.curr.Font_ISubset == nil {
+		return errors.New("a free text annotation needs a font")
+	}
+	annot, err := gp.newAnnotation("FreeText", gp.pageRect(x, y, w, h), opt, RGB{R: 255})
+	if err != nil {
+		return err
+	}
+	annot.font = gp.curr.Font_ISubset
+	annot.fontSize = gp.curr.Font_Size
+	if err := annot.font.AddChars(annot.contents); err != nil {
+		return err
+	}
+
+	// the text is laid out like the value of a multiline text field
+	box := &FormFieldObj{
+		rect:       annot.rect,
+		value:      annot.contents,
+		font:       annot.font,
+		fontSize:   annot.fontSize,
+		color:      gp.TextColor(),
+		border:     annot.color,
+		background: annot.interior,
+	}
+	lines, err := gp.multilineLines(box)
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	box.writeBox(&buff, false)
+	if err := box.writeLines(&buff, lines); err != nil {
+		return err
+	}
+	gp.addAnnotation(annot, buff.Bytes(), annot.font)
+	return nil
+}
+
+// AddSquareAnnotation adds a rectangle to the current page, x, y is its upper
+// left corner, w and h its size
+func (gp *Fpdf) AddSquareAnnotation(x, y, w, h float64, opt AnnotationOption) error {
+	annot, err := gp.newAnnotation("Square", gp.pageRect(x, y, w, h), opt, RGB{R: 255})
+	if err != nil {
+		return err
+	}
+
+	width, height := annot.rect[2]-annot.rect[0], annot.rect[3]-annot.rect[1]
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%s\n1 w\n0.50 0.50 %.2f %.2f re %s\n", annot.colorOperators(), width-1, height-1, annot.paintOperator())
+	gp.addAnnotation(annot, buff.Bytes())
+	return nil
+}
+
+// AddCircleAnnotation adds the largest ellipse fitting the rectangle x, y, w,
+// h to the current page
+func (gp *Fpdf) AddCircleAnnotation(x, y, w, h float64, opt AnnotationOption) error {
+	annot, err := gp.newAnnotation("Circle", gp.pageRect(x, y, w, h), opt, RGB{R: 255})
+	if err != nil {
+		return err
+	}
+
+	width, height := annot.rect[2]-annot.rect[0], annot.rect[3]-annot.rect[1]
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%s\n1 w\n%s%s\n", annot.colorOperators(), ellipsePath(width/2, height/2, width/2-0.5, height/2-0.5), annot.paintOperator())
+	gp.addAnnotation(annot, buff.Bytes())
+	return nil
+}
+
+// AddLineAnnotation adds a line from x1, y1 to x2, y2 to the current page
+func (gp *Fpdf) AddLineAnnotation(x1, y1, x2, y2 float64, opt AnnotationOption) error {
+	start, end := gp.pageRect(x1, y1, 0, 0), gp.pageRect(x2, y2, 0, 0)
+	rect := [4]float64{
+		math.Min(start[0], end[0]) - 1, math.Min(start[1], end[1]) - 1,
+		math.Max(start[0], end[0]) + 1, math.Max(start[1], end[1]) + 1,
+	}
+	annot, err := gp.newAnnotation("Line", rect, opt, RGB{R: 255})
+	if err != nil {
+		return err
+	}
+	annot.line = []float64{start[0], start[1], end[0], end[1]}
+
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%s\n1 w\n%.2f %.2f m %.2f %.2f l S\n", colorOperator(annot.color, true),
+		start[0]-rect[0], start[1]-rect[1], end[0]-rect[0], end[1]-rect[1])
+	gp.addAnnotation(annot, buff.Bytes())
+	return nil
+}
+
+// AddMarkupAnnotation highlights, underlines, strikes out or squiggly
+// underlines text drawn with Cell at x, y in the current font and font size.
+// markup is MarkupHighlight, MarkupUnderline, MarkupStrikeOut or
+// MarkupSquiggly.
+func (gp *Fpdf) AddMarkupAnnotation(markup int, text string, x, y float64, opt AnnotationOption) error {
+	subtype, ok := markupSubtypes[markup]
+	if !ok {
+		return fmt.Errorf("unknown markup %d", markup)
+	}
+	font := gp.curr.Font_ISubset
+	if font == nil {
+		return errors.New("a markup annotation needs the font of the text")
+	}
+
+	if err := font.AddChars(text); err != nil {
+		return err
+	}
+	_, _, width, err := createContent(font, text, gp.curr.Font_Size, nil, TextOption{})
+	if err != nil {
+		return err
+	}
+	ttfp := font.GetTTFParser()
+	ascent := convertTypoUnit(float64(ttfp.TypoAscender()), ttfp.UnitsPerEm(), gp.curr.Font_Size)
+	descent := convertTypoUnit(float64(ttfp.TypoDescender()), ttfp.UnitsPerEm(), gp.curr.Font_Size)
+
+	// Cell draws the text below its upper left corner
+	rect := gp.pageRect(x, y, 0, 0)
+	rect[1] -= ascent - descent
+	rect[2] += width
+	annot, err := gp.newAnnotation(subtype, rect, opt, RGB{R: 255, G: 255})
+	if err != nil {
+		return err
+	}
+	annot.quadPoints = []float64{rect[0], rect[3], rect[2], rect[3], rect[0], rect[1], rect[2], rect[1]}
+
+	height := rect[3] - rect[1]
+	base := -descent
+	var buff bytes.Buffer
+	var states []string
+	switch markup {
+	case MarkupHighlight:
+		// the highlight is multiplied with the text instead of covering it
+		state, _ := gp.addProcsetObj(newExtGStateObj(1, 1, "Multiply"))
+		states = append(states, state)
+		fmt.Fprintf(&buff, "/%s gs\n%s\n0 0 %.2f %.2f re f\n", state, colorOperator(annot.color, false), width, height)
+	case MarkupUnderline:
+		fmt.Fprintf(&buff, "%s\n1 w\n0 %.2f m %.2f %.2f l S\n", colorOperator(annot.color, true), base-1.5, width, base-1.5)
+	case MarkupStrikeOut:
+		middle := base + ascent*0.35
+		fmt.Fprintf(&buff, "%s\n1 w\n0 %.2f m %.2f %.2f l S\n", colorOperator(annot.color, true), middle, width, middle)
+	case MarkupSquiggly:
+		fmt.Fprintf(&buff, "%s\n0.5 w\n0 %.2f m\n", colorOperator(annot.color, true), base-1)
+		for x := 2.0; x <= width; x += 2 {
+			fmt.Fprintf(&buff, "%.2f %.2f l\n", x, base-1-math.Mod(x/2, 2))
+		}
+		io.WriteString(&buff, "S\n")
+	}
+	gp.addAnnotation(annot, buff.Bytes()).states = states
+	return nil
+}
+
+// AddStampAnnotation adds a rubber stamp with the name to the current page,
+// x, y is its upper left corner, w and h its size. Standard names are the
+// Stamp constants. The name is drawn in the current font if one is set,
+// otherwise the reader draws the stamp.
+func (gp *Fpdf) AddStampAnnotation(name string, x, y, w, h float64, opt AnnotationOption) error {
+	if name == "" {
+		return errors.New("a stamp needs a name")
+	}
+	annot, err := gp.newAnnotation("Stamp", gp.pageRect(x, y, w, h), opt, RGB{R: 200})
+	if err != nil {
+		return err
+	}
+	annot.name = name
+
+	font := gp.curr.Font_ISubset
+	if font == nil {
+		gp.addAnnotation(annot, nil)
+		return nil
+	}
+
+	// words of the name are separated by their capital letters
+	var text []rune
+	for x, r := range name {
+		if x > 0 && unicode.IsUpper(r) {
+			text = append(text, ' ')
+		}
+		text = append(text, unicode.ToUpper(r))
+	}
+	if err := font.AddChars(string(text)); err != nil {
+		return err
+	}
+	_, _, width, err := createContent(font, string(text), 1, nil, TextOption{})
+	if err != nil {
+		return err
+	}
+
+	box := &FormFieldObj{
+		rect:     annot.rect,
+		font:     font,
+		color:    annot.color,
+		border:   annot.color,
+		align:    Center,
+		fontSize: annot.rect[3] - annot.rect[1] - 4*fieldPadding,
+	}
+	if size := (annot.rect[2] - annot.rect[0] - 4*fieldPadding) / width; size < box.fontSize {
+		box.fontSize = size
+	}
+	line, err := gp.singleLine(box, string(text))
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	box.writeBox(&buff, false)
+	if err := box.writeLines(&buff, []fieldLine{line}); err != nil {
+		return err
+	}
+	gp.addAnnotation(annot, buff.Bytes(), font)
+	return nil
+}
+
+// newAnnotation creates an annotation at rect on the current page, the color
+// of the annotation is def unless the options set one
+func (gp *Fpdf) newAnnotation(subtype string, rect [4]float64, opt AnnotationOption, def Color) (*AnnotationObj, error) {
+	if gp.currentPage() == nil {
+		return nil, errors.New("an annotation needs a page")
+	}
+
+	annot := &AnnotationObj{
+		subtype:    subtype,
+		rect:       rect,
+		page:       gp.curr.IndexOfPageObj,
+		contents:   opt.Contents,
+		color:      def,
+		opacity:    opt.Opacity,
+		author:     opt.Author,
+		date:       opt.Date,
+		fileSpec:   -1,
+		appearance: -1,
+	}
+	annot.init(func() *Fpdf {
+		return gp
+	})
+	if annot.date.IsZero() {
+		annot.date = time.Now()
+	}
+	if opt.Color != nil {
+		annot.color = opt.Color
+	}
+	annot.interior = opt.Interior
+	for _, c := range []Color{annot.color, annot.interior} {
+		if c != nil && colorComponents(c) == "" {
+			return nil, fmt.Errorf("unsupported annotation color %v", c)
+		}
+	}
+	return annot, nil
+}
+
+// addAnnotation adds the annotation to the document and to the annotations of
+// its page and returns its appearance. The content draws the annotation in
+// its rectangle, the reader draws the annotation if it is nil.
+func (gp *Fpdf) addAnnotation(annot *AnnotationObj, content []byte, fonts ...*SubsetFontObj) *AppearanceObj {
+	var appearance *AppearanceObj
+	if content != nil {
+		appearance = &AppearanceObj{
+			w:       annot.rect[2] - annot.rect[0],
+			h:       annot.rect[3] - annot.rect[1],
+			content: content,
+			fonts:   fonts,
+		}
+		appearance.init(func() *Fpdf {
+			return gp
+		})
+		annot.appearance = gp.addObj(appearance)
+	}
+
+	index := gp.addObj(annot)
+	page := gp.pdfObjs.getPage(annot.page)
+	page.annotations = append(page.annotations, index)
+	return appearance
+}
+
+// pageRect returns the lower left and upper right corners in points of the
+// rectangle x, y, w, h on the current page
+func (gp *Fpdf) pageRect(x, y, w, h float64) [4]float64 {
+	gp.UnitsToPointsVar(&x, &y, &w, &h)
+	pageHeight := gp.GetBoundaryHeight(PageBoundaryMedia)
+	return [4]float64{x, pageHeight - y - h, x + w, pageHeight - y}
+}
+
+// colorOperators returns the operators setting the stroke color and the fill
+// color of the interior
+func (a *AnnotationObj) colorOperators() string {
+	ops := colorOperator(a.color, true)
+	if a.interior != nil {
+		ops += "\n" + colorOperator(a.interior, false)
+	}
+	return ops
+}
+
+// paintOperator returns the operator painting the shape of the annotation,
+// filled if it has an interior color
+func (a *AnnotationObj) paintOperator() string {
+	if a.interior != nil {
+		return "B"
+	}
+	return "S"
+}
+
+// formatNumbers returns the numbers separated by spaces
+func formatNumbers(numbers []float64) string {
+	s := make([]string, len(numbers))
+	for x, n := range numbers {
+		s[x] = fmt.Sprintf("%.2f", n)
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,149 @@
+package gofpdf
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 400), PdfOptionNoCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTextAnnotation(10, 10, AnnotationOption{}); err == nil {
+		t.Error("an annotation without a page should fail")
+	}
+	pdf.AddPage()
+	if err := pdf.AddFreeTextAnnotation(10, 10, 100, 50, AnnotationOption{Contents: "free"}); err == nil {
+		t.Error("free text without a font should fail")
+	}
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetXY(10, 50)
+	if err := pdf.Cell(200, 20, "Reviewed text"); err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	opt := AnnotationOption{Contents: "Please check", Author: "Reviewer", Date: date, Opacity: 0.5}
+	annotations := []struct {
+		subtype string
+		add     func() error
+	}{
+		{"Text", func() error { return pdf.AddTextAnnotation(250, 10, opt) }},
+		{"FreeText", func() error {
+			o := opt
+			o.Interior = Gray(0.9)
+			return pdf.AddFreeTextAnnotation(10, 100, 150, 50, o)
+		}},
+		{"Square", func() error { return pdf.AddSquareAnnotation(10, 160, 50, 30, opt) }},
+		{"Circle", func() error {
+			o := opt
+			o.Color = CMYK{C: 100}
+			return pdf.AddCircleAnnotation(70, 160, 60, 30, o)
+		}},
+		{"Line", func() error { return pdf.AddLineAnnotation(10, 200, 100, 220, opt) }},
+		{"Highlight", func() error { return pdf.AddMarkupAnnotation(MarkupHighlight, "Reviewed text", 10, 50, opt) }},
+		{"StrikeOut", func() error { return pdf.AddMarkupAnnotation(MarkupStrikeOut, "Reviewed", 10, 50, opt) }},
+		{"Stamp", func() error { return pdf.AddStampAnnotation(StampNotApproved, 150, 300, 120, 40, opt) }},
+	}
+	for _, a := range annotations {
+		if err := a.add(); err != nil {
+			t.Fatalf("%s: %s", a.subtype, err)
+		}
+	}
+	if err := pdf.AddMarkupAnnotation(42, "Reviewed", 10, 50, opt); err == nil {
+		t.Error("an unknown markup should fail")
+	}
+	if err := pdf.AddSquareAnnotation(10, 10, 10, 10, AnnotationOption{Color: Spot{Name: "none"}}); err == nil {
+		t.Error("a spot color should fail")
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots := reader.resolve(pages[0]["Annots"]).(pdfArray)
+	if len(annots) != len(annotations) {
+		t.Fatalf("expected %d annotations, found %d", len(annotations), len(annots))
+	}
+
+	for x, ref := range annots {
+		annot := reader.resolve(ref).(pdfDict)
+		if annot["Subtype"] != pdfName(annotations[x].subtype) {
+			t.Errorf("expected a %s annotation, found %v", annotations[x].subtype, annot["Subtype"])
+			continue
+		}
+		if string(textString(annot["T"])) != "Reviewer" || string(textString(annot["Contents"])) != "Please check" {
+			t.Errorf("unexpected author or contents of %v", annot)
+		}
+		if ca, _ := pdfNumber(annot["CA"]); ca != 0.5 {
+			t.Errorf("unexpected opacity of %s %v", annotations[x].subtype, annot["CA"])
+		}
+		if d, _ := annot["CreationDate"].(pdfString); string(d) != "D:"+infodate(date) {
+			t.Errorf("unexpected date %q", d)
+		}
+		if _, ok := annot["AP"]; !ok {
+			t.Errorf("%s should have an appearance", annotations[x].subtype)
+		}
+	}
+
+	// the highlight covers the text drawn by the cell
+	font := pdf.curr.Font_ISubset
+	_, _, width, err := createContent(font, "Reviewed text", 12, nil, TextOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	highlight := reader.resolve(annots[5]).(pdfDict)
+	quads := reader.resolve(highlight["QuadPoints"]).(pdfArray)
+	if len(quads) != 8 {
+		t.Fatalf("expected 8 quad points, found %d", len(quads))
+	}
+	left, _ := pdfNumber(quads[0])
+	top, _ := pdfNumber(quads[1])
+	right, _ := pdfNumber(quads[2])
+	if left != 10 || top != 350 || right-left < width-0.01 || right-left > width+0.01 {
+		t.Errorf("unexpected quad points %v, the text is %.2f wide", quads, width)
+	}
+
+	// the highlight is painted with the multiply blend mode
+	ap := reader.resolve(highlight["AP"]).(pdfDict)
+	appearance := reader.resolve(ap["N"]).(pdfStream)
+	resources := reader.resolve(appearance.Dict["Resources"]).(pdfDict)
+	states := reader.resolve(resources["ExtGState"]).(pdfDict)
+	if len(states) != 1 {
+		t.Fatalf("expected 1 graphics state, found %v", resources)
+	}
+	for name, ref := range states {
+		state := reader.resolve(ref).(pdfDict)
+		content, err := reader.decodeStream(appearance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state["BM"] != pdfName("Multiply") || !strings.HasPrefix(string(content), "/"+name+" gs\n") {
+			t.Errorf("unexpected graphics state %v of the highlight %q", state, content)
+		}
+	}
+
+	circle := reader.resolve(annots[3]).(pdfDict)
+	if c := reader.resolve(circle["C"]).(pdfArray); len(c) != 4 {
+		t.Errorf("the circle should be CMYK, found %v", c)
+	}
+	stamp := reader.resolve(annots[7]).(pdfDict)
+	if stamp["Name"] != pdfName("NotApproved") {
+		t.Errorf("unexpected stamp %v", stamp["Name"])
+	}
+}
@@ -0,0 +1,395 @@
+package gofpdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AppendDocument moves the pages of other to the end of the document,
+// together with their content, fonts, images, templates, layers, bookmarks,
+// anchors, named destinations, links, form fields, annotations, attached
+// files and page labels. Fonts loaded from the same file and images with the
+// same data are shared with the document instead of being embedded twice.
+// The footers of other are rendered before its pages are moved and drawing
+// continues on the last appended page.
+//
+// Documents can be built in separate goroutines, but other must be complete
+// when it is appended and must not be used afterwards. An error is returned
+// if both documents define the same anchor, named destination, form field or
+// attached file, or if other is signed or has a structure tree.
+func (gp *Fpdf) AppendDocument(other *Fpdf) error {
+	if other == nil {
+		return errors.New("document is nil")
+	}
+	if other == gp {
+		return errors.New("a document can not be appended to itself")
+	}
+	if other.signature() != nil {
+		return errors.New("a signed document can not be appended")
+	}
+	if other.tags.root != nil {
+		return errors.New("a tagged document can not be appended")
+	}
+
+	for name := range other.anchors {
+		if _, ok := gp.anchors[name]; ok {
+			return fmt.Errorf("anchor %q is defined in both documents", name)
+		}
+	}
+	for name := range other.destinations {
+		if _, ok := gp.destinations[name]; ok {
+			return fmt.Errorf("named destination %q is defined in both documents", name)
+		}
+	}
+	for _, obj := range other.pdfObjs.allOf(formFieldType) {
+		if name := obj.(*FormFieldObj).name; name != "" && gp.formField(name) != nil {
+			return fmt.Errorf("form field %q is defined in both documents", name)
+		}
+	}
+	for _, obj := range other.pdfObjs.allOf(fileSpecType) {
+		if spec := obj.(*FileSpecObj); spec.document && gp.attachedFile(spec.name) != nil {
+			return fmt.Errorf("file %q is attached to both documents", spec.name)
+		}
+	}
+
+	if err := other.closePages(); err != nil {
+		return err
+	}
+	other.leavePage()
+	gp.leavePage()
+
+	fonts := make(map[*SubsetFontObj]*SubsetFontObj)
+	for _, font := range other.getAllSubsetFonts() {
+		f, err := gp.appendFont(font)
+		if err != nil {
+			return err
+		}
+		fonts[font] = f
+	}
+
+	for _, img := range other.getAllImages() {
+		if _, _, err := gp.registerImageByImageObj(img); err != nil {
+			return err
+		}
+	}
+
+	for _, state := range other.pdfObjs.allExtGStates() {
+		gp.addProcsetObj(state)
+	}
+
+	for _, shading := range other.pdfObjs.allShadings() {
+		gp.registerShading(shading)
+	}
+
+	for _, spot := range other.pdfObjs.allSpotColors() {
+		gp.addProcsetObj(spot)
+	}
+	for name, spot := range other.spotColors {
+		if _, ok := gp.spotColors[name]; ok {
+			continue
+		}
+		if gp.spotColors == nil {
+			gp.spotColors = make(map[string]*SpotColorObj)
+		}
+		gp.spotColors[name] = spot
+	}
+
+	for alias, kind := range other.aliases {
+		if _, ok := gp.aliases[alias]; ok {
+			continue
+		}
+		if gp.aliases == nil {
+			gp.aliases = make(map[string]string)
+		}
+		gp.aliases[alias] = kind
+	}
+
+	offset := gp.PageCount()
+	gp.appendPageLabels(other)
+	layers := gp.appendLayers(other)
+	pages := gp.appendPages(other, fonts, layers)
+	gp.appendTemplates(other, pages)
+
+	// radio groups are not shown on a page, their buttons are
+	for _, obj := range other.pdfObjs.allOf(formFieldType) {
+		if group := obj.(*FormFieldObj); group.kids != nil {
+			group.init(func() *Fpdf {
+				return gp
+			})
+			group.index = gp.addObj(group)
+		}
+	}
+
+	for _, obj := range other.pdfObjs.allOf(fileSpecType) {
+		if spec := obj.(*FileSpecObj); spec.document {
+			gp.appendFileSpec(other, spec)
+		}
+	}
+
+	for name, anchor := range other.anchors {
+		anchor.page = appendedPageIndex(pages, anchor.page)
+		gp.anchors[name] = anchor
+	}
+	for name, dest := range other.destinations {
+		if dest.Name == "" {
+			dest.Page += offset
+		}
+		if gp.destinations == nil {
+			gp.destinations = make(map[string]Destination)
+		}
+		gp.destinations[name] = dest
+	}
+
+	if index := other.pdfObjs.indexOfFirst(outlinesType); index >= 0 {
+		outlines := other.pdfObjs.objs[index].(*OutlinesObj)
+		if len(outlines.children) > 0 {
+			target := gp.pdfObjs.outlines()
+			gp.appendOutlines(outlines.children, target.index, &target.children, pages)
+		}
+	}
+
+	if len(pages) > 0 {
+		return gp.SetPage(gp.PageCount())
+	}
+	return nil
+}
+
+// appendFont returns the font of the document that replaces font, the
+// characters used by font are added to a font loaded from the same file
+func (gp *Fpdf) appendFont(font *SubsetFontObj) (*SubsetFontObj, error) {
+	if _, id, ok := gp.pdfObjs.hasProcsetObj(font); ok {
+		actual := gp.pdfObjs.getSubsetFont(id)
+		if err := actual.AddChars(font.CharacterToGlyphIndex.AllKeysString()); err != nil {
+			return nil, err
+		}
+		return actual, nil
+	}
+
+	if err := gp.AddTTFFontBySubsetFont(font.Family, font); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// appendLayers adds the layers of other to the document and returns the new
+// identifiers by the identifiers used in the content of other
+func (gp *Fpdf) appendLayers(other *Fpdf) map[string]string {
+	ids := make(map[string]string)
+	offset := len(gp.layers.list)
+	for _, ocg := range other.layers.list {
+		id := gp.AddLayerWithOption(ocg.name, ocg.option)
+		ids[ocg.procsetIdentifier()] = gp.layers.list[id].procsetIdentifier()
+	}
+
+	for _, group := range other.layers.radioGroups {
+		moved := make([]int, len(group))
+		for x := range group {
+			moved[x] = group[x] + offset
+		}
+		gp.layers.radioGroups = append(gp.layers.radioGroups, moved)
+	}
+
+	gp.layers.openPane = gp.layers.openPane || other.layers.openPane
+	return ids
+}
+
+// appendPages moves the pages of other and their content into the document.
+// The returned map holds the new index of every page by its index in other.
+func (gp *Fpdf) appendPages(other *Fpdf, fonts map[*SubsetFontObj]*SubsetFontObj, layers map[string]string) map[int]int {
+	root := func() *Fpdf {
+		return gp
+	}
+
+	offset := gp.PageCount()
+	pages := make(map[int]int)
+	contents := make([]*ContentObj, 0)
+	for _, index := range other.pdfObjs.typeMap[pageType] {
+		page := other.pdfObjs.getPage(index)
+		content := page.getContent()
+
+		// links to pages of other by their number
+		for _, l := range page.Links {
+			if l.dest != nil && l.file == "" && l.dest.Name == "" {
+				l.dest.Page += offset
+			}
+		}
+
+		page.getRoot = root
+		page.setOption(other.curr.pageOption.merge(page.pageOption))
+		page.ResourcesRelate = fmt.Sprintf("%d 0 R", gp.pdfObjs.indexOfFirst(procSetType)+1)
+		pages[index] = gp.pdfObjs.addPageObj(page)
+
+		content.getRoot = root
+		page.setIndexOfContentObj(gp.addObj(content))
+		contents = append(contents, content)
+
+		fields := page.fields
+		page.fields = make([]int, 0, len(fields))
+		for _, field := range fields {
+			page.fields = append(page.fields, gp.appendFormField(other, field, pages[index], fonts))
+		}
+
+		annotations := page.annotations
+		page.annotations = make([]int, 0, len(annotations))
+		for _, annot := range annotations {
+			page.annotations = append(page.annotations, gp.appendAnnotation(other, annot, pages[index], fonts))
+		}
+	}
+
+	// the caches refer to the pages and fonts of other
+	for _, content := range contents {
+		for _, cache := range content.listCache.caches {
+			switch c := cache.(type) {
+			case *cacheContentText:
+				c.getRoot = root
+				c.pageIndex = appendedPageIndex(pages, c.pageIndex)
+				if f, ok := fonts[c.fontSubset]; ok {
+					c.fontSubset = f
+				}
+			case *cacheContentUseTemplate:
+				c.getRoot = root
+				c.pageIndex = appendedPageIndex(pages, c.pageIndex)
+			case *cacheContentLayerBegin:
+				c.id = layers[c.id]
+			}
+		}
+	}
+
+	return pages
+}
+
+// appendFormField moves the form field at index in other and its appearance
+// into the document, the field is shown on the page at page
+func (gp *Fpdf) appendFormField(other *Fpdf, index int, page int, fonts map[*SubsetFontObj]*SubsetFontObj) int {
+	field := other.pdfObjs.objs[index].(*FormFieldObj)
+	if f, ok := fonts[field.font]; ok {
+		field.font = f
+	}
+
+	field.appearance = gp.appendAppearance(other, field.appearance, fonts)
+	if field.offAppearance >= 0 {
+		field.offAppearance = gp.appendAppearance(other, field.offAppearance, fonts)
+	}
+
+	field.init(func() *Fpdf {
+		return gp
+	})
+	field.page = page
+	field.index = gp.addObj(field)
+	return field.index
+}
+
+// appendAppearance moves the appearance at index in other into the document
+func (gp *Fpdf) appendAppearance(other *Fpdf, index int, fonts map[*SubsetFontObj]*SubsetFontObj) int {
+	appearance := other.pdfObjs.objs[index].(*AppearanceObj)
+	for x, font := range appearance.fonts {
+		if f, ok := fonts[font]; ok {
+			appearance.fonts[x] = f
+		}
+	}
+	appearance.init(func() *Fpdf {
+		return gp
+	})
+	return gp.addObj(appearance)
+}
+
+// appendAnnotation moves the annotation at index in other, its appearance and
+// its attached file into the document, the annotation is shown on the page at
+// page
+func (gp *Fpdf) appendAnnotation(other *Fpdf, index int, page int, fonts map[*SubsetFontObj]*SubsetFontObj) int {
+	annot := other.pdfObjs.objs[index].(*AnnotationObj)
+	if f, ok := fonts[annot.font]; ok {
+		annot.font = f
+	}
+	if annot.appearance >= 0 {
+		annot.appearance = gp.appendAppearance(other, annot.appearance, fonts)
+	}
+	if annot.fileSpec >= 0 {
+		annot.fileSpec = gp.appendFileSpec(other, other.pdfObjs.objs[annot.fileSpec].(*FileSpecObj))
+	}
+	annot.init(func() *Fpdf {
+		return gp
+	})
+	annot.page = page
+	return gp.addObj(annot)
+}
+
+// appendFileSpec moves the attached file of other into the document
+func (gp *Fpdf) appendFileSpec(other *Fpdf, spec *FileSpecObj) int {
+	root := func() *Fpdf {
+		return gp
+	}
+
+	file := other.pdfObjs.objs[spec.file].(*EmbeddedFileObj)
+	file.init(root)
+	spec.file = gp.addObj(file)
+	spec.init(root)
+	return gp.addObj(spec)
+}
+
+// appendTemplates moves the templates used by other into the document, the
+// variants written for a single page follow the page
+func (gp *Fpdf) appendTemplates(other *Fpdf, pages map[int]int) {
+	for _, obj := range other.pdfObjs.allOf(templateType) {
+		tpl := obj.(*TemplateObj)
+		tpl.getRoot = func() *Fpdf {
+			return gp
+		}
+		tpl.setProtection(gp.protection())
+		if tpl.pageIndex >= 0 {
+			tpl.pageIndex = appendedPageIndex(pages, tpl.pageIndex)
+		}
+		if tpl.imported != nil {
+			gp.registerImportedObjs(tpl.imported)
+		}
+		gp.addProcsetObj(tpl)
+	}
+}
+
+// appendOutlines adds the bookmarks of other below the outline item parent
+func (gp *Fpdf) appendOutlines(items []*OutlineObj, parent int, siblings *[]*OutlineObj, pages map[int]int) {
+	for _, item := range items {
+		children := item.children
+		item.init(func() *Fpdf {
+			return gp
+		})
+		item.dest.page = appendedPageIndex(pages, item.dest.page)
+		item.index = gp.addObj(item)
+		item.parent = parent
+
+		if l := len(*siblings); l > 0 {
+			prev := (*siblings)[l-1]
+			prev.next = item.index
+			item.prev = prev.index
+		}
+		*siblings = append(*siblings, item)
+
+		gp.appendOutlines(children, item.index, &item.children, pages)
+	}
+}
+
+// appendedPageIndex returns the index of an appended page, -1 if the page was
+// not appended
+func appendedPageIndex(pages map[int]int, index int) int {
+	if i, ok := pages[index]; ok {
+		return i
+	}
+	return -1
+}
+
+// appendPageLabels moves the page labels of other behind the pages of the
+// document. Appended pages without a label continue the page numbers.
+func (gp *Fpdf) appendPageLabels(other *Fpdf) {
+	offset := gp.PageCount()
+	if len(gp.pageLabels) > 0 {
+		if _, ok := other.pageLabels[1]; !ok {
+			gp.pageLabels[offset+1] = pageLabel{style: PageLabelDecimal, start: offset + 1}
+		}
+	}
+	for page, label := range other.pageLabels {
+		if gp.pageLabels == nil {
+			gp.pageLabels = make(map[int]pageLabel)
+		}
+		gp.pageLabels[page+offset] = label
+	}
+}
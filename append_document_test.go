@@ -0,0 +1,133 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAppendDocument(t *testing.T) {
+	section := func(n int) (*Fpdf, error) {
+		pdf, err := New(PdfOptionPageSize(200, 300+float64(n)*100))
+		if err != nil {
+			return nil, err
+		}
+		if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+			return nil, err
+		}
+		pdf.AliasNbPages("")
+		pdf.AliasPageNumber("")
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(280)
+			pdf.Cell(100, 20, "{pn}/{nb}")
+		})
+
+		pdf.AddPage()
+		if err := pdf.SetFont("times", "", 12); err != nil {
+			return nil, err
+		}
+		pdf.SetAnchor(fmt.Sprintf("section%d", n))
+		pdf.AddBookmark(fmt.Sprintf("Section %d", n), 0)
+		if err := pdf.Cell(100, 20, fmt.Sprintf("Section %d", n)); err != nil {
+			return nil, err
+		}
+		if err := pdf.Image("test/res/gopher01.jpg", 10, 50, Rect{W: 50, H: 50}); err != nil {
+			return nil, err
+		}
+		pdf.AddInternalLink("section0", 10, 10, 50, 12)
+		pdf.AddPage()
+		return pdf, pdf.Cell(100, 20, "continued")
+	}
+
+	// the sections are built concurrently and appended in order
+	sections := make([]*Fpdf, 3)
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	for x := range sections {
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			sections[x], errs[x] = section(x)
+		}(x)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pdf := sections[0]
+	for _, s := range sections[1:] {
+		if err := pdf.AppendDocument(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pdf.PageCount() != 6 || pdf.CurrentPageNumber() != 6 {
+		t.Errorf("expected to be on page 6 of 6, found %d of %d", pdf.CurrentPageNumber(), pdf.PageCount())
+	}
+	if n := len(pdf.getAllSubsetFonts()); n != 1 {
+		t.Errorf("expected 1 font, found %d", n)
+	}
+	if n := len(pdf.getAllImages()); n != 1 {
+		t.Errorf("expected 1 image, found %d", n)
+	}
+
+	// an anchor of the target can not be redefined
+	dup, err := section(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AppendDocument(dup); err == nil {
+		t.Error("appending a document with an existing anchor should fail")
+	}
+
+	pdf.SetNoCompression()
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 6 {
+		t.Fatalf("expected 6 pages, found %d", len(pages))
+	}
+	for x, page := range pages {
+		box := reader.resolve(page["MediaBox"]).(pdfArray)
+		if h, _ := pdfNumber(box[1]); h != 300+float64(x/2)*100 {
+			t.Errorf("page %d should keep the height of its section, found %v", x+1, h)
+		}
+	}
+
+	// the links of all sections and the first bookmark point at the first
+	// page
+	first := fmt.Sprintf("/Dest [%d 0 R ", pdf.pdfObjs.typeMap[pageType][0]+1)
+	if n := bytes.Count(b, []byte(first)); n != 4 {
+		t.Errorf("expected 4 destinations on the first page, found %d", n)
+	}
+
+	// the page number alias of the appended footers counts all pages
+	font := pdf.getAllSubsetFonts()[0]
+	var glyphs bytes.Buffer
+	if err := writeGlyphs(&glyphs, font, "6/6"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, glyphs.Bytes()) {
+		t.Error("the footer of the last page should read 6/6")
+	}
+
+	for x := 0; x < 3; x++ {
+		title, _ := encodeTextString(fmt.Sprintf("Section %d", x), nil, 0)
+		if !bytes.Contains(b, []byte("/Title "+title)) {
+			t.Errorf("bookmark of section %d not found", x)
+		}
+	}
+}
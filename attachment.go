@@ -0,0 +1,246 @@
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+	"unicode/utf16"
+)
+
+const embeddedFileType = "EmbeddedFile"
+const fileSpecType = "FileSpec"
+
+// EmbeddedFileObj the data of an attached file, always compressed
+type EmbeddedFileObj struct { //impl IObj
+	data    []byte
+	mime    string
+	modDate time.Time
+	getRoot func() *Fpdf
+}
+
+func (e *EmbeddedFileObj) init(funcGetRoot func() *Fpdf) {
+	e.getRoot = funcGetRoot
+}
+
+func (e *EmbeddedFileObj) getType() string {
+	return embeddedFileType
+}
+
+func (e *EmbeddedFileObj) write(w io.Writer, objID int) error {
+	gp := e.getRoot()
+	protection := gp.protection()
+
+	level := gp.compressLevel
+	if level == zlib.NoCompression {
+		level = zlib.DefaultCompression
+	}
+	var buff bytes.Buffer
+	ww, err := zlib.NewWriterLevel(&buff, level)
+	if err != nil {
+		return err
+	}
+	ww.Write(e.data)
+	ww.Close()
+
+	data, err := encryptStream(protection, objID, buff.Bytes())
+	if err != nil {
+		return err
+	}
+	date, err := encodeString([]byte("D:"+infodate(e.modDate)), protection, objID)
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(e.data)
+	checksum, err := encodeString(sum[:], protection, objID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /EmbeddedFile\n")
+	if e.mime != "" {
+		fmt.Fprintf(w, "  /Subtype /%s\n", escapeName(e.mime))
+	}
+	fmt.Fprintf(w, "  /Params << /Size %d /ModDate %s /CheckSum %s >>\n", len(e.data), date, checksum)
+	io.WriteString(w, "  /Filter /FlateDecode\n")
+	fmt.Fprintf(w, "  /Length %d\n", len(data))
+	io.WriteString(w, ">>\n")
+	io.WriteString(w, "stream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
+	return nil
+}
+
+// FileSpecObj the file specification of an attached file. Files attached to
+// the document are listed in the catalog, the others belong to an annotation.
+type FileSpecObj struct { //impl IObj
+	name        string
+	description string
+	file        int
+	document    bool
+	getRoot     func() *Fpdf
+}
+
+func (f *FileSpecObj) init(funcGetRoot func() *Fpdf) {
+	f.getRoot = funcGetRoot
+}
+
+func (f *FileSpecObj) getType() string {
+	return fileSpecType
+}
+
+func (f *FileSpecObj) write(w io.Writer, objID int) error {
+	protection := f.getRoot().protection()
+	file, err := encodeString([]byte(f.name), protection, objID)
+	if err != nil {
+		return err
+	}
+	name, err := encodeTextString(f.name, protection, objID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /Filespec\n")
+	fmt.Fprintf(w, "  /F %s\n", file)
+	fmt.Fprintf(w, "  /UF %s\n", name)
+	if f.description != "" {
+		desc, err := encodeTextString(f.description, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /Desc %s\n", desc)
+	}
+	fmt.Fprintf(w, "  /EF << /F %d 0 R /UF %d 0 R >>\n", f.file+1, f.file+1)
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// AttachFile attaches the file read from r to the document. name is the file
+// name shown by the reader and must be unique among the attached files, mime
+// is the media type of the file, e.g. "text/xml".
+func (gp *Fpdf) AttachFile(name, description, mime string, r io.Reader) error {
+	if gp.attachedFile(name) != nil {
+		return fmt.Errorf("file %q is already attached", name)
+	}
+
+	spec, err := gp.newFileSpec(name, description, mime, r)
+	if err != nil {
+		return err
+	}
+	spec.document = true
+	gp.addObj(spec)
+	return nil
+}
+
+// AddFileAnnotation attaches the file read from r to an annotation shown on
+// the current page, see AttachFile for the arguments. x, y is the upper left
+// corner of the annotation, w and h its size.
+func (gp *Fpdf) AddFileAnnotation(name, description, mime string, r io.Reader, x, y, w, h float64) error {
+	annot, err := gp.newAnnotation("FileAttachment", gp.pageRect(x, y, w, h), AnnotationOption{Contents: description}, RGB{R: 255, G: 255})
+	if err != nil {
+		return err
+	}
+
+	spec, err := gp.newFileSpec(name, description, mime, r)
+	if err != nil {
+		return err
+	}
+	annot.name = "PushPin"
+	annot.fileSpec = gp.addObj(spec)
+	gp.addAnnotation(annot, nil)
+	return nil
+}
+
+// newFileSpec reads the file from r and adds its data to the document
+func (gp *Fpdf) newFileSpec(name, description, mime string, r io.Reader) (*FileSpecObj, error) {
+	if name == "" {
+		return nil, errors.New("an attached file needs a name")
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &EmbeddedFileObj{
+		data:    data,
+		mime:    mime,
+		modDate: time.Now(),
+	}
+	file.init(func() *Fpdf {
+		return gp
+	})
+
+	spec := &FileSpecObj{
+		name:        name,
+		description: description,
+		file:        gp.addObj(file),
+	}
+	spec.init(func() *Fpdf {
+		return gp
+	})
+	return spec, nil
+}
+
+// attachedFile returns the file attached to the document with the name, nil
+// if there is none
+func (gp *Fpdf) attachedFile(name string) *FileSpecObj {
+	for _, obj := range gp.pdfObjs.allOf(fileSpecType) {
+		if spec := obj.(*FileSpecObj); spec.document && spec.name == name {
+			return spec
+		}
+	}
+	return nil
+}
+
+// documentFileSpecs returns the indexes of the files attached to the document
+func (gp *Fpdf) documentFileSpecs() []int {
+	var files []int
+	for _, index := range gp.pdfObjs.typeMap[fileSpecType] {
+		if gp.pdfObjs.at(index).(*FileSpecObj).document {
+			files = append(files, index)
+		}
+	}
+	return files
+}
+
+// writeEmbeddedFiles writes the name tree of the files attached to the
+// document, sorted by their names
+func (gp *Fpdf) writeEmbeddedFiles(w io.Writer, files []int, objID int) error {
+	type entry struct {
+		key   []uint16
+		index int
+		name  string
+	}
+	entries := make([]entry, 0, len(files))
+	for _, index := range files {
+		name := gp.pdfObjs.at(index).(*FileSpecObj).name
+		entries = append(entries, entry{utf16.Encode([]rune(name)), index, name})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].key, entries[j].key
+		for x := 0; x < len(a) && x < len(b); x++ {
+			if a[x] != b[x] {
+				return a[x] < b[x]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	io.WriteString(w, " /EmbeddedFiles << /Names [")
+	for _, e := range entries {
+		name, err := encodeTextString(e.name, gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, " %s %d 0 R", name, e.index+1)
+	}
+	io.WriteString(w, " ] >>")
+	return nil
+}
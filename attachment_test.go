@@ -0,0 +1,115 @@
+package gofpdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAttachFile(t *testing.T) {
+	invoice := `<?xml version="1.0" encoding="UTF-8"?><Invoice><ID>42</ID></Invoice>`
+	csv := "item,price\nbook,12.50\n"
+
+	pdf, err := New(PdfOptionPageSize(300, 400), PdfOptionNoCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddFileAnnotation("items.csv", "Items", "text/csv", strings.NewReader(csv), 10, 10, 20, 20); err == nil {
+		t.Error("an annotation without a page should fail")
+	}
+	pdf.AddPage()
+	if err := pdf.AttachFile("zugferd-invoice.xml", "Invoice", "text/xml", strings.NewReader(invoice)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AttachFile("items.csv", "", "text/csv", strings.NewReader(csv)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AttachFile("items.csv", "", "text/csv", strings.NewReader(csv)); err == nil {
+		t.Error("attaching a file twice should fail")
+	}
+	if err := pdf.AddFileAnnotation("items.csv", "Items", "text/csv", strings.NewReader(csv), 10, 10, 20, 20); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the name tree is sorted by the file names
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	names := reader.resolve(catalog["Names"]).(pdfDict)
+	files := reader.resolve(reader.resolve(names["EmbeddedFiles"]).(pdfDict)["Names"]).(pdfArray)
+	if len(files) != 4 {
+		t.Fatalf("expected 2 attached files, found %d entries", len(files))
+	}
+	for x, expected := range []struct {
+		name, data, mime string
+	}{
+		{"items.csv", csv, "text/csv"},
+		{"zugferd-invoice.xml", invoice, "text/xml"},
+	} {
+		if name := string(textString(files[x*2])); name != expected.name {
+			t.Errorf("expected file %q at %d, found %q", expected.name, x, name)
+		}
+		spec := reader.resolve(files[x*2+1]).(pdfDict)
+		stream := reader.resolve(reader.resolve(spec["EF"]).(pdfDict)["F"]).(pdfStream)
+		if stream.Dict["Subtype"] != pdfName(expected.mime) || stream.Dict["Filter"] != pdfName("FlateDecode") {
+			t.Errorf("unexpected embedded file %v", stream.Dict)
+		}
+		data, err := reader.decodeStream(stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected.data {
+			t.Errorf("unexpected data of %q: %q", expected.name, data)
+		}
+	}
+
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots := reader.resolve(pages[0]["Annots"]).(pdfArray)
+	if len(annots) != 1 {
+		t.Fatalf("expected 1 annotation, found %d", len(annots))
+	}
+	annot := reader.resolve(annots[0]).(pdfDict)
+	spec := reader.resolve(annot["FS"]).(pdfDict)
+	if annot["Subtype"] != pdfName("FileAttachment") || string(textString(spec["UF"])) != "items.csv" {
+		t.Errorf("unexpected annotation %v", annot)
+	}
+}
+
+func TestAttachFileProtection(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionProtectionAES256(PermissionsPrint, "user", "owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.AttachFile("secret.txt", "", "text/plain", strings.NewReader("secret data")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := referenceAES256Key(t, b, []byte("user"))
+	fileID := pdf.pdfObjs.indexOfFirst(embeddedFileType) + 1
+	compressed, err := referenceAESDecrypt(key, streamData(t, b, fileID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := flateDecode(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte("secret data")) {
+		t.Errorf("unexpected data %q", data)
+	}
+}
@@ -141,4 +141,17 @@ func (c *cacheContentClipPolygon) write(w io.Writer, protection *PDFProtection)
 }
 
 type cacheContentGradientClip struct {
+	pageHeight float64
+	x, y, w, h float64
+	id         string
+}
+
+// write clips the rectangle and maps the unit square to it, so the shading
+// coordinates are normalized to the rectangle
+func (c *cacheContentGradientClip) write(w io.Writer, protection *PDFProtection) error {
+	fmt.Fprintf(w, "q %.2f %.2f %.2f %.2f re W n\n", c.x, c.pageHeight-c.y, c.w, -c.h)
+	fmt.Fprintf(w, "%.5f 0 0 %.5f %.5f %.5f cm\n", c.w, c.h, c.x, c.pageHeight-(c.y+c.h))
+	fmt.Fprintf(w, "/%s sh\n", c.id)
+	fmt.Fprint(w, "Q\n")
+	return nil
 }
@@ -0,0 +1,15 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+type cacheContentExtGState struct {
+	id string
+}
+
+func (c *cacheContentExtGState) write(w io.Writer, protection *PDFProtection) error {
+	fmt.Fprintf(w, "/%s gs\n", c.id)
+	return nil
+}
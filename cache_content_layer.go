@@ -0,0 +1,22 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+type cacheContentLayerBegin struct {
+	id string
+}
+
+func (c *cacheContentLayerBegin) write(w io.Writer, protection *PDFProtection) error {
+	fmt.Fprintf(w, "/OC /%s BDC\n", c.id)
+	return nil
+}
+
+type cacheContentLayerEnd struct{}
+
+func (c *cacheContentLayerEnd) write(w io.Writer, protection *PDFProtection) error {
+	io.WriteString(w, "EMC\n")
+	return nil
+}
@@ -0,0 +1,29 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+// cacheContentMarkedContentBegin begins the marked content of a structure
+// element, or of an artifact if mcid is negative
+type cacheContentMarkedContentBegin struct {
+	tag  string
+	mcid int
+}
+
+func (c *cacheContentMarkedContentBegin) write(w io.Writer, protection *PDFProtection) error {
+	if c.mcid < 0 {
+		io.WriteString(w, "/Artifact BMC\n")
+		return nil
+	}
+	fmt.Fprintf(w, "/%s <</MCID %d>> BDC\n", c.tag, c.mcid)
+	return nil
+}
+
+type cacheContentMarkedContentEnd struct{}
+
+func (c *cacheContentMarkedContentEnd) write(w io.Writer, protection *PDFProtection) error {
+	io.WriteString(w, "EMC\n")
+	return nil
+}
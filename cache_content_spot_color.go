@@ -0,0 +1,28 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+// color spaces of spot colors
+const (
+	colorTypeStrokeSpot = "CS"
+	colorTypeFillSpot   = "cs"
+)
+
+type cacheContentSpotColor struct {
+	colorType string
+	id        string
+	tint      uint8
+}
+
+func (c *cacheContentSpotColor) write(w io.Writer, protection *PDFProtection) error {
+	op := "scn"
+	if c.colorType == colorTypeStrokeSpot {
+		op = "SCN"
+	}
+
+	fmt.Fprintf(w, "/%s %s %.3f %s\n", c.id, c.colorType, float64(c.tint)/cmykManipulant, op)
+	return nil
+}
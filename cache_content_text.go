@@ -15,7 +15,7 @@ const ContentTypeText = 1
 type cacheContentText struct {
 	//---setup---
 	rectangle   *Rect
-	textColor   Rgb
+	textColor   Color // nil when the text uses the fill color
 	grayFill    float64
 	fontObjId   string //Curr.Font_FontCount+1
 	fontSize    float64
@@ -29,6 +29,8 @@ type cacheContentText struct {
 	textOpt     TextOption
 	lineWidth   float64
 	text        string
+	pageIndex   int
+	getRoot     func() *Fpdf
 	//---result---
 	cellWidthPdfUnit, textWidthPdfUnit float64
 	cellHeightPdfUnit                  float64
@@ -39,7 +41,7 @@ func (c *cacheContentText) isSame(cache cacheContentText) bool {
 		//if rectangle != nil we assumes this is not same content
 		return false
 	}
-	if c.textColor.equal(cache.textColor) &&
+	if c.textColor == cache.textColor &&
 		c.grayFill == cache.grayFill &&
 		c.fontObjId == cache.fontObjId &&
 		c.fontSize == cache.fontSize &&
@@ -112,6 +114,27 @@ func (c *cacheContentText) calX() (float64, error) {
 }
 
 func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
+	if c.getRoot != nil {
+		root := c.getRoot()
+		if root.hasAlias(c.text) {
+			// templates keep a placeholder until they are written
+			if !root.compiling {
+				return c.writeText(w, root.splitAliases(c.text))
+			}
+
+			resolved := *c
+			resolved.text = root.replaceAliases(c.text, c.pageIndex)
+			if _, _, err := resolved.createContent(); err != nil {
+				return err
+			}
+			return resolved.writeText(w, []textSegment{{text: resolved.text}})
+		}
+	}
+
+	return c.writeText(w, []textSegment{{text: c.text}})
+}
+
+func (c *cacheContentText) writeText(w io.Writer, segments []textSegment) error {
 	// r := c.textColor.r
 	// g := c.textColor.g
 	// b := c.textColor.b
@@ -124,6 +147,12 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 		return err
 	}
 
+	// the text color is kept to the text
+	if c.textColor != nil {
+		io.WriteString(w, "q\n")
+		c.textColor.cache(false).write(w, nil)
+	}
+
 	io.WriteString(w, "BT\n")
 	fmt.Fprintf(w, "%0.2f %0.2f TD\n", x, y)
 	fmt.Fprintf(w, "/%s %0.2f Tf\n", c.fontObjId, c.fontSize)
@@ -141,21 +170,52 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 	// 	//c.AppendStreamSetGrayFill(grayFill)
 	// }
 
+	for _, segment := range segments {
+		if segment.alias != "" {
+			writeAliasPlaceholder(w, segment.alias, c.fontObjId)
+			continue
+		}
+
+		if err := writeGlyphs(w, c.fontSubset, segment.text); err != nil {
+			return err
+		}
+	}
+
+	io.WriteString(w, "ET\n")
+
+	if c.fontStyle&Underline == Underline {
+		err := c.underline(w, c.x, c.y, c.x+c.cellWidthPdfUnit, c.y)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.textColor != nil {
+		io.WriteString(w, "Q\n")
+	}
+
+	c.drawBorder(w)
+
+	return nil
+}
+
+// writeGlyphs writes the text as a TJ operation using the glyph indexes of the font
+func writeGlyphs(w io.Writer, f *SubsetFontObj, text string) error {
 	io.WriteString(w, "[<")
 
-	unitsPerEm := int(c.fontSubset.ttfp.UnitsPerEm())
+	unitsPerEm := int(f.ttfp.UnitsPerEm())
 	var leftRune rune
 	var leftRuneIndex uint
-	for i, r := range c.text {
+	for i, r := range text {
 
-		glyphindex, err := c.fontSubset.CharIndex(r)
+		glyphindex, err := f.CharIndex(r)
 		if err != nil {
 			return err
 		}
 
 		pairvalPdfUnit := 0
-		if i > 0 && c.fontSubset.ttfFontOption.UseKerning { //kerning
-			pairval := kern(c.fontSubset, leftRune, r, leftRuneIndex, glyphindex)
+		if i > 0 && f.ttfFontOption.UseKerning { //kerning
+			pairval := kern(f, leftRune, r, leftRuneIndex, glyphindex)
 			pairvalPdfUnit = convertTTFUnit2PDFUnit(int(pairval), unitsPerEm)
 			if pairvalPdfUnit != 0 {
 				fmt.Fprintf(w, ">%d<", (-1)*pairvalPdfUnit)
@@ -167,19 +227,8 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 		leftRuneIndex = glyphindex
 	}
 
-	io.WriteString(w, ">] TJ\n")
-	io.WriteString(w, "ET\n")
-
-	if c.fontStyle&Underline == Underline {
-		err := c.underline(w, c.x, c.y, c.x+c.cellWidthPdfUnit, c.y)
-		if err != nil {
-			return err
-		}
-	}
-
-	c.drawBorder(w)
-
-	return nil
+	_, err := io.WriteString(w, ">] TJ\n")
+	return err
 }
 
 func (c *cacheContentText) drawBorder(w io.Writer) error {
@@ -355,7 +404,7 @@ func (c *CacheContent) Setup(rectangle *Rect,
 	c.cacheContentText = cacheContentText{
 		fontSubset:  fontSubset,
 		rectangle:   rectangle,
-		textColor:   textColor,
+		textColor:   RGB{R: textColor.r, G: textColor.g, B: textColor.b},
 		grayFill:    grayFill,
 		fontObjId:   fontObjId,
 		fontSize:    fontSize,
@@ -13,6 +13,8 @@ type cacheContentUseTemplate struct {
 	sx         float64
 	sy         float64
 	h          float64
+	pageIndex  int
+	getRoot    func() *Fpdf
 }
 
 func (c *cacheContentUseTemplate) write(w io.Writer, protection *PDFProtection) error {
@@ -20,7 +22,23 @@ func (c *cacheContentUseTemplate) write(w io.Writer, protection *PDFProtection)
 	ty := c.pageHeight - c.y - c.h
 
 	fmt.Fprintf(w, "q %.4f 0 0 %.4f %.4f %.4f cm\n", c.sx, c.sy, tx, ty)
-	fmt.Fprintf(w, "/%s Do Q\n", c.id)
+	fmt.Fprintf(w, "/%s Do Q\n", c.name())
 
 	return nil
 }
+
+// name returns the name of the template, or of its variant for the page when
+// the template contains the page number alias. Content copied into another
+// template keeps the plain name, the variant is selected when that template
+// is written.
+func (c *cacheContentUseTemplate) name() string {
+	if c.getRoot == nil || !c.getRoot().compiling {
+		return c.id
+	}
+
+	pid := fmt.Sprintf("%sP%d", c.id, c.pageIndex)
+	if _, ok := c.getRoot().pdfObjs.hasProcsetID(pid); ok {
+		return pid
+	}
+	return c.id
+}
@@ -7,10 +7,11 @@ import (
 
 //CatalogObj : catalog dictionary
 type CatalogObj struct { //impl IObj
+	getRoot func() *Fpdf
 }
 
 func (c *CatalogObj) init(funcGetRoot func() *Fpdf) {
-
+	c.getRoot = funcGetRoot
 }
 
 func (c *CatalogObj) getType() string {
@@ -21,6 +22,43 @@ func (c *CatalogObj) write(w io.Writer, objID int) error {
 	io.WriteString(w, "<<\n")
 	fmt.Fprintf(w, "  /Type /%s\n", c.getType())
 	io.WriteString(w, "  /Pages 2 0 R\n")
+	if p := c.getRoot().protection(); p != nil && p.encryption == EncryptionAES256 {
+		// AES-256 is an extension of PDF 1.7 before PDF 2.0
+		io.WriteString(w, "  /Extensions << /ADBE << /BaseVersion /1.7 /ExtensionLevel 8 >> >>\n")
+	}
+	layers := c.getRoot().layers
+	if len(layers.list) > 0 {
+		layers.writeOCProperties(w, c.getRoot().conformance != 0)
+	}
+	pageMode := c.getRoot().viewer.pageMode != 0
+	if index := c.getRoot().pdfObjs.indexOfFirst(outlinesType); index >= 0 {
+		fmt.Fprintf(w, "  /Outlines %d 0 R\n", index+1)
+		if !layers.openPane && !pageMode {
+			io.WriteString(w, "  /PageMode /UseOutlines\n")
+		}
+	}
+	if layers.openPane && !pageMode {
+		io.WriteString(w, "  /PageMode /UseOC\n")
+	}
+	if err := c.getRoot().writeViewerOptions(w); err != nil {
+		return err
+	}
+	c.getRoot().writeMetadata(w)
+	c.getRoot().writeConformance(w)
+	if err := c.getRoot().writeStructure(w, objID); err != nil {
+		return err
+	}
+	if err := c.getRoot().writePageLabels(w, objID); err != nil {
+		return err
+	}
+	if err := c.getRoot().writeNames(w, objID); err != nil {
+		return err
+	}
+	if len(c.getRoot().pdfObjs.typeMap[formFieldType]) > 0 {
+		if err := c.getRoot().writeAcroForm(w, objID); err != nil {
+			return err
+		}
+	}
 	io.WriteString(w, ">>\n")
 	return nil
 }
@@ -14,6 +14,7 @@ func (gp *Fpdf) ClipRect(x, y, w, h float64, outline bool) {
 	style := "n"
 	if outline {
 		style = "S"
+		gp.markContent()
 	}
 
 	gp.currentContent().AppendStreamClipRect(x, y, w, h, style)
@@ -32,6 +33,7 @@ func (gp *Fpdf) ClipText(x, y float64, txtStr string, outline bool) {
 	style := 7
 	if outline {
 		style = 5
+		gp.markContent()
 	}
 
 	gp.currentContent().AppendStreamClipText(x, y, txtStr, style)
@@ -52,6 +54,7 @@ func (gp *Fpdf) ClipRoundedRect(x, y, w, h, r float64, outline bool) {
 	style := "n"
 	if outline {
 		style = "S"
+		gp.markContent()
 	}
 
 	gp.currentContent().AppendStreamClipRoundedRect(x, y, w, h, r, style)
@@ -71,6 +74,7 @@ func (gp *Fpdf) ClipEllipse(x, y, rx, ry float64, outline bool) {
 	style := "n"
 	if outline {
 		style = "S"
+		gp.markContent()
 	}
 
 	gp.currentContent().AppendStreamClipEllipse(x, y, rx, ry, style)
@@ -103,6 +107,7 @@ func (gp *Fpdf) ClipPolygon(points []Point, outline bool) {
 	style := "n"
 	if outline {
 		style = "S"
+		gp.markContent()
 	}
 
 	for x := 0; x < len(points); x++ {
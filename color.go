@@ -0,0 +1,231 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color a color of text, fill or stroke operations. It is implemented by
+// Gray, RGB, CMYK and Spot.
+type Color interface {
+	// cache returns the content setting the color for fill or stroke operations
+	cache(stroke bool) iCacheContent
+}
+
+// Gray a gray level between 0.0 (black) and 1.0 (white)
+type Gray float64
+
+func (c Gray) cache(stroke bool) iCacheContent {
+	cache := &cacheContentGray{grayType: grayTypeFill, scale: fixRange10(float64(c))}
+	if stroke {
+		cache.grayType = grayTypeStroke
+	}
+	return cache
+}
+
+// RGB a color of the red, green and blue components
+type RGB struct {
+	R, G, B uint8
+}
+
+func (c RGB) cache(stroke bool) iCacheContent {
+	cache := &cacheContentColor{colorType: colorTypeFillRGB, r: c.R, g: c.G, b: c.B}
+	if stroke {
+		cache.colorType = colorTypeStrokeRGB
+	}
+	return cache
+}
+
+// CMYK a color of the cyan, magenta, yellow and black components, each
+// ranging from 0 to 100 percent
+type CMYK struct {
+	C, M, Y, K uint8
+}
+
+func (c CMYK) cache(stroke bool) iCacheContent {
+	cache := &cacheContentColor{colorType: colorTypeFillCMYK, c: c.C, m: c.M, y: c.Y, k: c.K}
+	if stroke {
+		cache.colorType = colorTypeStrokeCMYK
+	}
+	return cache
+}
+
+// Spot a tint of a spot color added with AddSpotColor, ranging from 0 (no
+// intensity) to 100 (full intensity)
+type Spot struct {
+	Name string
+	Tint uint8
+
+	// id of the color space, set when the color is used
+	id string
+}
+
+func (c Spot) cache(stroke bool) iCacheContent {
+	cache := &cacheContentSpotColor{colorType: colorTypeFillSpot, id: c.id, tint: percentBound(c.Tint)}
+	if stroke {
+		cache.colorType = colorTypeStrokeSpot
+	}
+	return cache
+}
+
+// namedColors are the colors known by ParseColor
+var namedColors = map[string]RGB{
+	"black":   {0, 0, 0},
+	"white":   {255, 255, 255},
+	"red":     {255, 0, 0},
+	"lime":    {0, 255, 0},
+	"green":   {0, 128, 0},
+	"blue":    {0, 0, 255},
+	"yellow":  {255, 255, 0},
+	"cyan":    {0, 255, 255},
+	"aqua":    {0, 255, 255},
+	"magenta": {255, 0, 255},
+	"fuchsia": {255, 0, 255},
+	"gray":    {128, 128, 128},
+	"grey":    {128, 128, 128},
+	"silver":  {192, 192, 192},
+	"maroon":  {128, 0, 0},
+	"olive":   {128, 128, 0},
+	"navy":    {0, 0, 128},
+	"purple":  {128, 0, 128},
+	"teal":    {0, 128, 128},
+	"orange":  {255, 165, 0},
+}
+
+// ParseColor parses a hexadecimal color in the form "#rgb" or "#rrggbb", or
+// one of the basic named colors like "red" or "navy".
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := namedColors[s]; ok {
+		return c, nil
+	}
+
+	if !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("unknown color %q", s)
+	}
+
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("invalid hexadecimal color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hexadecimal color %q", s)
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+// SetTextColor sets the color of the text. Until it is called, text is
+// painted with the fill color.
+func (gp *Fpdf) SetTextColor(c Color) error {
+	c, err := gp.resolveColor(c)
+	if err != nil {
+		return err
+	}
+
+	gp.curr.setTextColor(c)
+	return nil
+}
+
+// TextColor returns the color of the text
+func (gp *Fpdf) TextColor() Color {
+	if c := gp.curr.textColor(); c != nil {
+		return c
+	}
+	return gp.curr.fillColor
+}
+
+// SetFillColor sets the color of fill operations
+func (gp *Fpdf) SetFillColor(c Color) error {
+	c, err := gp.resolveColor(c)
+	if err != nil {
+		return err
+	}
+
+	if g, ok := c.(Gray); ok {
+		gp.curr.grayFill = float64(g)
+	}
+	gp.curr.fillColor = c
+	if page := gp.currentPage(); page != nil {
+		gp.currentContent().AppendStreamSetColorFill(c)
+		page.fillColor = c
+	}
+	return nil
+}
+
+// FillColor returns the color of fill operations
+func (gp *Fpdf) FillColor() Color {
+	return gp.curr.fillColor
+}
+
+// SetStrokeColor sets the color of stroke operations
+func (gp *Fpdf) SetStrokeColor(c Color) error {
+	c, err := gp.resolveColor(c)
+	if err != nil {
+		return err
+	}
+
+	if g, ok := c.(Gray); ok {
+		gp.curr.grayStroke = float64(g)
+	}
+	gp.curr.strokeColor = c
+	if page := gp.currentPage(); page != nil {
+		gp.currentContent().AppendStreamSetColorStroke(c)
+		page.strokeColor = c
+	}
+	return nil
+}
+
+// StrokeColor returns the color of stroke operations
+func (gp *Fpdf) StrokeColor() Color {
+	return gp.curr.strokeColor
+}
+
+// resumeColors sets the fill and stroke colors on the current page, new
+// pages start black and pages returned to by SetPage keep the colors they
+// were left with
+func (gp *Fpdf) resumeColors() {
+	page := gp.currentPage()
+	if page == nil {
+		return
+	}
+
+	fill, stroke := page.fillColor, page.strokeColor
+	if fill == nil {
+		fill = Gray(0)
+	}
+	if stroke == nil {
+		stroke = Gray(0)
+	}
+	if gp.curr.fillColor != fill {
+		gp.currentContent().AppendStreamSetColorFill(gp.curr.fillColor)
+		page.fillColor = gp.curr.fillColor
+	}
+	if gp.curr.strokeColor != stroke {
+		gp.currentContent().AppendStreamSetColorStroke(gp.curr.strokeColor)
+		page.strokeColor = gp.curr.strokeColor
+	}
+}
+
+// resolveColor looks up the color space of spot colors
+func (gp *Fpdf) resolveColor(c Color) (Color, error) {
+	switch color := c.(type) {
+	case nil:
+		return nil, fmt.Errorf("color is nil")
+	case Spot:
+		spot, err := gp.spotColor(color.Name)
+		if err != nil {
+			return nil, err
+		}
+		color.id = spot.procsetIdentifier()
+		return color, nil
+	case *Spot:
+		return gp.resolveColor(*color)
+	}
+	return c, nil
+}
@@ -0,0 +1,166 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in  string
+		out Color
+	}{
+		{"#ff8000", RGB{255, 128, 0}},
+		{"#F80", RGB{255, 136, 0}},
+		{" Navy ", RGB{0, 0, 128}},
+		{"white", RGB{255, 255, 255}},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.in)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if c != test.out {
+			t.Errorf("%q: expected %v, found %v", test.in, test.out, c)
+		}
+	}
+
+	for _, in := range []string{"", "#12", "#gggggg", "unknown"} {
+		if _, err := ParseColor(in); err == nil {
+			t.Errorf("%q should not be parsed", in)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if c := pdf.TextColor(); c != Gray(0) {
+		t.Errorf("the text should use the fill color, found %v", c)
+	}
+
+	if err := pdf.SetFillColor(RGB{255, 0, 0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.SetStrokeColor(Gray(0.5)); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.SetTextColor(CMYK{0, 0, 0, 100}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.SetTextColor(Spot{Name: "unknown"}); err == nil {
+		t.Error("an unknown spot color should fail")
+	}
+
+	if c := pdf.FillColor(); c != (RGB{255, 0, 0}) {
+		t.Errorf("unexpected fill color %v", c)
+	}
+	if c := pdf.StrokeColor(); c != Gray(0.5) {
+		t.Errorf("unexpected stroke color %v", c)
+	}
+	if c := pdf.TextColor(); c != (CMYK{0, 0, 0, 100}) {
+		t.Errorf("unexpected text color %v", c)
+	}
+
+	if err := pdf.Cell(50, 20, "cmyk"); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.SetTextColor(Gray(0.25)); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.Cell(50, 20, "gray"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, s := range []string{
+		"1.00 0.00 0.00 rg\n", "0.50 G\n",
+		"q\n0.00 0.00 0.00 1.00 k\nBT", "q\n0.25 g\nBT",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+}
+
+func TestColorOnPages(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetNoCompression()
+
+	// the colors are set on the first page once it is added
+	if err := pdf.SetFillColor(RGB{255, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetStrokeColor(Gray(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetRGBTextColor(0, 128, 0)
+	if c := pdf.TextColor(); c != (RGB{0, 128, 0}) {
+		t.Errorf("unexpected text color %v", c)
+	}
+	pdf.AddPage()
+	pdf.AddPage()
+	if err := pdf.SetFillColor(RGB{0, 0, 255}); err != nil {
+		t.Fatal(err)
+	}
+	// the first page was left red
+	if err := pdf.SetPage(1); err != nil {
+		t.Fatal(err)
+	}
+	pdf.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both pages start with the colors and then turn blue
+	expected := "1.00 0.00 0.00 rg\n0.50 G\n0.00 0.00 1.00 rg\n"
+	for x := range pages {
+		content, err := reader.decodeStream(reader.resolve(pages[x]["Contents"]).(pdfStream))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(content, []byte(expected)) {
+			t.Errorf("%q not found in the content of page %d: %q", expected, x+1, content)
+		}
+	}
+}
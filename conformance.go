@@ -0,0 +1,221 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// conformance levels of the document
+const (
+	// PDFA2B PDF/A-2b, ISO 19005-2 level B, for the long term archival of
+	// the visual appearance of the document
+	PDFA2B = iota + 1
+)
+
+type conformancePdfOption struct {
+	level int
+}
+
+func (c *conformancePdfOption) apply(gp *Fpdf) error {
+	if c.level != PDFA2B {
+		return fmt.Errorf("conformance level %d is not valid", c.level)
+	}
+	gp.conformance = c.level
+	return nil
+}
+
+// PdfOptionConformance creates a PdfOption that makes the document conform
+// to the level, PDFA2B. The document is written with XMP metadata and an
+// sRGB output intent. Writing fails if the document uses a feature the level
+// forbids: encryption, attached files, launch links, JavaScript actions,
+// annotations without appearance, layers restricted to print or view,
+// DeviceCMYK colors, images or spot colors and fonts of imported pages that
+// are not embedded.
+func PdfOptionConformance(level int) PdfOption {
+	return &conformancePdfOption{level: level}
+}
+
+// checkConformance returns an error describing the first feature of the
+// document that its conformance level forbids
+func (gp *Fpdf) checkConformance() error {
+	if gp.isUseProtection() {
+		return errors.New("PDF/A forbids encryption")
+	}
+	if gp.xmp.packet != nil && !bytes.Contains(gp.xmp.packet, []byte(xmpNamespaces["pdfaid"])) {
+		return errors.New("PDF/A requires the XMP packet to identify the conformance level")
+	}
+	if len(gp.pdfObjs.typeMap[fileSpecType]) > 0 {
+		return errors.New("PDF/A-2 forbids attached files")
+	}
+	if len(gp.pdfObjs.allSpotColors()) > 0 {
+		return errors.New("PDF/A forbids spot colors with a DeviceCMYK alternate")
+	}
+	for _, ocg := range gp.layers.list {
+		if ocg.option.Usage != LayerUsageAll {
+			return fmt.Errorf("PDF/A forbids the usage of layer %q", ocg.name)
+		}
+	}
+	for _, obj := range gp.pdfObjs.allOf(annotationType) {
+		annot := obj.(*AnnotationObj)
+		if annot.appearance < 0 {
+			return fmt.Errorf("PDF/A forbids the %s annotation without appearance", annot.subtype)
+		}
+		if isCMYK(annot.color, annot.interior) {
+			return fmt.Errorf("PDF/A forbids the DeviceCMYK color of the %s annotation with an sRGB output intent", annot.subtype)
+		}
+	}
+	for _, obj := range gp.pdfObjs.allOf(formFieldType) {
+		field := obj.(*FormFieldObj)
+		if field.action != nil && !pdfaActions[field.action.kind()] {
+			return fmt.Errorf("PDF/A forbids the %s action of the field %q", field.action.kind(), field.name)
+		}
+		if isCMYK(field.color, field.border, field.background) {
+			return fmt.Errorf("PDF/A forbids the DeviceCMYK color of the field %q with an sRGB output intent", field.name)
+		}
+	}
+	for _, img := range gp.getAllImages() {
+		if img.imginfo.colspace == "DeviceCMYK" {
+			return errors.New("PDF/A forbids DeviceCMYK images with an sRGB output intent")
+		}
+	}
+
+	for n, page := range gp.getAllPages() {
+		for _, l := range page.Links {
+			if l.dest == nil && l.file != "" {
+				return fmt.Errorf("PDF/A forbids the launch link on page %d", n+1)
+			}
+			if isCMYK(l.style.BorderColor) {
+				return fmt.Errorf("PDF/A forbids the DeviceCMYK border color of the link on page %d with an sRGB output intent", n+1)
+			}
+		}
+		if usesCMYK(page.getContent().listCache.caches) {
+			return fmt.Errorf("PDF/A forbids the DeviceCMYK color on page %d with an sRGB output intent", n+1)
+		}
+	}
+	for _, obj := range gp.pdfObjs.allOf(templateType) {
+		if obj.(*TemplateObj).cmyk {
+			return errors.New("PDF/A forbids the DeviceCMYK color of a template with an sRGB output intent")
+		}
+	}
+
+	for _, obj := range gp.pdfObjs.allOf(importedType) {
+		if name := unembeddedFont(obj.(*ImportedObj).value); name != "" {
+			return fmt.Errorf("PDF/A forbids the font %s that is not embedded", name)
+		}
+	}
+	return nil
+}
+
+// pdfaActions the kinds of actions PDF/A-2 permits, JavaScript, Launch,
+// ResetForm, ImportData and the other kinds are forbidden
+var pdfaActions = map[string]bool{
+	"GoTo":       true,
+	"GoToR":      true,
+	"URI":        true,
+	"SubmitForm": true,
+}
+
+// usesCMYK returns true if the content sets a DeviceCMYK color
+func usesCMYK(caches []iCacheContent) bool {
+	for _, cache := range caches {
+		switch c := cache.(type) {
+		case *cacheContentColor:
+			if c.colorType == colorTypeFillCMYK || c.colorType == colorTypeStrokeCMYK {
+				return true
+			}
+		case *cacheContentText:
+			if _, ok := c.textColor.(CMYK); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCMYK returns true if one of the colors is a DeviceCMYK color
+func isCMYK(colors ...Color) bool {
+	for _, c := range colors {
+		if _, ok := c.(CMYK); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// unembeddedFont returns the name of the font described by the imported
+// value if its program is not embedded
+func unembeddedFont(v interface{}) string {
+	dict, ok := v.(pdfDict)
+	if !ok {
+		if stream, ok := v.(pdfStream); ok {
+			dict = stream.Dict
+		}
+	}
+
+	switch dict["Type"] {
+	case pdfName("FontDescriptor"):
+		for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+			if _, ok := dict[key]; ok {
+				return ""
+			}
+		}
+		name, _ := dict["FontName"].(pdfName)
+		return string(name)
+	case pdfName("Font"):
+		// the standard fonts have no font descriptor
+		_, descriptor := dict["FontDescriptor"]
+		if subtype := dict["Subtype"]; !descriptor && subtype != pdfName("Type0") && subtype != pdfName("Type3") {
+			name, _ := dict["BaseFont"].(pdfName)
+			return string(name)
+		}
+	}
+	return ""
+}
+
+// addConformanceObjs adds the color profile of the output intent the
+// conformance level requires
+func (gp *Fpdf) addConformanceObjs() {
+	if gp.pdfObjs.indexOfFirst(iccProfileType) < 0 {
+		profile := &ICCProfileObj{
+			profile:    srgbProfile(),
+			components: 3,
+		}
+		profile.init(func() *Fpdf {
+			return gp
+		})
+		gp.addObj(profile)
+	}
+}
+
+// writeConformance writes the entries of the catalog required by the
+// conformance level
+func (gp *Fpdf) writeConformance(w io.Writer) {
+	if index := gp.pdfObjs.indexOfFirst(iccProfileType); index >= 0 {
+		io.WriteString(w, "  /OutputIntents [<< /Type /OutputIntent /S /GTS_PDFA1")
+		io.WriteString(w, " /OutputConditionIdentifier (sRGB IEC61966-2.1) /Info (sRGB IEC61966-2.1)")
+		fmt.Fprintf(w, " /RegistryName (http://www.color.org) /DestOutputProfile %d 0 R >>]\n", index+1)
+	}
+}
+
+// fileID returns the identifier of the document in the trailer, nil if it
+// has none
+func (gp *Fpdf) fileID() []byte {
+	if gp.isUseProtection() {
+		return gp.protection().fileID
+	}
+	if gp.conformance == 0 {
+		return nil
+	}
+
+	info := gp.GetInfo()
+	sum := md5.New()
+	io.WriteString(sum, infodate(gp.documentDate()))
+	for _, s := range []string{info.Title, info.Author, info.Subject, info.Keywords, info.Creator, info.Producer} {
+		io.WriteString(sum, s)
+	}
+	fmt.Fprintf(sum, "%d", len(gp.pdfObjs.objs))
+	return sum.Sum(nil)
+}
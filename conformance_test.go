@@ -0,0 +1,166 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestConformancePDFA2B(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionConformance(PDFA2B), PdfOptionTitle("Archive <2021>"), PdfOptionAuthor("Records"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.Cell(100, 20, "Archived"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("%PDF-1.7\n\n%\xe2\xe3\xcf\xd3\n")) {
+		t.Errorf("the header should mark the file as binary, found %q", b[:20])
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := reader.trailer["ID"].(pdfArray); !ok || len(id) != 2 {
+		t.Errorf("the trailer should have an ID, found %v", reader.trailer["ID"])
+	}
+
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	metadata := reader.resolve(catalog["Metadata"]).(pdfStream)
+	if metadata.Dict["Subtype"] != pdfName("XML") || metadata.Dict["Filter"] != nil {
+		t.Errorf("unexpected metadata %v", metadata.Dict)
+	}
+	info := reader.resolve(reader.trailer["Info"]).(pdfDict)
+	created := strings.TrimPrefix(string(info["CreationDate"].(pdfString)), "D:")
+	xmpCreated := created[:4] + "-" + created[4:6] + "-" + created[6:8] + "T" + created[8:10] + ":" + created[10:12] + ":" + created[12:14] +
+		strings.Replace(strings.TrimSuffix(created[14:], "'"), "'", ":", 1)
+	for _, s := range []string{
+		"<pdfaid:part>2</pdfaid:part>",
+		"<pdfaid:conformance>B</pdfaid:conformance>",
+		"<rdf:li xml:lang=\"x-default\">Archive &lt;2021&gt;</rdf:li>",
+		"<rdf:li>Records</rdf:li>",
+		"<xmp:CreateDate>" + xmpCreated + "</xmp:CreateDate>",
+	} {
+		if !bytes.Contains(metadata.Data, []byte(s)) {
+			t.Errorf("%q not found in the metadata", s)
+		}
+	}
+
+	intents := reader.resolve(catalog["OutputIntents"]).(pdfArray)
+	intent := reader.resolve(intents[0]).(pdfDict)
+	if intent["S"] != pdfName("GTS_PDFA1") {
+		t.Errorf("unexpected output intent %v", intent)
+	}
+	stream := reader.resolve(intent["DestOutputProfile"]).(pdfStream)
+	profile, err := reader.decodeStream(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := pdfNumber(stream.Dict["N"]); n != 3 || int(binary.BigEndian.Uint32(profile)) != len(profile) ||
+		string(profile[12:24]) != "mntrRGB XYZ " || string(profile[36:40]) != "acsp" {
+		t.Errorf("unexpected color profile %q", profile[:40])
+	}
+}
+
+func TestConformanceViolations(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmykTemplate := func(pdf *Fpdf) (Template, error) {
+		return pdf.CreateTemplateCustom(Point{}, func(tpl *Fpdf) error {
+			if err := tpl.SetFillColor(CMYK{M: 100}); err != nil {
+				return err
+			}
+			tpl.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+			return nil
+		}, PdfOptionPageSize(100, 100))
+	}
+
+	violations := []struct {
+		name string
+		opts []PdfOption
+		draw func(pdf *Fpdf) error
+	}{
+		{"encryption", []PdfOption{PdfOptionProtection(PermissionsPrint, "user", "owner")}, nil},
+		{"attached file", nil, func(pdf *Fpdf) error {
+			return pdf.AttachFile("data.csv", "", "text/csv", strings.NewReader("a,b"))
+		}},
+		{"launch link", nil, func(pdf *Fpdf) error { return pdf.AddLaunchLink("run.exe", 10, 10, 20, 20) }},
+		{"CMYK color", nil, func(pdf *Fpdf) error {
+			pdf.SetCMYKFillColor(0, 100, 100, 0)
+			pdf.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+			return nil
+		}},
+		{"annotation without appearance", nil, func(pdf *Fpdf) error {
+			return pdf.AddStampAnnotation(StampApproved, 10, 10, 50, 20, AnnotationOption{})
+		}},
+		{"JavaScript action", nil, func(pdf *Fpdf) error {
+			return pdf.AddPushButton("run", "", 10, 10, 50, 20, PushButtonOption{JavaScript: "app.alert('run')"})
+		}},
+		{"CMYK annotation", nil, func(pdf *Fpdf) error {
+			return pdf.AddSquareAnnotation(10, 10, 50, 20, AnnotationOption{Color: CMYK{C: 100}})
+		}},
+		{"CMYK field", nil, func(pdf *Fpdf) error {
+			return pdf.AddCheckbox("agree", 10, 10, 20, 20, CheckboxOption{Background: CMYK{Y: 100}})
+		}},
+		{"CMYK link border", nil, func(pdf *Fpdf) error {
+			if err := pdf.SetLinkStyle(LinkStyle{BorderWidth: 1, BorderColor: CMYK{K: 100}}); err != nil {
+				return err
+			}
+			pdf.AddExternalLink("https://example.com", 10, 10, 50, 20)
+			return nil
+		}},
+		{"CMYK template", nil, func(pdf *Fpdf) error {
+			tpl, err := cmykTemplate(pdf)
+			if err != nil {
+				return err
+			}
+			return pdf.UseTemplate(tpl)
+		}},
+		{"CMYK signature appearance", nil, func(pdf *Fpdf) error {
+			tpl, err := cmykTemplate(pdf)
+			if err != nil {
+				return err
+			}
+			opt := SignatureOption{Signer: key, Certificates: []*x509.Certificate{selfSignedCertificate(t, key)}, Appearance: tpl}
+			return pdf.AddSignatureField("signature", 10, 10, 50, 20, opt)
+		}},
+	}
+	for _, v := range violations {
+		pdf, err := New(append([]PdfOption{PdfOptionPageSize(200, 200), PdfOptionConformance(PDFA2B)}, v.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.AddPage()
+		if v.draw != nil {
+			if err := v.draw(pdf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := pdf.GetBytesPdfReturnErr(); err == nil || !strings.Contains(err.Error(), "PDF/A") {
+			t.Errorf("the %s should fail, found %v", v.name, err)
+		}
+	}
+
+	if _, err := New(PdfOptionConformance(42)); err == nil {
+		t.Error("an unknown conformance level should fail")
+	}
+}
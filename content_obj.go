@@ -52,27 +52,21 @@ func (c *ContentObj) write(w io.Writer, objID int) error {
 		}
 	}
 
-	streamlen := buff.Len()
+	data, err := encryptStream(c.protection(), objID, buff.Bytes())
+	if err != nil {
+		return err
+	}
 
 	io.WriteString(w, "<<\n")
 	if isFlate {
 		io.WriteString(w, "/Filter/FlateDecode")
 	}
-	fmt.Fprintf(w, "/Length %d\n", streamlen)
+	fmt.Fprintf(w, "/Length %d\n", len(data))
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
-	if c.protection() != nil {
-		tmp, err := rc4Cip(c.protection().objectkey(objID), buff.Bytes())
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
+	w.Write(data)
+	if isFlate || c.protection() != nil {
 		io.WriteString(w, "\n")
-	} else {
-		buff.WriteTo(w)
-		if isFlate {
-			io.WriteString(w, "\n")
-		}
 	}
 	io.WriteString(w, "endstream\n")
 
@@ -111,6 +105,12 @@ func (c *ContentObj) AppendStreamText(text string) error {
 		pageheight:  c.getRoot().GetBoundaryHeight(PageBoundaryMedia),
 		contentType: ContentTypeText,
 		lineWidth:   c.getRoot().curr.lineWidth,
+		pageIndex:   c.getRoot().curr.IndexOfPageObj,
+		getRoot:     c.getRoot,
+	}
+
+	if err := c.getRoot().addAliasChars(fontSubset, text); err != nil {
+		return err
 	}
 
 	var err error
@@ -151,6 +151,12 @@ func (c *ContentObj) AppendStreamSubsetFont(rectangle Rect, text string, cellOpt
 		cellOpt:     cellOpt,
 		lineWidth:   c.getRoot().curr.lineWidth,
 		textOpt:     textOpts,
+		pageIndex:   c.getRoot().curr.IndexOfPageObj,
+		getRoot:     c.getRoot,
+	}
+
+	if err := c.getRoot().addAliasChars(fontSubset, text); err != nil {
+		return err
 	}
 	var err error
 	c.getRoot().curr.X, c.getRoot().curr.Y, err = c.listCache.appendContentText(cache, text)
@@ -220,6 +226,60 @@ func (c *ContentObj) AppendStreamLineTo(x, y float64) {
 	c.listCache.append(&cache)
 }
 
+// AppendStreamSetColorFill set the color for the fill
+func (c *ContentObj) AppendStreamSetColorFill(color Color) {
+	c.listCache.append(color.cache(false))
+}
+
+// AppendStreamSetColorStroke set the color for the stroke
+func (c *ContentObj) AppendStreamSetColorStroke(color Color) {
+	c.listCache.append(color.cache(true))
+}
+
+// AppendStreamLayerBegin begins the marked content of a layer
+func (c *ContentObj) AppendStreamLayerBegin(id string) {
+	var cache cacheContentLayerBegin
+	cache.id = id
+	c.listCache.append(&cache)
+}
+
+// AppendStreamLayerEnd ends the marked content of a layer
+func (c *ContentObj) AppendStreamLayerEnd() {
+	c.listCache.append(new(cacheContentLayerEnd))
+}
+
+// AppendStreamMarkedContentBegin begins the marked content of a structure
+// element, or of an artifact if mcid is negative
+func (c *ContentObj) AppendStreamMarkedContentBegin(tag string, mcid int) {
+	var cache cacheContentMarkedContentBegin
+	cache.tag = tag
+	cache.mcid = mcid
+	c.listCache.append(&cache)
+}
+
+// AppendStreamMarkedContentEnd ends the marked content of a structure
+// element or an artifact
+func (c *ContentObj) AppendStreamMarkedContentEnd() {
+	c.listCache.append(new(cacheContentMarkedContentEnd))
+}
+
+// AppendStreamGradient paints a shading clipped to a rectangle
+func (c *ContentObj) AppendStreamGradient(x, y, w, h float64, id string) {
+	var cache cacheContentGradientClip
+	cache.pageHeight = c.getRoot().GetBoundaryHeight(PageBoundaryMedia)
+	cache.x, cache.y = x, y
+	cache.w, cache.h = w, h
+	cache.id = id
+	c.listCache.append(&cache)
+}
+
+// AppendStreamExtGState append the use of a graphics state parameter dictionary
+func (c *ContentObj) AppendStreamExtGState(id string) {
+	var cache cacheContentExtGState
+	cache.id = id
+	c.listCache.append(&cache)
+}
+
 // AppendStreamUseTemplate append a use template
 func (c *ContentObj) AppendStreamUseTemplate(id string, x, y, h, sx, sy float64) {
 	var cache cacheContentUseTemplate
@@ -228,6 +288,8 @@ func (c *ContentObj) AppendStreamUseTemplate(id string, x, y, h, sx, sy float64)
 	cache.x, cache.y = x, y
 	cache.sx, cache.sy = sx, sy
 	cache.h = h
+	cache.pageIndex = c.getRoot().curr.IndexOfPageObj
+	cache.getRoot = c.getRoot
 	c.listCache.append(&cache)
 }
 
@@ -24,8 +24,12 @@ type Current struct {
 	//img
 	CountOfImg int
 
-	//text color
-	txtColor Rgb
+	//text color, nil when the text is painted with the fill color
+	txtColor Color
+
+	//fill and stroke color
+	fillColor   Color
+	strokeColor Color
 
 	//text grayscale
 	grayFill float64
@@ -36,16 +40,24 @@ type Current struct {
 	capStyle  int
 	joinStyle int
 
+	//transparency
+	fillAlpha   float64
+	strokeAlpha float64
+	blendMode   string
+
 	lheight    float64
 	unit       int
 	pageOption PageOption
+
+	//border and highlighting of links
+	linkStyle LinkStyle
 }
 
-func (c *Current) setTextColor(rgb Rgb) {
-	c.txtColor = rgb
+func (c *Current) setTextColor(color Color) {
+	c.txtColor = color
 }
 
-func (c *Current) textColor() Rgb {
+func (c *Current) textColor() Color {
 	return c.txtColor
 }
 
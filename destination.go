@@ -0,0 +1,189 @@
+package gofpdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// fit modes of the page shown at a destination
+const (
+	// FitPage fits the whole page in the window
+	FitPage = iota
+	// FitWidth fits the width of the page in the window, Y at the top
+	FitWidth
+	// FitHeight fits the height of the page in the window, X at the left
+	FitHeight
+	// FitActualSize shows the page at its actual size, X, Y at the upper left
+	FitActualSize
+	// FitRect fits the rectangle X, Y, W, H in the window
+	FitRect
+	// FitBox fits the content of the page in the window
+	FitBox
+	// FitBoxWidth fits the width of the content in the window, Y at the top
+	FitBoxWidth
+	// FitBoxHeight fits the height of the content in the window, X at the left
+	FitBoxHeight
+	// FitPosition shows X, Y at the upper left magnified by Zoom, the zoom
+	// of the viewer is kept if Zoom is 0
+	FitPosition
+)
+
+// Destination a view of a page, the target of links, bookmarks and the open
+// action. The page is either given by its number or by the name of a named
+// destination. The position and size are measured from the upper left
+// corner of the page in the units of the document.
+type Destination struct {
+	Page int    // the number of the page, starting at 1
+	Name string // the name of a named destination, used instead of the page
+	Fit  int    // one of the Fit constants
+
+	X, Y float64
+	W, H float64 // the size of the rectangle of FitRect
+	Zoom float64 // the magnification of FitPosition, 1 for the actual size
+}
+
+var fitNames = map[int]string{
+	FitPage:       "Fit",
+	FitWidth:      "FitH",
+	FitHeight:     "FitV",
+	FitActualSize: "XYZ",
+	FitRect:       "FitR",
+	FitBox:        "FitB",
+	FitBoxWidth:   "FitBH",
+	FitBoxHeight:  "FitBV",
+	FitPosition:   "XYZ",
+}
+
+// validate returns an error if the destination can not be shown
+func (d Destination) validate() error {
+	if d.Name == "" && d.Page < 1 {
+		return errors.New("a destination needs a page or a name")
+	}
+	if _, ok := fitNames[d.Fit]; !ok {
+		return fmt.Errorf("fit mode %d is not valid", d.Fit)
+	}
+	if d.Fit == FitRect && (d.W <= 0 || d.H <= 0) {
+		return errors.New("the rectangle of a destination needs a size")
+	}
+	if d.Zoom < 0 {
+		return fmt.Errorf("zoom %.2f is not valid", d.Zoom)
+	}
+	return nil
+}
+
+// toPoints returns the destination with its position and size in points
+func (d Destination) toPoints(gp *Fpdf) Destination {
+	gp.UnitsToPointsVar(&d.X, &d.Y, &d.W, &d.H)
+	return d
+}
+
+// view returns the fit mode and its operands for a page of height h
+func (d Destination) view(h float64) string {
+	top := h - d.Y
+	switch d.Fit {
+	case FitWidth, FitBoxWidth:
+		return fmt.Sprintf("/%s %.2f", fitNames[d.Fit], top)
+	case FitHeight, FitBoxHeight:
+		return fmt.Sprintf("/%s %.2f", fitNames[d.Fit], d.X)
+	case FitActualSize:
+		return fmt.Sprintf("/XYZ %.2f %.2f 1", d.X, top)
+	case FitRect:
+		return fmt.Sprintf("/FitR %.2f %.2f %.2f %.2f", d.X, top-d.H, d.X+d.W, top)
+	case FitPosition:
+		if d.Zoom == 0 {
+			return fmt.Sprintf("/XYZ %.2f %.2f null", d.X, top)
+		}
+		return fmt.Sprintf("/XYZ %.2f %.2f %.2f", d.X, top, d.Zoom)
+	}
+	return "/" + fitNames[d.Fit]
+}
+
+// destinationArray returns the destination in the document, the name of a
+// named destination is encrypted for the object objID
+func (gp *Fpdf) destinationArray(d Destination, objID int) (string, error) {
+	if d.Name != "" {
+		return encodeString([]byte(d.Name), gp.protection(), objID)
+	}
+
+	pages := gp.pdfObjs.typeMap[pageType]
+	if d.Page < 1 || d.Page > len(pages) {
+		return "", fmt.Errorf("destination page %d does not exist, the document has %d pages", d.Page, len(pages))
+	}
+	page := gp.pdfObjs.getPage(pages[d.Page-1])
+	var h float64
+	if pb := page.pageOption.GetBoundary(PageBoundaryMedia); pb != nil {
+		h = pb.Size.H
+	}
+	return fmt.Sprintf("[%d 0 R %s]", pages[d.Page-1]+1, d.view(h)), nil
+}
+
+// remoteDestinationArray returns the destination in another document, its
+// pages are assumed to be of the size of the pages of this document
+func (gp *Fpdf) remoteDestinationArray(d Destination, objID int) (string, error) {
+	if d.Name != "" {
+		return encodeString([]byte(d.Name), gp.protection(), objID)
+	}
+	return fmt.Sprintf("[%d %s]", d.Page-1, d.view(gp.GetBoundaryHeight(PageBoundaryMedia))), nil
+}
+
+// AddNamedDestination defines a destination that can be referred to by its
+// name, from links of the document with a Destination of that name and from
+// other documents. A destination of the same name is replaced.
+func (gp *Fpdf) AddNamedDestination(name string, dest Destination) error {
+	if name == "" {
+		return errors.New("a named destination needs a name")
+	}
+	if dest.Name != "" {
+		return errors.New("a named destination needs a page")
+	}
+	if err := dest.validate(); err != nil {
+		return err
+	}
+
+	if gp.destinations == nil {
+		gp.destinations = make(map[string]Destination)
+	}
+	gp.destinations[name] = dest.toPoints(gp)
+	return nil
+}
+
+// writeNames writes the name trees of the named destinations and of the
+// files attached to the document
+func (gp *Fpdf) writeNames(w io.Writer, objID int) error {
+	files := gp.documentFileSpecs()
+	if len(gp.destinations) == 0 && len(files) == 0 {
+		return nil
+	}
+
+	io.WriteString(w, "  /Names <<")
+	if len(gp.destinations) > 0 {
+		names := make([]string, 0, len(gp.destinations))
+		for name := range gp.destinations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		io.WriteString(w, " /Dests << /Names [")
+		for _, name := range names {
+			key, err := encodeString([]byte(name), gp.protection(), objID)
+			if err != nil {
+				return err
+			}
+			dest, err := gp.destinationArray(gp.destinations[name], objID)
+			if err != nil {
+				return fmt.Errorf("named destination %q: %s", name, err)
+			}
+			fmt.Fprintf(w, " %s %s", key, dest)
+		}
+		io.WriteString(w, " ] >>")
+	}
+	if len(files) > 0 {
+		if err := gp.writeEmbeddedFiles(w, files, objID); err != nil {
+			return err
+		}
+	}
+	io.WriteString(w, " >>\n")
+	return nil
+}
@@ -0,0 +1,156 @@
+package gofpdf
+
+import (
+	"testing"
+)
+
+func TestLinkDestinations(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 300), PdfOptionNoCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddPageLink(Destination{Page: 1}, 10, 10, 50, 20); err == nil {
+		t.Error("a link without a page should fail")
+	}
+	pdf.AddPage()
+	pdf.AddPage()
+	if err := pdf.SetPage(1); err != nil {
+		t.Fatal(err)
+	}
+
+	links := []struct {
+		add    func() error
+		expect func(annot pdfDict) bool
+	}{
+		{
+			func() error {
+				return pdf.AddPageLink(Destination{Page: 2, Fit: FitRect, X: 10, Y: 20, W: 100, H: 50}, 10, 10, 50, 20)
+			},
+			func(annot pdfDict) bool {
+				dest := annot["Dest"].(pdfArray)
+				return len(dest) == 6 && dest[1] == pdfName("FitR") && number(dest[3]) == 230 && number(dest[5]) == 280
+			},
+		},
+		{
+			func() error { return pdf.AddPageLink(Destination{Page: 2, Fit: FitBox}, 10, 40, 50, 20) },
+			func(annot pdfDict) bool {
+				dest := annot["Dest"].(pdfArray)
+				return len(dest) == 2 && dest[1] == pdfName("FitB")
+			},
+		},
+		{
+			func() error {
+				return pdf.AddPageLink(Destination{Page: 1, Fit: FitPosition, X: 5, Y: 100, Zoom: 2}, 10, 70, 50, 20)
+			},
+			func(annot pdfDict) bool {
+				dest := annot["Dest"].(pdfArray)
+				return dest[1] == pdfName("XYZ") && number(dest[2]) == 5 && number(dest[3]) == 200 && number(dest[4]) == 2
+			},
+		},
+		{
+			func() error { return pdf.AddPageLink(Destination{Name: "chapter2"}, 10, 100, 50, 20) },
+			func(annot pdfDict) bool { return annot["Dest"] == pdfString("chapter2") },
+		},
+		{
+			func() error {
+				return pdf.AddRemoteLink("manual.pdf", Destination{Page: 3, Fit: FitWidth, Y: 50}, 10, 130, 50, 20)
+			},
+			func(annot pdfDict) bool {
+				action := annot["A"].(pdfDict)
+				dest := action["D"].(pdfArray)
+				return action["S"] == pdfName("GoToR") && action["F"] == pdfString("manual.pdf") &&
+					number(dest[0]) == 2 && dest[1] == pdfName("FitH") && number(dest[2]) == 250
+			},
+		},
+		{
+			func() error { return pdf.AddLaunchLink("readme.txt", 10, 160, 50, 20) },
+			func(annot pdfDict) bool {
+				action := annot["A"].(pdfDict)
+				return action["S"] == pdfName("Launch") && action["F"] == pdfString("readme.txt")
+			},
+		},
+		{
+			func() error {
+				if err := pdf.SetLinkStyle(LinkStyle{BorderWidth: 1, BorderColor: RGB{R: 255}, Dash: []float64{3, 2}, Highlight: HighlightPush}); err != nil {
+					return err
+				}
+				pdf.AddExternalLink("https://example.com", 10, 190, 50, 20)
+				return pdf.SetLinkStyle(LinkStyle{})
+			},
+			func(annot pdfDict) bool {
+				bs := annot["BS"].(pdfDict)
+				return annot["H"] == pdfName("P") && bs["S"] == pdfName("D") && len(annot["C"].(pdfArray)) == 3 &&
+					annot["A"].(pdfDict)["URI"] == pdfString("https://example.com")
+			},
+		},
+	}
+	for _, l := range links {
+		if err := l.add(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pdf.AddNamedDestination("chapter2", Destination{Page: 2, Fit: FitHeight, X: 15}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dest := range []Destination{{}, {Page: 1, Fit: 42}, {Page: 1, Fit: FitRect}} {
+		if err := pdf.AddPageLink(dest, 0, 0, 10, 10); err == nil {
+			t.Errorf("destination %v should fail", dest)
+		}
+	}
+	if err := pdf.SetLinkStyle(LinkStyle{Highlight: 42}); err == nil {
+		t.Error("an unknown highlight should fail")
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots := reader.resolve(pages[0]["Annots"]).(pdfArray)
+	if len(annots) != len(links) {
+		t.Fatalf("expected %d links, found %d", len(links), len(annots))
+	}
+	for x, l := range links {
+		if annot := reader.resolve(annots[x]).(pdfDict); !l.expect(annot) {
+			t.Errorf("unexpected link %d %v", x, annot)
+		}
+	}
+
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	names := reader.resolve(catalog["Names"]).(pdfDict)
+	dests := reader.resolve(reader.resolve(names["Dests"]).(pdfDict)["Names"]).(pdfArray)
+	second := pdfRef{Num: pdf.pdfObjs.typeMap[pageType][1] + 1}
+	if len(dests) != 2 || dests[0] != pdfString("chapter2") {
+		t.Fatalf("unexpected named destinations %v", dests)
+	}
+	if dest := dests[1].(pdfArray); dest[0] != second || dest[1] != pdfName("FitV") || number(dest[2]) != 15 {
+		t.Errorf("unexpected named destination %v", dest)
+	}
+}
+
+func TestLinkMissingPage(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.AddPageLink(Destination{Page: 5}, 10, 10, 50, 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdf.GetBytesPdfReturnErr(); err == nil {
+		t.Error("a link to a missing page should fail")
+	}
+}
+
+func number(v interface{}) float64 {
+	n, _ := pdfNumber(v)
+	return n
+}
@@ -25,22 +25,17 @@ func (d *DeviceRGBObj) getType() string {
 
 //สร้าง ข้อมูลใน pdf
 func (d *DeviceRGBObj) write(w io.Writer, objID int) error {
+	data, err := encryptStream(d.protection(), objID, d.data)
+	if err != nil {
+		return err
+	}
 
 	io.WriteString(w, "<<\n")
-	fmt.Fprintf(w, "/Length %d\n", len(d.data))
+	fmt.Fprintf(w, "/Length %d\n", len(data))
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
-	if d.protection() != nil {
-		tmp, err := rc4Cip(d.protection().objectkey(objID), d.data)
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		io.WriteString(w, "\n")
-	} else {
-		w.Write(d.data)
-	}
-	io.WriteString(w, "endstream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
 
 	return nil
 }
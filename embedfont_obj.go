@@ -26,21 +26,16 @@ func (e *EmbedFontObj) write(w io.Writer, objID int) error {
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "<</Length %d\n", len(b))
+	data, err := encryptStream(e.protection(), objID, b)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "<</Length %d\n", len(data))
 	io.WriteString(w, "/Filter /FlateDecode\n")
 	fmt.Fprintf(w, "/Length1 %d\n", e.font.GetOriginalsize())
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
-	if e.protection() != nil {
-		tmp, err := rc4Cip(e.protection().objectkey(objID), b)
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		io.WriteString(w, "\n")
-	} else {
-		w.Write(b)
-	}
+	w.Write(data)
 	io.WriteString(w, "\nendstream\n")
 	return nil
 }
@@ -10,9 +10,13 @@ const encryptionType = "Encryption"
 
 //EncryptionObj  encryption object res
 type EncryptionObj struct {
-	uValue []byte //U entry in pdf document
-	oValue []byte //O entry in pdf document
-	pValue int    //P entry in pdf document
+	encryption int    //EncryptionRC4|EncryptionAES128|EncryptionAES256
+	uValue     []byte //U entry in pdf document
+	oValue     []byte //O entry in pdf document
+	pValue     int    //P entry in pdf document
+	ueValue    []byte //UE entry in pdf document
+	oeValue    []byte //OE entry in pdf document
+	permsValue []byte //Perms entry in pdf document
 }
 
 func (e *EncryptionObj) init(func() *Fpdf) {
@@ -26,8 +30,28 @@ func (e *EncryptionObj) getType() string {
 func (e *EncryptionObj) write(w io.Writer, objID int) error {
 	io.WriteString(w, "<<\n")
 	io.WriteString(w, "/Filter /Standard\n")
-	io.WriteString(w, "/V 1\n")
-	io.WriteString(w, "/R 2\n")
+	switch e.encryption {
+	case EncryptionAES128:
+		io.WriteString(w, "/V 4\n")
+		io.WriteString(w, "/R 4\n")
+		io.WriteString(w, "/Length 128\n")
+		io.WriteString(w, "/CF << /StdCF << /Type /CryptFilter /CFM /AESV2 /AuthEvent /DocOpen /Length 16 >> >>\n")
+		io.WriteString(w, "/StmF /StdCF\n")
+		io.WriteString(w, "/StrF /StdCF\n")
+	case EncryptionAES256:
+		io.WriteString(w, "/V 5\n")
+		io.WriteString(w, "/R 6\n")
+		io.WriteString(w, "/Length 256\n")
+		io.WriteString(w, "/CF << /StdCF << /Type /CryptFilter /CFM /AESV3 /AuthEvent /DocOpen /Length 32 >> >>\n")
+		io.WriteString(w, "/StmF /StdCF\n")
+		io.WriteString(w, "/StrF /StdCF\n")
+		fmt.Fprintf(w, "/OE (%s)\n", e.escape(e.oeValue))
+		fmt.Fprintf(w, "/UE (%s)\n", e.escape(e.ueValue))
+		fmt.Fprintf(w, "/Perms (%s)\n", e.escape(e.permsValue))
+	default:
+		io.WriteString(w, "/V 1\n")
+		io.WriteString(w, "/R 2\n")
+	}
 	fmt.Fprintf(w, "/O (%s)\n", e.escape(e.oValue))
 	fmt.Fprintf(w, "/U (%s)\n", e.escape(e.uValue))
 	fmt.Fprintf(w, "/P %d\n", e.pValue)
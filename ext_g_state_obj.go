@@ -0,0 +1,132 @@
+package gofpdf
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+
+	"github.com/ISeeMe/gofpdf/geh"
+)
+
+const extGStateType = "ExtGState"
+
+// blendModes are the blend modes supported by SetAlpha
+var blendModes = []string{
+	"Normal", "Multiply", "Screen", "Overlay", "Darken", "Lighten",
+	"ColorDodge", "ColorBurn", "HardLight", "SoftLight", "Difference",
+	"Exclusion", "Hue", "Saturation", "Color", "Luminosity",
+}
+
+// ExtGStateObj graphics state parameter dictionary holding the transparency
+// and blend mode set by SetAlpha
+type ExtGStateObj struct { //impl IObj, ProcsetIObj
+	fillAlpha   float64
+	strokeAlpha float64
+	blendMode   string
+}
+
+func newExtGStateObj(fillAlpha, strokeAlpha float64, blendMode string) *ExtGStateObj {
+	return &ExtGStateObj{
+		fillAlpha:   fillAlpha,
+		strokeAlpha: strokeAlpha,
+		blendMode:   blendMode,
+	}
+}
+
+func (s *ExtGStateObj) getType() string {
+	return extGStateType
+}
+
+func (s *ExtGStateObj) write(w io.Writer, objID int) error {
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Type /%s\n", s.getType())
+	fmt.Fprintf(w, "  /ca %.3f\n", s.fillAlpha)
+	fmt.Fprintf(w, "  /CA %.3f\n", s.strokeAlpha)
+	fmt.Fprintf(w, "  /BM /%s\n", s.blendMode)
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// procsetIdentifier is derived from the parameters, so equal states share
+// one object and keep their name when used through a template
+func (s *ExtGStateObj) procsetIdentifier() string {
+	key := fmt.Sprintf("%.3f %.3f %s", s.fillAlpha, s.strokeAlpha, s.blendMode)
+	return fmt.Sprintf("GS%x", sha1.Sum([]byte(key)))[:18]
+}
+
+func (s *ExtGStateObj) GobEncode() ([]byte, error) {
+	return geh.EncodeMany(s.fillAlpha, s.strokeAlpha, s.blendMode)
+}
+
+func (s *ExtGStateObj) GobDecode(buf []byte) error {
+	return geh.DecodeMany(buf, &s.fillAlpha, &s.strokeAlpha, &s.blendMode)
+}
+
+// SetAlpha sets the transparency of the fill and stroke operations and the
+// blend mode used to paint them. The alpha values range from 0.0 (fully
+// transparent) to 1.0 (fully opaque). blendMode must be one of "Normal",
+// "Multiply", "Screen", "Overlay", "Darken", "Lighten", "ColorDodge",
+// "ColorBurn", "HardLight", "SoftLight", "Difference", "Exclusion", "Hue",
+// "Saturation", "Color" or "Luminosity", an empty string selects "Normal".
+// Call SetAlpha(1, 1, "Normal") to return to normal rendering.
+func (gp *Fpdf) SetAlpha(fillAlpha, strokeAlpha float64, blendMode string) error {
+	if blendMode == "" {
+		blendMode = "Normal"
+	}
+
+	if fillAlpha < 0 || fillAlpha > 1 || strokeAlpha < 0 || strokeAlpha > 1 {
+		return fmt.Errorf("alpha values (0.0 - 1.0) are out of range: %.3f, %.3f", fillAlpha, strokeAlpha)
+	}
+
+	if !isBlendMode(blendMode) {
+		return fmt.Errorf("unrecognized blend mode %q", blendMode)
+	}
+
+	if fillAlpha == gp.curr.fillAlpha && strokeAlpha == gp.curr.strokeAlpha && blendMode == gp.curr.blendMode {
+		return nil
+	}
+
+	gp.curr.fillAlpha = fillAlpha
+	gp.curr.strokeAlpha = strokeAlpha
+	gp.curr.blendMode = blendMode
+	gp.resumeAlpha()
+	return nil
+}
+
+// resumeAlpha sets the transparency and blend mode of SetAlpha on the
+// current page, new pages start opaque and pages returned to by SetPage keep
+// the state they were left with
+func (gp *Fpdf) resumeAlpha() {
+	page := gp.currentPage()
+	if page == nil {
+		return
+	}
+
+	state := newExtGStateObj(gp.curr.fillAlpha, gp.curr.strokeAlpha, gp.curr.blendMode)
+	id := ""
+	if state.fillAlpha != 1 || state.strokeAlpha != 1 || state.blendMode != "Normal" {
+		id = state.procsetIdentifier()
+	}
+	if id == page.extGState {
+		return
+	}
+
+	name, _ := gp.addProcsetObj(state)
+	gp.currentContent().AppendStreamExtGState(name)
+	page.extGState = id
+}
+
+// GetAlpha returns the fill and stroke transparency and the blend mode set by
+// SetAlpha
+func (gp *Fpdf) GetAlpha() (fillAlpha, strokeAlpha float64, blendMode string) {
+	return gp.curr.fillAlpha, gp.curr.strokeAlpha, gp.curr.blendMode
+}
+
+func isBlendMode(mode string) bool {
+	for x := 0; x < len(blendModes); x++ {
+		if blendModes[x] == mode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetAlpha(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+
+	if err := pdf.SetAlpha(2, 1, ""); err == nil {
+		t.Error("an alpha value out of range should fail")
+	}
+	if err := pdf.SetAlpha(1, 1, "Unknown"); err == nil {
+		t.Error("an unknown blend mode should fail")
+	}
+
+	for x := 0; x < 3; x++ {
+		if err := pdf.SetAlpha(0.5, 0.25, "Multiply"); err != nil {
+			t.Error(err)
+			return
+		}
+		pdf.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+		if err := pdf.SetAlpha(1, 1, "Normal"); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if fill, stroke, mode := pdf.GetAlpha(); fill != 1 || stroke != 1 || mode != "Normal" {
+		t.Errorf("unexpected alpha %f %f %s", fill, stroke, mode)
+	}
+
+	if l := len(pdf.pdfObjs.allExtGStates()); l != 2 {
+		t.Errorf("expected 2 graphics states, found %d", l)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := newExtGStateObj(0.5, 0.25, "Multiply").procsetIdentifier()
+	for _, s := range []string{"/ExtGState <<", "/ca 0.500", "/CA 0.250", "/BM /Multiply", "/" + id + " gs"} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+}
+
+func TestSetAlphaInTemplate(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+
+	tpl, err := pdf.CreateTemplate(func(tp *Fpdf) error {
+		if err := tp.SetAlpha(0.3, 0.3, "Screen"); err != nil {
+			return err
+		}
+		tp.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := tpl.Serialize()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	tpl, err = DeserializeTemplate(b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if l := len(tpl.(templateResources).ExtGStates()); l != 1 {
+		t.Errorf("expected 1 graphics state in the template, found %d", l)
+		return
+	}
+
+	if err := pdf.UseTemplate(tpl); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err = pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := tpl.(templateResources).ExtGStates()[0].procsetIdentifier()
+	if _, ok := pdf.pdfObjs.hasProcsetID(id); !ok {
+		t.Error("the graphics state of the template should be registered")
+	}
+	if bytes.Count(b, []byte("/BM /Screen")) != 1 {
+		t.Error("the graphics state should be written once")
+	}
+}
+
+func TestSetAlphaOnPages(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+	if err := pdf.SetAlpha(0.5, 0.5, ""); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.AddPage()
+	if err := pdf.SetAlpha(0.5, 0.5, ""); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+	if err := pdf.SetAlpha(1, 1, ""); err != nil {
+		t.Error(err)
+		return
+	}
+	// page 1 was left transparent
+	if err := pdf.SetPage(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	transparent := "/" + newExtGStateObj(0.5, 0.5, "Normal").procsetIdentifier() + " gs"
+	opaque := "/" + newExtGStateObj(1, 1, "Normal").procsetIdentifier() + " gs"
+	for n, expected := range [][]string{{transparent, opaque}, {transparent, opaque}} {
+		page := pdf.getAllPages()[n]
+		content, err := page.getContent().bytes(page.indexOfContentObj)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, s := range expected {
+			if bytes.Count(content, []byte(s)) != 1 {
+				t.Errorf("%q should be written once on page %d: %q", s, n+1, content)
+			}
+		}
+	}
+}
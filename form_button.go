@@ -0,0 +1,314 @@
+package gofpdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// formats of submitted forms
+const (
+	// SubmitFDF submits the form in the Forms Data Format
+	SubmitFDF = iota
+	// SubmitHTML submits the form like an HTML form is posted
+	SubmitHTML
+	// SubmitXFDF submits the form in the XML Forms Data Format
+	SubmitXFDF
+	// SubmitPDF submits the whole document
+	SubmitPDF
+)
+
+// flags of the submit form action by format
+var submitFlags = map[int]int{
+	SubmitFDF:  0,
+	SubmitHTML: 1 << 2,
+	SubmitXFDF: 1 << 5,
+	SubmitPDF:  1 << 8,
+}
+
+// CheckboxOption options of a checkbox
+type CheckboxOption struct {
+	Checked     bool   // the checkbox is initially checked
+	ExportValue string // the value of the checked field, "Yes" if empty
+	Required    bool   // the field must be checked before the form is submitted
+	ReadOnly    bool   // the value can not be changed
+	Border      Color  // the color of the border, no border is drawn if nil
+	Background  Color  // the color of the background, transparent if nil
+}
+
+// PushButtonOption options of a push button. At most one of the actions
+// performed when the button is clicked can be set.
+type PushButtonOption struct {
+	Border       Color  // the color of the border, no border is drawn if nil
+	Background   Color  // the color of the background, transparent if nil
+	URI          string // opens the URI
+	JavaScript   string // runs the script
+	SubmitURL    string // submits the form to the URL
+	SubmitFormat int    // SubmitFDF|SubmitHTML|SubmitXFDF|SubmitPDF
+}
+
+// fieldAction the action of a push button
+type fieldAction struct {
+	uri         string
+	javaScript  string
+	submitURL   string
+	submitFlags int
+}
+
+// kind returns the type of the action as written in the document
+func (a *fieldAction) kind() string {
+	switch {
+	case a.uri != "":
+		return "URI"
+	case a.javaScript != "":
+		return "JavaScript"
+	case a.submitURL != "":
+		return "SubmitForm"
+	}
+	return ""
+}
+
+func (a *fieldAction) write(w io.Writer, protection *PDFProtection, objID int) error {
+	switch {
+	case a.uri != "":
+		uri, err := encodeString([]byte(a.uri), protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /A << /S /URI /URI %s >>\n", uri)
+	case a.javaScript != "":
+		js, err := encodeTextString(a.javaScript, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /A << /S /JavaScript /JS %s >>\n", js)
+	case a.submitURL != "":
+		url, err := encodeString([]byte(a.submitURL), protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /A << /S /SubmitForm /F << /FS /URL /F %s >> /Flags %d >>\n", url, a.submitFlags)
+	}
+	return nil
+}
+
+// AddCheckbox adds a checkbox to the current page. x, y is the upper left
+// corner of the checkbox, w and h its size. The check mark is drawn in the
+// current text color. name identifies the field when the form is submitted
+// and must be unique within the document.
+func (gp *Fpdf) AddCheckbox(name string, x, y, w, h float64, opt CheckboxOption) error {
+	field, err := gp.newFormField(name, fieldTypeButton, x, y, w, h, FormFieldOption{
+		Required:   opt.Required,
+		ReadOnly:   opt.ReadOnly,
+		Border:     opt.Border,
+		Background: opt.Background,
+	})
+	if err != nil {
+		return err
+	}
+
+	field.font = nil
+	field.onState = opt.ExportValue
+	if field.onState == "" {
+		field.onState = "Yes"
+	}
+	field.value = "Off"
+	if opt.Checked {
+		field.value = field.onState
+	}
+
+	gp.addToggleAppearances(field, false)
+	gp.addWidget(field)
+	return nil
+}
+
+// AddRadioGroup adds a group of mutually exclusive radio buttons, the buttons
+// are added with AddRadioButton. The value of the options selects the
+// initially selected button, none is selected if it is empty. The border and
+// background colors apply to all buttons of the group.
+func (gp *Fpdf) AddRadioGroup(name string, opt FormFieldOption) error {
+	if err := gp.checkFieldName(name); err != nil {
+		return err
+	}
+
+	group := &FormFieldObj{
+		name:          name,
+		fieldType:     fieldTypeButton,
+		flags:         fieldFlagRadio | fieldFlagNoToggleOff,
+		value:         opt.Value,
+		kids:          make([]*FormFieldObj, 0),
+		page:          -1,
+		offAppearance: -1,
+	}
+	group.init(func() *Fpdf {
+		return gp
+	})
+
+	var err error
+	if opt.Border != nil {
+		if group.border, err = gp.resolveColor(opt.Border); err != nil {
+			return err
+		}
+	}
+	if opt.Background != nil {
+		if group.background, err = gp.resolveColor(opt.Background); err != nil {
+			return err
+		}
+	}
+	if opt.ReadOnly {
+		group.flags |= fieldFlagReadOnly
+	}
+	if opt.Required {
+		group.flags |= fieldFlagRequired
+	}
+
+	group.index = gp.addObj(group)
+	return nil
+}
+
+// AddRadioButton adds a button of the radio group to the current page, see
+// AddCheckbox for the position. value is the value of the group when the
+// button is selected and must be unique within the group. Buttons of a group
+// can be placed on different pages.
+func (gp *Fpdf) AddRadioButton(group, value string, x, y, w, h float64) error {
+	parent := gp.formField(group)
+	if parent == nil || parent.kids == nil {
+		return fmt.Errorf("radio group %q does not exist", group)
+	}
+	if value == "" {
+		return errors.New("a radio button needs a value")
+	}
+	for _, kid := range parent.kids {
+		if kid.onState == value {
+			return fmt.Errorf("radio group %q already has a button %q", group, value)
+		}
+	}
+
+	button, err := gp.newWidget(x, y, w, h, parent.border, parent.background)
+	if err != nil {
+		return err
+	}
+	button.font = nil
+	button.fieldType = fieldTypeButton
+	button.parent = parent
+	button.onState = value
+
+	gp.addToggleAppearances(button, true)
+	parent.kids = append(parent.kids, button)
+	gp.addWidget(button)
+	return nil
+}
+
+// AddPushButton adds a button to the current page, see AddCheckbox for the
+// position. The caption is centered in the current font, font size and text
+// color. name identifies the field and must be unique within the document.
+func (gp *Fpdf) AddPushButton(name, caption string, x, y, w, h float64, opt PushButtonOption) error {
+	actions := 0
+	for _, action := range []string{opt.URI, opt.JavaScript, opt.SubmitURL} {
+		if action != "" {
+			actions++
+		}
+	}
+	if actions > 1 {
+		return errors.New("a push button can only have one action")
+	}
+	flags, ok := submitFlags[opt.SubmitFormat]
+	if !ok {
+		return fmt.Errorf("unknown submit format %d", opt.SubmitFormat)
+	}
+	if caption != "" && gp.curr.Font_ISubset == nil {
+		return errors.New("the caption of a push button needs a font")
+	}
+
+	field, err := gp.newFormField(name, fieldTypeButton, x, y, w, h, FormFieldOption{
+		Border:     opt.Border,
+		Background: opt.Background,
+	})
+	if err != nil {
+		return err
+	}
+	field.flags |= fieldFlagPushButton
+	field.caption = caption
+	field.align = Center
+	if actions > 0 {
+		field.action = &fieldAction{
+			uri:         opt.URI,
+			javaScript:  opt.JavaScript,
+			submitURL:   opt.SubmitURL,
+			submitFlags: flags,
+		}
+	}
+
+	var buff bytes.Buffer
+	field.writeBox(&buff, false)
+	if caption == "" {
+		field.font = nil
+	} else {
+		if err := field.font.AddChars(caption); err != nil {
+			return err
+		}
+		line, err := gp.singleLine(field, caption)
+		if err != nil {
+			return err
+		}
+		if err := field.writeLines(&buff, []fieldLine{line}); err != nil {
+			return err
+		}
+	}
+
+	field.appearance = gp.addAppearance(field, buff.Bytes())
+	gp.addWidget(field)
+	return nil
+}
+
+// addToggleAppearances adds the appearances of the selected and the not
+// selected state of a checkbox or a radio button
+func (gp *Fpdf) addToggleAppearances(field *FormFieldObj, round bool) {
+	w, h := field.rect[2]-field.rect[0], field.rect[3]-field.rect[1]
+
+	var off bytes.Buffer
+	field.writeBox(&off, round)
+
+	var on bytes.Buffer
+	field.writeBox(&on, round)
+	if round {
+		fmt.Fprintf(&on, "%s\n%sf\n", colorOperator(field.color, false), circlePath(w/2, h/2, math.Min(w, h)/4))
+	} else {
+		fmt.Fprintf(&on, "%s\n%.2f w 1 J 1 j\n%.2f %.2f m %.2f %.2f l %.2f %.2f l S\n",
+			colorOperator(field.color, true), math.Min(w, h)/10, w*0.2, h*0.5, w*0.42, h*0.25, w*0.8, h*0.78)
+	}
+
+	field.appearance = gp.addAppearance(field, on.Bytes())
+	field.offAppearance = gp.addAppearance(field, off.Bytes())
+}
+
+// checkFieldName returns an error if the name can not be used for a new field
+func (gp *Fpdf) checkFieldName(name string) error {
+	if name == "" {
+		return errors.New("a form field needs a name")
+	}
+	if gp.formField(name) != nil {
+		return fmt.Errorf("form field %q already exists", name)
+	}
+	return nil
+}
+
+// circlePath returns the path of a circle approximated by four Bézier curves
+func circlePath(cx, cy, r float64) string {
+	return ellipsePath(cx, cy, r, r)
+}
+
+// ellipsePath returns the path of an ellipse with the radii rx and ry
+// approximated by four Bézier curves
+func ellipsePath(cx, cy, rx, ry float64) string {
+	kx, ky := rx*0.5523, ry*0.5523
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%.2f %.2f m\n", cx+rx, cy)
+	fmt.Fprintf(&buff, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx+rx, cy+ky, cx+kx, cy+ry, cx, cy+ry)
+	fmt.Fprintf(&buff, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx-kx, cy+ry, cx-rx, cy+ky, cx-rx, cy)
+	fmt.Fprintf(&buff, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx-rx, cy-ky, cx-kx, cy-ry, cx, cy-ry)
+	fmt.Fprintf(&buff, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx+kx, cy-ry, cx+rx, cy-ky, cx+rx, cy)
+	return buff.String()
+}
@@ -0,0 +1,141 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFormButtons(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+
+	// checkboxes and radio buttons do not need a font
+	if err := pdf.AddCheckbox("terms", 10, 10, 12, 12, CheckboxOption{Checked: true, Required: true, Border: Gray(0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddCheckbox("newsletter", 10, 30, 12, 12, CheckboxOption{ExportValue: "Subscribe"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddPushButton("submit", "Submit", 10, 50, 60, 20, PushButtonOption{}); err == nil {
+		t.Error("a caption without a font should fail")
+	}
+
+	if err := pdf.AddRadioButton("plan", "basic", 10, 80, 12, 12); err == nil {
+		t.Error("a button of an unknown group should fail")
+	}
+	if err := pdf.AddRadioGroup("plan", FormFieldOption{Value: "premium", Border: Gray(0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddRadioButton("plan", "basic", 10, 80, 12, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddRadioButton("plan", "basic", 30, 80, 12, 12); err == nil {
+		t.Error("a duplicate button value should fail")
+	}
+	pdf.AddPage()
+	if err := pdf.AddRadioButton("plan", "premium", 10, 80, 12, 12); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddPushButton("both", "", 10, 50, 60, 20, PushButtonOption{URI: "https://example.com", JavaScript: "app.alert(1)"}); err == nil {
+		t.Error("a push button with two actions should fail")
+	}
+	buttons := []PushButtonOption{
+		{URI: "https://example.com/help"},
+		{JavaScript: "this.resetForm();"},
+		{SubmitURL: "https://example.com/submit", SubmitFormat: SubmitHTML, Background: Gray(0.8)},
+	}
+	for x, opt := range buttons {
+		if err := pdf.AddPushButton([]string{"help", "reset", "submit"}[x], "Click", 10, 120+float64(x)*30, 60, 20, opt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pdf.SetNoCompression()
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	form := reader.resolve(catalog["AcroForm"]).(pdfDict)
+	refs := reader.resolve(form["Fields"]).(pdfArray)
+	if len(refs) != 6 {
+		t.Fatalf("expected 6 fields, found %d", len(refs))
+	}
+
+	fields := make(map[string]pdfDict)
+	for _, ref := range refs {
+		field := reader.resolve(ref).(pdfDict)
+		fields[string(textString(field["T"]))] = field
+	}
+
+	terms := fields["terms"]
+	if terms["V"] != pdfName("Yes") || terms["AS"] != pdfName("Yes") || terms["Ff"] != fieldFlagRequired {
+		t.Errorf("unexpected checkbox %v", terms)
+	}
+	newsletter := fields["newsletter"]
+	states := reader.resolve(reader.resolve(newsletter["AP"]).(pdfDict)["N"]).(pdfDict)
+	if newsletter["AS"] != pdfName("Off") || states["Subscribe"] == nil || states["Off"] == nil {
+		t.Errorf("unexpected checkbox %v", newsletter)
+	}
+
+	// the radio group is a field without a widget, its buttons are widgets
+	// on two pages
+	plan := fields["plan"]
+	if _, ok := plan["Rect"]; ok || plan["Ff"] != fieldFlagRadio|fieldFlagNoToggleOff || plan["V"] != pdfName("premium") {
+		t.Errorf("unexpected radio group %v", plan)
+	}
+	kids := reader.resolve(plan["Kids"]).(pdfArray)
+	if len(kids) != 2 {
+		t.Fatalf("expected 2 radio buttons, found %d", len(kids))
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x, kid := range kids {
+		button := reader.resolve(kid).(pdfDict)
+		parent, _ := reader.resolve(button["Parent"]).(pdfDict)
+		if string(textString(parent["T"])) != "plan" {
+			t.Error("the radio button should refer to its group")
+		}
+		if state := []pdfName{"Off", "premium"}[x]; button["AS"] != state {
+			t.Errorf("expected state %s of radio button %d, found %v", state, x, button["AS"])
+		}
+		annots := reader.resolve(pages[x]["Annots"]).(pdfArray)
+		found := false
+		for _, annot := range annots {
+			found = found || annot == kid
+		}
+		if !found {
+			t.Errorf("radio button %d should be an annotation of page %d", x, x+1)
+		}
+	}
+
+	for _, s := range []string{
+		"/A << /S /URI /URI ",
+		"/A << /S /JavaScript /JS ",
+		"/A << /S /SubmitForm /F << /FS /URL /F ",
+		"/Flags 4 >>",
+		"/Ff 65536",
+		"/MK << /BG [0.800] /CA ",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+}
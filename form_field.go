@@ -0,0 +1,845 @@
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ISeeMe/gofpdf/bp"
+)
+
+const formFieldType = "FormField"
+const appearanceType = "Appearance"
+
+// types of form fields
+const (
+	fieldTypeText      = "Tx"
+	fieldTypeChoice    = "Ch"
+	fieldTypeButton    = "Btn"
+	fieldTypeSignature = "Sig"
+)
+
+// flags of form fields
+const (
+	fieldFlagReadOnly    = 1 << 0
+	fieldFlagRequired    = 1 << 1
+	fieldFlagMultiline   = 1 << 12
+	fieldFlagPassword    = 1 << 13
+	fieldFlagNoToggleOff = 1 << 14
+	fieldFlagRadio       = 1 << 15
+	fieldFlagPushButton  = 1 << 16
+	fieldFlagCombo       = 1 << 17
+	fieldFlagEdit        = 1 << 18
+	fieldFlagDoNotScroll = 1 << 23
+	fieldFlagComb        = 1 << 24
+)
+
+// padding between the border of a field and its text
+const fieldPadding = 2
+
+// FormFieldOption options shared by all form fields
+type FormFieldOption struct {
+	Value      string // the initial value, also restored when the form is reset
+	Required   bool   // the field must have a value before the form is submitted
+	ReadOnly   bool   // the value can not be changed
+	Border     Color  // the color of the border, no border is drawn if nil
+	Background Color  // the color of the background, transparent if nil
+}
+
+// TextFieldOption options of a text field
+type TextFieldOption struct {
+	FormFieldOption
+	Align     int  // Left|Center|Right, Left if 0
+	Multiline bool // the text may span multiple lines
+	Password  bool // the text is displayed as asterisks and never stored
+	MaxLen    int  // the maximum number of characters, unlimited if 0
+	Comb      bool // the field is divided into MaxLen positions, one per character
+}
+
+// ChoiceFieldOption options of a combo box or a list box
+type ChoiceFieldOption struct {
+	FormFieldOption
+	Options  []string // the items to choose from, Value must be one of them
+	Editable bool     // a combo box also accepts values that are not one of the options
+}
+
+// FormFieldObj a field of an interactive form, combined with the widget
+// annotation showing it on a page. The field of a radio group has no widget,
+// its buttons are widgets without a field of their own.
+type FormFieldObj struct { //impl IObj
+	name       string
+	fieldType  string
+	flags      int
+	rect       [4]float64 // lower left and upper right corners
+	value      string
+	options    []string
+	maxLen     int
+	align      int
+	font       *SubsetFontObj
+	fontSize   float64
+	color      Color
+	border     Color
+	background Color
+	caption    string
+	action     *fieldAction
+	page       int
+	index      int
+	parent     *FormFieldObj
+	kids       []*FormFieldObj
+	getRoot    func() *Fpdf
+
+	appearance int
+	// onState is the name of the appearance of a selected checkbox or radio
+	// button, offAppearance the index of its appearance when not selected
+	onState       string
+	offAppearance int
+}
+
+func (f *FormFieldObj) init(funcGetRoot func() *Fpdf) {
+	f.getRoot = funcGetRoot
+}
+
+func (f *FormFieldObj) getType() string {
+	return formFieldType
+}
+
+func (f *FormFieldObj) write(w io.Writer, objID int) error {
+	protection := f.getRoot().protection()
+	name, err := encodeTextString(f.name, protection, objID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	if f.kids == nil {
+		io.WriteString(w, "  /Type /Annot\n")
+		io.WriteString(w, "  /Subtype /Widget\n")
+		io.WriteString(w, "  /F 4\n")
+		fmt.Fprintf(w, "  /P %d 0 R\n", f.page+1)
+		fmt.Fprintf(w, "  /Rect [%.2f %.2f %.2f %.2f]\n", f.rect[0], f.rect[1], f.rect[2], f.rect[3])
+	}
+	if f.parent != nil {
+		fmt.Fprintf(w, "  /Parent %d 0 R\n", f.parent.index+1)
+	} else {
+		fmt.Fprintf(w, "  /FT /%s\n", f.fieldType)
+		fmt.Fprintf(w, "  /T %s\n", name)
+		if f.flags != 0 {
+			fmt.Fprintf(w, "  /Ff %d\n", f.flags)
+		}
+	}
+	if f.kids != nil {
+		io.WriteString(w, "  /Kids [")
+		for x, kid := range f.kids {
+			if x > 0 {
+				io.WriteString(w, " ")
+			}
+			fmt.Fprintf(w, "%d 0 R", kid.index+1)
+		}
+		io.WriteString(w, "]\n")
+	}
+	if f.font != nil {
+		da, err := encodeString([]byte(f.defaultAppearance()), protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /DA %s\n", da)
+	}
+	switch f.align {
+	case Center:
+		io.WriteString(w, "  /Q 1\n")
+	case Right:
+		io.WriteString(w, "  /Q 2\n")
+	}
+	if f.maxLen > 0 {
+		fmt.Fprintf(w, "  /MaxLen %d\n", f.maxLen)
+	}
+	if len(f.options) > 0 {
+		io.WriteString(w, "  /Opt [")
+		for x, option := range f.options {
+			s, err := encodeTextString(option, protection, objID)
+			if err != nil {
+				return err
+			}
+			if x > 0 {
+				io.WriteString(w, " ")
+			}
+			io.WriteString(w, s)
+		}
+		io.WriteString(w, "]\n")
+	}
+	if f.fieldType == fieldTypeSignature {
+		if index := f.getRoot().pdfObjs.indexOfFirst(signatureType); index >= 0 {
+			fmt.Fprintf(w, "  /V %d 0 R\n", index+1)
+		}
+	} else if f.fieldType == fieldTypeButton {
+		if f.value != "" {
+			fmt.Fprintf(w, "  /V /%s\n", escapeName(f.value))
+			fmt.Fprintf(w, "  /DV /%s\n", escapeName(f.value))
+		}
+	} else if f.value != "" {
+		value, err := encodeTextString(f.value, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /V %s\n", value)
+		fmt.Fprintf(w, "  /DV %s\n", value)
+	}
+	if f.kids != nil {
+		io.WriteString(w, ">>\n")
+		return nil
+	}
+
+	mk, err := f.appearanceCharacteristics(objID)
+	if err != nil {
+		return err
+	}
+	if mk != "" {
+		fmt.Fprintf(w, "  /MK << %s>>\n", mk)
+	}
+	if f.onState != "" {
+		state := "Off"
+		if f.selected() {
+			state = f.onState
+		}
+		fmt.Fprintf(w, "  /AP << /N << /%s %d 0 R /Off %d 0 R >> >>\n", escapeName(f.onState), f.appearance+1, f.offAppearance+1)
+		fmt.Fprintf(w, "  /AS /%s\n", escapeName(state))
+	} else {
+		fmt.Fprintf(w, "  /AP << /N %d 0 R >>\n", f.appearance+1)
+	}
+	if f.action != nil {
+		if err := f.action.write(w, protection, objID); err != nil {
+			return err
+		}
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// selected returns true if the checkbox or radio button is selected
+func (f *FormFieldObj) selected() bool {
+	if f.parent != nil {
+		return f.parent.value == f.onState
+	}
+	return f.value == f.onState
+}
+
+// defaultAppearance returns the font and color used by the reader to draw
+// the text entered into the field
+func (f *FormFieldObj) defaultAppearance() string {
+	return fmt.Sprintf("/%s %.2f Tf %s", f.font.procsetIdentifier(), f.fontSize, colorOperator(f.color, false))
+}
+
+// appearanceCharacteristics returns the entries of the border and background
+// colors and the caption used by the reader to draw the field
+func (f *FormFieldObj) appearanceCharacteristics(objID int) (string, error) {
+	var mk string
+	if c := colorComponents(f.border); c != "" {
+		mk += fmt.Sprintf("/BC [%s] ", c)
+	}
+	if c := colorComponents(f.background); c != "" {
+		mk += fmt.Sprintf("/BG [%s] ", c)
+	}
+	if f.caption != "" {
+		caption, err := encodeTextString(f.caption, f.getRoot().protection(), objID)
+		if err != nil {
+			return "", err
+		}
+		mk += fmt.Sprintf("/CA %s ", caption)
+	}
+	return mk, nil
+}
+
+// AppearanceObj a form XObject drawing a form field
+type AppearanceObj struct { //impl IObj
+	w, h     float64
+	content  []byte
+	fonts    []*SubsetFontObj
+	spots    []string // identifiers of the spot color spaces used
+	states   []string // identifiers of the graphics states used
+	xobjects []string // identifiers of the templates used
+	getRoot  func() *Fpdf
+}
+
+func (a *AppearanceObj) init(funcGetRoot func() *Fpdf) {
+	a.getRoot = funcGetRoot
+}
+
+func (a *AppearanceObj) getType() string {
+	return appearanceType
+}
+
+func (a *AppearanceObj) write(w io.Writer, objID int) error {
+	gp := a.getRoot()
+
+	buff := bp.GetBuffer()
+	defer bp.PutBuffer(buff)
+
+	isFlate := gp.compressLevel != zlib.NoCompression
+	if isFlate {
+		ww, err := zlib.NewWriterLevel(buff, gp.compressLevel)
+		if err != nil {
+			return err
+		}
+		ww.Write(a.content)
+		ww.Close()
+	} else {
+		buff.Write(a.content)
+	}
+
+	data, err := encryptStream(gp.protection(), objID, buff.Bytes())
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /XObject\n")
+	io.WriteString(w, "  /Subtype /Form\n")
+	fmt.Fprintf(w, "  /BBox [0 0 %.2f %.2f]\n", a.w, a.h)
+	io.WriteString(w, "  /Resources <<")
+	if len(a.fonts) > 0 {
+		io.WriteString(w, " /Font <<")
+		for _, font := range a.fonts {
+			id := font.procsetIdentifier()
+			index, _ := gp.pdfObjs.hasProcsetID(id)
+			fmt.Fprintf(w, " /%s %d 0 R", id, index+1)
+		}
+		io.WriteString(w, " >>")
+	}
+	if len(a.spots) > 0 {
+		io.WriteString(w, " /ColorSpace <<")
+		for _, id := range a.spots {
+			index, _ := gp.pdfObjs.hasProcsetID(id)
+			fmt.Fprintf(w, " /%s %d 0 R", id, index+1)
+		}
+		io.WriteString(w, " >>")
+	}
+	if len(a.states) > 0 {
+		io.WriteString(w, " /ExtGState <<")
+		for _, id := range a.states {
+			index, _ := gp.pdfObjs.hasProcsetID(id)
+			fmt.Fprintf(w, " /%s %d 0 R", id, index+1)
+		}
+		io.WriteString(w, " >>")
+	}
+	if len(a.xobjects) > 0 {
+		io.WriteString(w, " /XObject <<")
+		for _, id := range a.xobjects {
+			index, _ := gp.pdfObjs.hasProcsetID(id)
+			fmt.Fprintf(w, " /%s %d 0 R", id, index+1)
+		}
+		io.WriteString(w, " >>")
+	}
+	io.WriteString(w, " >>\n")
+	if isFlate {
+		io.WriteString(w, "  /Filter /FlateDecode\n")
+	}
+	fmt.Fprintf(w, "  /Length %d\n", len(data))
+	io.WriteString(w, ">>\n")
+	io.WriteString(w, "stream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
+	return nil
+}
+
+// fieldLine a line of text in the appearance of a field, the position is
+// relative to the lower left corner of the field
+type fieldLine struct {
+	x, y float64
+	text string
+}
+
+// AddTextField adds a text field to the current page. x, y is the upper left
+// corner of the field, w and h its size. The text is set in the current font,
+// font size and text color. name identifies the field when the form is
+// submitted and must be unique within the document. The font is embedded as a
+// subset of the characters of the value and the options, readers may replace
+// it when other text is typed into the field.
+func (gp *Fpdf) AddTextField(name string, x, y, w, h float64, opt TextFieldOption) error {
+	if opt.Comb && (opt.MaxLen <= 0 || opt.Multiline || opt.Password) {
+		return errors.New("a comb field needs a maximum length and can not be multiline or a password")
+	}
+	if opt.Password && opt.Value != "" {
+		return errors.New("the value of a password field is never stored")
+	}
+	if opt.MaxLen > 0 && len([]rune(opt.Value)) > opt.MaxLen {
+		return fmt.Errorf("the value is longer than %d characters", opt.MaxLen)
+	}
+
+	field, err := gp.newFormField(name, fieldTypeText, x, y, w, h, opt.FormFieldOption)
+	if err != nil {
+		return err
+	}
+	field.maxLen = opt.MaxLen
+	field.align = opt.Align
+	if opt.Multiline {
+		field.flags |= fieldFlagMultiline
+	}
+	if opt.Password {
+		field.flags |= fieldFlagPassword
+	}
+	if opt.Comb {
+		field.flags |= fieldFlagComb | fieldFlagDoNotScroll
+	}
+
+	var lines []fieldLine
+	switch {
+	case opt.Comb:
+		lines, err = gp.combLines(field)
+	case opt.Multiline:
+		lines, err = gp.multilineLines(field)
+	default:
+		var line fieldLine
+		line, err = gp.singleLine(field, field.value)
+		lines = []fieldLine{line}
+	}
+	if err != nil {
+		return err
+	}
+
+	return gp.addFormField(field, lines, nil)
+}
+
+// AddComboBox adds a drop-down list to the current page, see AddTextField for
+// the position and the text. The box shows the value, which must be one of the
+// options unless the box is editable.
+func (gp *Fpdf) AddComboBox(name string, x, y, w, h float64, opt ChoiceFieldOption) error {
+	field, err := gp.newChoiceField(name, x, y, w, h, opt)
+	if err != nil {
+		return err
+	}
+	field.flags |= fieldFlagCombo
+	if opt.Editable {
+		field.flags |= fieldFlagEdit
+	}
+
+	line, err := gp.singleLine(field, field.value)
+	if err != nil {
+		return err
+	}
+	return gp.addFormField(field, []fieldLine{line}, nil)
+}
+
+// AddListBox adds a scrollable list to the current page, see AddTextField for
+// the position and the text. The options are listed from the top, the value
+// is highlighted and must be one of them.
+func (gp *Fpdf) AddListBox(name string, x, y, w, h float64, opt ChoiceFieldOption) error {
+	if opt.Editable {
+		return errors.New("a list box can not be editable")
+	}
+
+	field, err := gp.newChoiceField(name, x, y, w, h, opt)
+	if err != nil {
+		return err
+	}
+
+	ascent, descent := fieldFontMetrics(field)
+	top := field.rect[3] - field.rect[1] - fieldPadding
+	var highlight []float64
+	lines := make([]fieldLine, 0, len(field.options))
+	for _, option := range field.options {
+		line, err := gp.alignedLine(field, option, top-ascent)
+		if err != nil {
+			return err
+		}
+		if option == field.value {
+			highlight = []float64{fieldPadding / 2, line.y + descent, field.rect[2] - field.rect[0] - fieldPadding, ascent - descent}
+		}
+		lines = append(lines, line)
+		top -= ascent - descent
+	}
+
+	return gp.addFormField(field, lines, highlight)
+}
+
+func (gp *Fpdf) newChoiceField(name string, x, y, w, h float64, opt ChoiceFieldOption) (*FormFieldObj, error) {
+	if len(opt.Options) == 0 {
+		return nil, errors.New("a choice field needs options")
+	}
+	if opt.Value != "" && !opt.Editable {
+		found := false
+		for _, option := range opt.Options {
+			found = found || option == opt.Value
+		}
+		if !found {
+			return nil, fmt.Errorf("value %q is not one of the options", opt.Value)
+		}
+	}
+
+	field, err := gp.newFormField(name, fieldTypeChoice, x, y, w, h, opt.FormFieldOption)
+	if err != nil {
+		return nil, err
+	}
+	field.options = opt.Options
+	if err := field.font.AddChars(strings.Join(opt.Options, "")); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// newFormField creates a field on the current page, text and choice fields
+// use the current font
+func (gp *Fpdf) newFormField(name, fieldType string, x, y, w, h float64, opt FormFieldOption) (*FormFieldObj, error) {
+	if err := gp.checkFieldName(name); err != nil {
+		return nil, err
+	}
+	if (fieldType == fieldTypeText || fieldType == fieldTypeChoice) && gp.curr.Font_ISubset == nil {
+		return nil, errors.New("a form field needs a font")
+	}
+
+	field, err := gp.newWidget(x, y, w, h, opt.Border, opt.Background)
+	if err != nil {
+		return nil, err
+	}
+	field.name = name
+	field.fieldType = fieldType
+	field.value = opt.Value
+	if opt.ReadOnly {
+		field.flags |= fieldFlagReadOnly
+	}
+	if opt.Required {
+		field.flags |= fieldFlagRequired
+	}
+
+	if field.font != nil {
+		if err := field.font.AddChars(field.value); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+// newWidget creates the widget of a field on the current page, the text is
+// drawn in the current font, font size and text color
+func (gp *Fpdf) newWidget(x, y, w, h float64, border, background Color) (*FormFieldObj, error) {
+	if gp.currentPage() == nil {
+		return nil, errors.New("a form field needs a page")
+	}
+
+	gp.UnitsToPointsVar(&x, &y, &w, &h)
+	pageHeight := gp.GetBoundaryHeight(PageBoundaryMedia)
+
+	field := &FormFieldObj{
+		rect:          [4]float64{x, pageHeight - y - h, x + w, pageHeight - y},
+		font:          gp.curr.Font_ISubset,
+		fontSize:      gp.curr.Font_Size,
+		color:         gp.TextColor(),
+		page:          gp.curr.IndexOfPageObj,
+		offAppearance: -1,
+	}
+	field.init(func() *Fpdf {
+		return gp
+	})
+
+	var err error
+	if border != nil {
+		if field.border, err = gp.resolveColor(border); err != nil {
+			return nil, err
+		}
+	}
+	if background != nil {
+		if field.background, err = gp.resolveColor(background); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+// addFormField adds the field to the current page. The lines are drawn in the
+// font of the field above the highlighted rectangle x, y, w, h.
+func (gp *Fpdf) addFormField(field *FormFieldObj, lines []fieldLine, highlight []float64) error {
+	var buff bytes.Buffer
+	field.writeBox(&buff, false)
+	if highlight != nil {
+		fmt.Fprintf(&buff, "0.60 0.76 0.85 rg\n%.2f %.2f %.2f %.2f re f\n", highlight[0], highlight[1], highlight[2], highlight[3])
+	}
+
+	io.WriteString(&buff, "/Tx BMC\n")
+	if err := field.writeLines(&buff, lines); err != nil {
+		return err
+	}
+	io.WriteString(&buff, "EMC\n")
+
+	field.appearance = gp.addAppearance(field, buff.Bytes())
+	gp.addWidget(field)
+	return nil
+}
+
+// addAppearance adds a form XObject drawing the widget of the field with the
+// content, xobjects are the identifiers of the templates the content uses
+func (gp *Fpdf) addAppearance(field *FormFieldObj, content []byte, xobjects ...string) int {
+	appearance := &AppearanceObj{
+		w:        field.rect[2] - field.rect[0],
+		h:        field.rect[3] - field.rect[1],
+		content:  content,
+		spots:    spotIdentifiers(field.color, field.border, field.background),
+		xobjects: xobjects,
+	}
+	if field.font != nil {
+		appearance.fonts = []*SubsetFontObj{field.font}
+	}
+	appearance.init(func() *Fpdf {
+		return gp
+	})
+	return gp.addObj(appearance)
+}
+
+// addWidget adds the field to the document and its widget to the annotations
+// of the current page
+func (gp *Fpdf) addWidget(field *FormFieldObj) {
+	field.index = gp.addObj(field)
+	page := gp.currentPage()
+	page.fields = append(page.fields, field.index)
+}
+
+// writeBox draws the background and the border of the widget, round widgets
+// are drawn as the largest circle fitting the widget
+func (f *FormFieldObj) writeBox(w io.Writer, round bool) {
+	width, height := f.rect[2]-f.rect[0], f.rect[3]-f.rect[1]
+	if round {
+		r := math.Min(width, height) / 2
+		if f.background != nil {
+			fmt.Fprintf(w, "%s\n%sf\n", colorOperator(f.background, false), circlePath(width/2, height/2, r))
+		}
+		if f.border != nil {
+			fmt.Fprintf(w, "%s\n1 w\n%sS\n", colorOperator(f.border, true), circlePath(width/2, height/2, r-0.5))
+		}
+		return
+	}
+
+	if f.background != nil {
+		fmt.Fprintf(w, "%s\n0 0 %.2f %.2f re f\n", colorOperator(f.background, false), width, height)
+	}
+	if f.border != nil {
+		fmt.Fprintf(w, "%s\n1 w 0.50 0.50 %.2f %.2f re S\n", colorOperator(f.border, true), width-1, height-1)
+		if f.flags&fieldFlagComb != 0 {
+			for x := 1; x < f.maxLen; x++ {
+				cx := width * float64(x) / float64(f.maxLen)
+				fmt.Fprintf(w, "%.2f 0 m %.2f %.2f l S\n", cx, cx, height)
+			}
+		}
+	}
+}
+
+// writeLines draws the lines of text in the font of the field, clipped to the
+// widget
+func (f *FormFieldObj) writeLines(w io.Writer, lines []fieldLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "q\n1 1 %.2f %.2f re W n\nBT\n", f.rect[2]-f.rect[0]-2, f.rect[3]-f.rect[1]-2)
+	fmt.Fprintf(w, "/%s %.2f Tf\n%s\n", f.font.procsetIdentifier(), f.fontSize, colorOperator(f.color, false))
+	for _, line := range lines {
+		if line.text == "" {
+			continue
+		}
+		fmt.Fprintf(w, "1 0 0 1 %.2f %.2f Tm\n", line.x, line.y)
+		if err := writeGlyphs(w, f.font, line.text); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "ET\nQ\n")
+	return err
+}
+
+// singleLine returns the text vertically centered in the field
+func (gp *Fpdf) singleLine(field *FormFieldObj, text string) (fieldLine, error) {
+	if field.flags&fieldFlagPassword != 0 {
+		text = strings.Repeat("*", len([]rune(text)))
+	}
+	ascent, descent := fieldFontMetrics(field)
+	h := field.rect[3] - field.rect[1]
+	return gp.alignedLine(field, text, (h-(ascent-descent))/2-descent)
+}
+
+// multilineLines returns the text wrapped at the width of the field, starting
+// at the top
+func (gp *Fpdf) multilineLines(field *FormFieldObj) ([]fieldLine, error) {
+	if field.value == "" {
+		return nil, nil
+	}
+
+	size := gp.curr.Font_Size
+	gp.curr.Font_Size = field.fontSize
+	texts, err := gp.splitLines(field.value, field.rect[2]-field.rect[0]-2*fieldPadding, TextOption{})
+	gp.curr.Font_Size = size
+	if err != nil {
+		return nil, err
+	}
+
+	ascent, descent := fieldFontMetrics(field)
+	y := field.rect[3] - field.rect[1] - fieldPadding - ascent
+	lines := make([]fieldLine, 0, len(texts))
+	for _, text := range texts {
+		line, err := gp.alignedLine(field, strings.TrimRight(text, " "), y)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+		y -= ascent - descent
+	}
+	return lines, nil
+}
+
+// combLines returns a line per character, centered in its position
+func (gp *Fpdf) combLines(field *FormFieldObj) ([]fieldLine, error) {
+	line, err := gp.singleLine(field, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cell := (field.rect[2] - field.rect[0]) / float64(field.maxLen)
+	lines := make([]fieldLine, 0, field.maxLen)
+	for x, r := range []rune(field.value) {
+		_, _, w, err := createContent(field.font, string(r), field.fontSize, nil, TextOption{})
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fieldLine{x: float64(x)*cell + (cell-w)/2, y: line.y, text: string(r)})
+	}
+	return lines, nil
+}
+
+// alignedLine returns the text on the baseline y, aligned like the field
+func (gp *Fpdf) alignedLine(field *FormFieldObj, text string, y float64) (fieldLine, error) {
+	line := fieldLine{x: fieldPadding, y: y, text: text}
+	if text == "" || (field.align != Center && field.align != Right) {
+		return line, nil
+	}
+
+	if err := field.font.AddChars(text); err != nil {
+		return line, err
+	}
+	_, _, w, err := createContent(field.font, text, field.fontSize, nil, TextOption{})
+	if err != nil {
+		return line, err
+	}
+
+	width := field.rect[2] - field.rect[0]
+	if field.align == Center {
+		line.x = (width - w) / 2
+	} else {
+		line.x = width - fieldPadding - w
+	}
+	return line, nil
+}
+
+// fieldFontMetrics returns the ascent and the descent of the font of the
+// field, the descent is negative
+func fieldFontMetrics(field *FormFieldObj) (float64, float64) {
+	ttfp := field.font.GetTTFParser()
+	ascent := convertTypoUnit(float64(ttfp.TypoAscender()), ttfp.UnitsPerEm(), field.fontSize)
+	descent := convertTypoUnit(float64(ttfp.TypoDescender()), ttfp.UnitsPerEm(), field.fontSize)
+	return ascent, descent
+}
+
+// formField returns the field with the name, nil if there is none
+func (gp *Fpdf) formField(name string) *FormFieldObj {
+	for _, obj := range gp.pdfObjs.allOf(formFieldType) {
+		if field := obj.(*FormFieldObj); field.name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// writeAcroForm writes the interactive form of the catalog, the fonts of all
+// fields are available to the reader to fill them in
+func (gp *Fpdf) writeAcroForm(w io.Writer, objID int) error {
+	var first *FormFieldObj
+	fonts := make(map[string]bool)
+	spots := make(map[string]bool)
+
+	io.WriteString(w, "  /AcroForm <<\n")
+	io.WriteString(w, "    /Fields [")
+	for _, obj := range gp.pdfObjs.allOf(formFieldType) {
+		field := obj.(*FormFieldObj)
+		if field.parent == nil {
+			fmt.Fprintf(w, " %d 0 R", field.index+1)
+		}
+		if field.font != nil && first == nil {
+			first = field
+		}
+	}
+	io.WriteString(w, " ]\n")
+
+	io.WriteString(w, "    /DR << /Font <<")
+	for _, obj := range gp.pdfObjs.allOf(formFieldType) {
+		field := obj.(*FormFieldObj)
+		if field.font == nil {
+			continue
+		}
+		if id := field.font.procsetIdentifier(); !fonts[id] {
+			fonts[id] = true
+			fontIndex, _ := gp.pdfObjs.hasProcsetID(id)
+			fmt.Fprintf(w, " /%s %d 0 R", id, fontIndex+1)
+		}
+		for _, id := range spotIdentifiers(field.color) {
+			spots[id] = true
+		}
+	}
+	io.WriteString(w, " >>")
+	if len(spots) > 0 {
+		io.WriteString(w, " /ColorSpace <<")
+		for _, spot := range gp.pdfObjs.allSpotColors() {
+			if id := spot.procsetIdentifier(); spots[id] {
+				index, _ := gp.pdfObjs.hasProcsetID(id)
+				fmt.Fprintf(w, " /%s %d 0 R", id, index+1)
+			}
+		}
+		io.WriteString(w, " >>")
+	}
+	io.WriteString(w, " >>\n")
+
+	if first != nil {
+		da, err := encodeString([]byte(first.defaultAppearance()), gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "    /DA %s\n", da)
+	}
+	if gp.pdfObjs.indexOfFirst(signatureType) >= 0 {
+		// the document is signed and must only be appended to
+		io.WriteString(w, "    /SigFlags 3\n")
+	}
+	io.WriteString(w, "  >>\n")
+	return nil
+}
+
+// colorOperator returns the operator setting the color for fill or stroke
+// operations
+func colorOperator(c Color, stroke bool) string {
+	var buff bytes.Buffer
+	c.cache(stroke).write(&buff, nil)
+	return strings.TrimSuffix(buff.String(), "\n")
+}
+
+// colorComponents returns the components of a gray, RGB or CMYK color, an
+// empty string for other colors
+func colorComponents(c Color) string {
+	switch color := c.(type) {
+	case Gray:
+		return fmt.Sprintf("%.3f", fixRange10(float64(color)))
+	case RGB:
+		return fmt.Sprintf("%.3f %.3f %.3f", float64(color.R)*rgbManipulant, float64(color.G)*rgbManipulant, float64(color.B)*rgbManipulant)
+	case CMYK:
+		return fmt.Sprintf("%.3f %.3f %.3f %.3f", float64(color.C)/cmykManipulant, float64(color.M)/cmykManipulant, float64(color.Y)/cmykManipulant, float64(color.K)/cmykManipulant)
+	}
+	return ""
+}
+
+// spotIdentifiers returns the identifiers of the color spaces of the spot
+// colors among colors
+func spotIdentifiers(colors ...Color) []string {
+	var ids []string
+	for _, c := range colors {
+		if spot, ok := c.(Spot); ok {
+			ids = append(ids, spot.id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,171 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFormFields(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTextField("name", 10, 10, 100, 20, TextFieldOption{}); err == nil {
+		t.Error("a field without a page should fail")
+	}
+	pdf.AddPage()
+	if err := pdf.AddTextField("name", 10, 10, 100, 20, TextFieldOption{}); err == nil {
+		t.Error("a field without a font should fail")
+	}
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+
+	border := FormFieldOption{Border: Gray(0.5), Background: RGB{R: 255, G: 255, B: 204}}
+	fields := []struct {
+		name string
+		add  func(name string) error
+	}{
+		{"name", func(name string) error {
+			opt := TextFieldOption{FormFieldOption: border, Align: Center}
+			opt.Value = "Jane Doe"
+			opt.Required = true
+			return pdf.AddTextField(name, 10, 10, 150, 20, opt)
+		}},
+		{"address", func(name string) error {
+			opt := TextFieldOption{Multiline: true}
+			opt.Value = "Main Street 1 and a rather long second part\nSpringfield"
+			return pdf.AddTextField(name, 10, 40, 150, 60, opt)
+		}},
+		{"pin", func(name string) error {
+			return pdf.AddTextField(name, 10, 110, 80, 20, TextFieldOption{Password: true, MaxLen: 4})
+		}},
+		{"zip", func(name string) error {
+			opt := TextFieldOption{FormFieldOption: border, MaxLen: 5, Comb: true}
+			opt.Value = "12345"
+			return pdf.AddTextField(name, 10, 140, 100, 20, opt)
+		}},
+		{"country", func(name string) error {
+			opt := ChoiceFieldOption{Options: []string{"Germany", "Thailand"}, Editable: true}
+			opt.Value = "Thailand"
+			return pdf.AddComboBox(name, 10, 170, 100, 20, opt)
+		}},
+		{"plan", func(name string) error {
+			opt := ChoiceFieldOption{Options: []string{"Basic", "Premium", "Enterprise"}}
+			opt.Value = "Premium"
+			opt.ReadOnly = true
+			return pdf.AddListBox(name, 10, 200, 100, 60, opt)
+		}},
+	}
+	for _, f := range fields {
+		if err := f.add(f.name); err != nil {
+			t.Fatalf("%s: %s", f.name, err)
+		}
+	}
+
+	if err := fields[0].add("name"); err == nil {
+		t.Error("a duplicate name should fail")
+	}
+	if err := pdf.AddTextField("comb", 10, 10, 100, 20, TextFieldOption{Comb: true}); err == nil {
+		t.Error("a comb field without a maximum length should fail")
+	}
+	if err := pdf.AddListBox("list", 10, 10, 100, 20, ChoiceFieldOption{Options: []string{"a"}, FormFieldOption: FormFieldOption{Value: "b"}}); err == nil {
+		t.Error("a value that is not an option should fail")
+	}
+
+	pdf.SetNoCompression()
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	form := reader.resolve(catalog["AcroForm"]).(pdfDict)
+	refs := reader.resolve(form["Fields"]).(pdfArray)
+	if len(refs) != len(fields) {
+		t.Fatalf("expected %d fields, found %d", len(fields), len(refs))
+	}
+	resources := reader.resolve(form["DR"]).(pdfDict)
+	if fonts := reader.resolve(resources["Font"]).(pdfDict); len(fonts) != 1 {
+		t.Errorf("expected 1 font in the form resources, found %d", len(fonts))
+	}
+
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	annots := reader.resolve(pages[0]["Annots"]).(pdfArray)
+	if len(annots) != len(fields) {
+		t.Errorf("expected %d widget annotations, found %d", len(fields), len(annots))
+	}
+
+	expected := map[string]struct {
+		ft    pdfName
+		flags int
+	}{
+		"name":    {"Tx", fieldFlagRequired},
+		"address": {"Tx", fieldFlagMultiline},
+		"pin":     {"Tx", fieldFlagPassword},
+		"zip":     {"Tx", fieldFlagComb | fieldFlagDoNotScroll},
+		"country": {"Ch", fieldFlagCombo | fieldFlagEdit},
+		"plan":    {"Ch", fieldFlagReadOnly},
+	}
+	for _, ref := range refs {
+		field := reader.resolve(ref).(pdfDict)
+		name := string(textString(field["T"]))
+		e, ok := expected[name]
+		if !ok {
+			t.Errorf("unexpected field %q", name)
+			continue
+		}
+		flags, _ := field["Ff"].(int)
+		if field["FT"] != e.ft || flags != e.flags || field["Subtype"] != pdfName("Widget") {
+			t.Errorf("unexpected field %q %v", name, field)
+		}
+
+		ap := reader.resolve(field["AP"]).(pdfDict)
+		stream := reader.resolve(ap["N"]).(pdfStream)
+		content, err := reader.decodeStream(stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(content, []byte("/Tx BMC\n")) {
+			t.Errorf("unexpected appearance of %q %q", name, content)
+		}
+		if v, ok := field["V"]; ok && string(textString(v)) != string(textString(field["DV"])) {
+			t.Errorf("the default value of %q should be its value", name)
+		}
+	}
+
+	for _, s := range []string{
+		"/MaxLen 5",
+		"/Q 1",
+		"/MK << /BC [0.500] /BG [1.000 1.000 0.800] >>",
+		"0.60 0.76 0.85 rg",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+}
+
+// textString decodes a text string written as UTF-16BE with a byte order
+// mark, other strings are returned unchanged
+func textString(v interface{}) pdfString {
+	s, _ := v.(pdfString)
+	if len(s) < 2 || s[0] != 0xFE || s[1] != 0xFF {
+		return s
+	}
+	var runes []rune
+	for x := 2; x+1 < len(s); x += 2 {
+		runes = append(runes, rune(s[x])<<8|rune(s[x+1]))
+	}
+	return pdfString(string(runes))
+}
@@ -0,0 +1,71 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ISeeMe/gofpdf/geh"
+)
+
+const functionType = "Function"
+
+// FunctionObj function mapping the position within a gradient to a color.
+// Two stops are interpolated by an exponential interpolation function, more
+// stops by a stitching function combining one interpolation per pair of
+// stops.
+type FunctionObj struct { //impl IObj
+	stops []GradientStop
+}
+
+func newFunctionObj(stops []GradientStop) *FunctionObj {
+	return &FunctionObj{
+		stops: stops,
+	}
+}
+
+func (f *FunctionObj) getType() string {
+	return functionType
+}
+
+func (f *FunctionObj) write(w io.Writer, objID int) error {
+	if len(f.stops) == 2 {
+		io.WriteString(w, f.interpolation(f.stops[0], f.stops[1]))
+		io.WriteString(w, "\n")
+		return nil
+	}
+
+	functions := make([]string, 0, len(f.stops)-1)
+	bounds := make([]string, 0, len(f.stops)-2)
+	encode := make([]string, 0, len(f.stops)-1)
+	for x := 1; x < len(f.stops); x++ {
+		functions = append(functions, f.interpolation(f.stops[x-1], f.stops[x]))
+		encode = append(encode, "0 1")
+		if x < len(f.stops)-1 {
+			bounds = append(bounds, fmt.Sprintf("%.5f", f.stops[x].Offset))
+		}
+	}
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /FunctionType 3\n")
+	io.WriteString(w, "  /Domain [0 1]\n")
+	fmt.Fprintf(w, "  /Functions [%s]\n", strings.Join(functions, " "))
+	fmt.Fprintf(w, "  /Bounds [%s]\n", strings.Join(bounds, " "))
+	fmt.Fprintf(w, "  /Encode [%s]\n", strings.Join(encode, " "))
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// interpolation returns the dictionary of the function interpolating
+// linearly between two stops
+func (f *FunctionObj) interpolation(from, to GradientStop) string {
+	return fmt.Sprintf("<</FunctionType 2 /Domain [0 1] /C0 [%s] /C1 [%s] /N 1>>", from.color(), to.color())
+}
+
+func (f *FunctionObj) GobEncode() ([]byte, error) {
+	return geh.EncodeMany(f.stops)
+}
+
+func (f *FunctionObj) GobDecode(buf []byte) error {
+	return geh.DecodeMany(buf, &f.stops)
+}
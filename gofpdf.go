@@ -30,6 +30,8 @@ type Fpdf struct {
 
 	pdfObjs *pdfObjs
 	anchors map[string]anchorOption
+	// named destinations by name
+	destinations map[string]Destination
 
 	curr Current
 
@@ -47,6 +49,44 @@ type Fpdf struct {
 
 	info        *PdfInfo
 	appliedOpts []PdfOption
+
+	// page breaks, headers and footers
+	autoPageBreak       bool
+	acceptPageBreakFunc func() bool
+	headerFunc          func()
+	footerFunc          func()
+	inHeader            bool
+	inFooter            bool
+
+	// aliases replaced when the document is written, alias -> kind
+	aliases map[string]string
+	// page labels by the number of the first page of their range
+	pageLabels map[int]pageLabel
+
+	// spot colors by name
+	spotColors map[string]*SpotColorObj
+
+	// optional content groups
+	layers layers
+
+	// how the viewer shows the document
+	viewer viewerOptions
+
+	// the structure tree of a tagged document
+	tags tags
+	// the natural language of the document
+	lang string
+	// the XMP metadata
+	xmp xmpMetadata
+	// the conformance level, 0 for none
+	conformance int
+	// the creation date, set when the document is written first
+	creationDate time.Time
+
+	// objects copied from imported documents, "source number" -> index
+	importedObjs map[string]int
+	// compiling is true while the objects are written
+	compiling bool
 }
 
 // Set a page boundary
@@ -216,6 +256,7 @@ func (gp *Fpdf) Beziergon(pts Points, styleStr string) error {
 		return fmt.Errorf("the number of points can not be less than 4. %d found", len(points))
 	}
 
+	gp.markContent()
 	gp.currentContent().AppendStreamPoint(points[0].XY())
 
 	points = points[1:]
@@ -328,6 +369,7 @@ func (gp *Fpdf) Beziertext(pts Points, startBracket, endBracket float64, text st
 		Border: opt.Border,
 		Float:  opt.Float,
 	}
+	gp.markContent()
 	for i, v := range selected {
 		gp.Rotate(v.normaldir, v.pt.X/72.0, v.pt.Y/72.0)
 		width := endpts[i+1] - endpts[i]*72.0
@@ -364,6 +406,7 @@ func (gp *Fpdf) Beziertext(pts Points, startBracket, endBracket float64, text st
 // The Circle() example demonstrates this method.
 func (gp *Fpdf) CurveBezierCubic(x0, y0, cx0, cy0, cx1, cy1, x1, y1 float64, styleStr string) {
 	gp.UnitsToPointsVar(&x0, &y0, &cx0, &cy0, &cx1, &cy1, &x1, &y1)
+	gp.markContent()
 	gp.currentContent().AppendStreamPoint(x0, y0)
 	gp.currentContent().AppendStreamCurveBezierCubic(cx0, cy0, cx1, cy1, x1, y1)
 	gp.currentContent().AppendStreamDrawPath(styleStr)
@@ -438,11 +481,7 @@ func (gp *Fpdf) ln(h float64, toLeftMargin bool) {
 		gp.curr.X = gp.margins.Left
 	}
 
-	if gp.curr.Y+h > gp.bottomMarginHeight() {
-		page := gp.currentPage()
-		gp.addPageWithOption(page.pageOption)
-	}
-
+	gp.pageBreak(h)
 	gp.curr.Y += gp.curr.setLineHeight(h)
 }
 
@@ -470,6 +509,7 @@ func (gp *Fpdf) Circle(x, y, r float64, styleStr string) {
 // The Circle() example demonstrates this method.
 func (gp *Fpdf) Ellipse(x, y, rx, ry, degRotate float64, styleStr string) {
 	gp.UnitsToPointsVar(&x, &y, &rx, &ry)
+	gp.markContent()
 	gp.currentContent().AppendStreamArcTo(x, y, rx, ry, degRotate, 0, 360, styleStr, false)
 }
 
@@ -489,6 +529,7 @@ func (gp *Fpdf) Ellipse(x, y, rx, ry, degRotate float64, styleStr string) {
 // The Circle() example demonstrates this method.
 func (gp *Fpdf) Arc(x, y, rx, ry, degRotate, degStart, degEnd float64, styleStr string) {
 	gp.UnitsToPointsVar(&x, &y, &rx, &ry)
+	gp.markContent()
 	gp.currentContent().AppendStreamArcTo(x, y, rx, ry, degRotate, degStart, degEnd, styleStr, true)
 
 }
@@ -516,6 +557,7 @@ func (gp *Fpdf) ArcTo(x, y, rx, ry, degRotate, degStart, degEnd float64) {
 //Line : draw line
 func (gp *Fpdf) Line(x1 float64, y1 float64, x2 float64, y2 float64) {
 	gp.UnitsToPointsVar(&x1, &y1, &x2, &y2)
+	gp.markContent()
 	gp.currentContent().AppendStreamLine(x1, y1, x2, y2)
 }
 
@@ -531,6 +573,7 @@ func (gp *Fpdf) Line(x1 float64, y1 float64, x2 float64, y2 float64) {
 // overlaying the lines.
 func (gp *Fpdf) MoveTo(x, y float64) {
 	gp.UnitsToPointsVar(&x, &y)
+	gp.markContent()
 	gp.currentContent().AppendStreamPoint(x, y)
 	gp.curr.X, gp.curr.Y = x, y
 }
@@ -548,12 +591,14 @@ func (gp *Fpdf) LineTo(x, y float64) {
 //RectFromLowerLeft : draw rectangle from lower-left corner (x, y)
 func (gp *Fpdf) RectFromLowerLeft(x float64, y float64, wdth float64, hght float64) {
 	gp.UnitsToPointsVar(&x, &y, &wdth, &hght)
+	gp.markContent()
 	gp.currentContent().AppendStreamRectangle(x, y, wdth, hght, "")
 }
 
 //RectFromUpperLeft : draw rectangle from upper-left corner (x, y)
 func (gp *Fpdf) RectFromUpperLeft(x float64, y float64, wdth float64, hght float64) {
 	gp.UnitsToPointsVar(&x, &y, &wdth, &hght)
+	gp.markContent()
 	gp.currentContent().AppendStreamRectangle(x, y+hght, wdth, hght, "")
 }
 
@@ -564,6 +609,7 @@ func (gp *Fpdf) RectFromUpperLeft(x float64, y float64, wdth float64, hght float
 //		DF or FD: draw and fill
 func (gp *Fpdf) RectFromLowerLeftWithStyle(x float64, y float64, wdth float64, hght float64, style string) {
 	gp.UnitsToPointsVar(&x, &y, &wdth, &hght)
+	gp.markContent()
 	gp.currentContent().AppendStreamRectangle(x, y, wdth, hght, style)
 }
 
@@ -574,25 +620,25 @@ func (gp *Fpdf) RectFromLowerLeftWithStyle(x float64, y float64, wdth float64, h
 //		DF or FD: draw and fill
 func (gp *Fpdf) RectFromUpperLeftWithStyle(x float64, y float64, wdth float64, hght float64, style string) {
 	gp.UnitsToPointsVar(&x, &y, &wdth, &hght)
+	gp.markContent()
 	gp.currentContent().AppendStreamRectangle(x, y+hght, wdth, hght, style)
 }
 
 //Oval : draw oval
 func (gp *Fpdf) Oval(x1 float64, y1 float64, x2 float64, y2 float64) {
 	gp.UnitsToPointsVar(&x1, &y1, &x2, &y2)
+	gp.markContent()
 	gp.currentContent().AppendStreamOval(x1, y1, x2, y2)
 }
 
 //SetGrayFill set the grayscale for the fill, takes a float64 between 0.0 and 1.0
 func (gp *Fpdf) SetGrayFill(grayScale float64) {
-	gp.curr.grayFill = grayScale
-	gp.currentContent().AppendStreamSetGrayFill(grayScale)
+	gp.SetFillColor(Gray(grayScale))
 }
 
 //SetGrayStroke set the grayscale for the stroke, takes a float64 between 0.0 and 1.0
 func (gp *Fpdf) SetGrayStroke(grayScale float64) {
-	gp.curr.grayStroke = grayScale
-	gp.currentContent().AppendStreamSetGrayStroke(grayScale)
+	gp.SetStrokeColor(Gray(grayScale))
 }
 
 //SetX : set current position X
@@ -646,17 +692,47 @@ func (gp *Fpdf) ImageByObj(img *ImageObj, x float64, y float64, rect Rect) error
 		return err
 	}
 
-	gp.currentContent().AppendStreamImage(cacheImageIndex, x, y, rect)
-	return nil
+	return gp.drawImage(img, cacheImageIndex, x, y, rect)
 }
 
 func (gp *Fpdf) imageByHolder(img ImageHolder, x float64, y float64, rect Rect) error {
-	cacheImageIndex, _, err := gp.registerImageByHolder(img)
+	cacheImageIndex, index, err := gp.registerImageByHolder(img)
 	if err != nil {
 		return err
 	}
 
-	gp.currentContent().AppendStreamImage(cacheImageIndex, x, y, rect)
+	return gp.drawImage(gp.pdfObjs.at(index).(*ImageObj), cacheImageIndex, x, y, rect)
+}
+
+// drawImage draws the registered image in rect at x, y, breaking the page
+// if the image does not fit above the bottom margin. A zero width or height
+// is computed from the other keeping the aspect ratio of the image, if both
+// are zero the image is drawn in the size returned by GetRect.
+func (gp *Fpdf) drawImage(img *ImageObj, id string, x, y float64, rect Rect) error {
+	if rect.W == 0 || rect.H == 0 {
+		size, err := img.getRect()
+		if err != nil {
+			return err
+		}
+		// the aspect ratio is the one of the pixels, the size of GetRect is
+		// rounded
+		w, h := float64(img.imginfo.w), float64(img.imginfo.h)
+		switch {
+		case rect.W == 0 && rect.H == 0:
+			rect = *size
+		case w == 0 || h == 0:
+		case rect.H == 0:
+			rect.H = rect.W * h / w
+		default:
+			rect.W = rect.H * w / h
+		}
+	}
+
+	if gp.pageBreakAt(y, rect.H) {
+		y = gp.curr.Y
+	}
+	gp.markContent()
+	gp.currentContent().AppendStreamImage(id, x, y, rect)
 	return nil
 }
 
@@ -703,40 +779,81 @@ func (gp *Fpdf) useTemplateScaled(t Template, corner Point, size Rect) error {
 		return errors.New("template is nil")
 	}
 
+	id, err := gp.registerTplWithResources(t)
+	if err != nil {
+		return err
+	}
+
+	// templates containing the page number alias are written for every page using them
+	if templateHasPageAlias(t) {
+		templates := t.Templates()
+		for x := 0; x < len(templates); x++ {
+			if templateHasPageAlias(templates[x]) {
+				gp.registerTplForPage(templates[x], gp.curr.IndexOfPageObj)
+			}
+		}
+		gp.registerTplForPage(t, gp.curr.IndexOfPageObj)
+	}
+
+	_, tSize := t.Size()
+	scalex := size.W / tSize.W
+	scaley := size.H / tSize.H
+
+	gp.markContent()
+	gp.currentContent().AppendStreamUseTemplate(id, corner.X, corner.Y, size.H, scalex, scaley)
+	return nil
+}
+
+// registerTplWithResources adds the template and the resources it uses to the
+// document, returning the identifier of the template
+func (gp *Fpdf) registerTplWithResources(t Template) (string, error) {
 	templates := t.Templates()
 	for x := 0; x < len(templates); x++ {
 		if _, _, err := gp.registerTpl(templates[x]); err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	imgs := t.Images()
 	for x := 0; x < len(imgs); x++ {
 		if _, _, err := gp.registerImageByImageObj(imgs[x]); err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	fonts := t.Fonts()
 	for x := 0; x < len(fonts); x++ {
 		if err := gp.AddTTFFontBySubsetFont(fonts[x].Family, fonts[x]); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	id, _, err := gp.registerTpl(t)
-	if err != nil {
-		return err
+	if resources, ok := t.(templateResources); ok {
+		states := resources.ExtGStates()
+		for x := 0; x < len(states); x++ {
+			gp.addProcsetObj(states[x])
+		}
+
+		shadings := resources.Shadings()
+		for x := 0; x < len(shadings); x++ {
+			gp.registerShading(shadings[x])
+		}
+
+		spots := resources.SpotColors()
+		for x := 0; x < len(spots); x++ {
+			gp.addProcsetObj(spots[x])
+		}
 	}
-	_, tSize := t.Size()
-	scalex := size.W / tSize.W
-	scaley := size.H / tSize.H
 
-	gp.currentContent().AppendStreamUseTemplate(id, corner.X, corner.Y, size.H, scalex, scaley)
-	return nil
+	id, _, err := gp.registerTpl(t)
+	return id, err
 }
 
 func (gp *Fpdf) registerTpl(template Template) (string, int, error) {
+	if imported, ok := template.(*ImportedTpl); ok {
+		gp.registerImportedObjs(imported)
+	}
+
 	//create img object
 	tplObj := newTemplateObj(template, gp.protection(), func() *Fpdf {
 		return gp
@@ -746,6 +863,13 @@ func (gp *Fpdf) registerTpl(template Template) (string, int, error) {
 	return a, b, nil
 }
 
+func (gp *Fpdf) registerTplForPage(template Template, pageIndex int) (string, int) {
+	tplObj := newTemplatePageObj(template, pageIndex, gp.protection(), func() *Fpdf {
+		return gp
+	})
+	return gp.addProcsetObj(tplObj)
+}
+
 func (gp *Fpdf) procsetIndex(id string, isFont bool) int {
 	if isFont {
 		return gp.procset().Realtes.getIndex(id)
@@ -860,16 +984,79 @@ func (gp *Fpdf) AddPageWithOption(opt PageOption) {
 }
 
 func (gp *Fpdf) addPageWithOption(opt PageOption) {
+	gp.footer()
+	gp.leavePage()
+
 	page := new(PageObj)
 	page.init(func() *Fpdf {
 		return gp
 	})
 
 	page.setOption(gp.curr.pageOption.merge(opt))
+	page.margins = gp.margins
 
 	page.ResourcesRelate = fmt.Sprintf("%d 0 R", gp.pdfObjs.indexOfFirst(procSetType)+1)
-	gp.pdfObjs.addPageObj(page)
+	gp.curr.IndexOfPageObj = gp.pdfObjs.addPageObj(page)
 	gp.resetCurrXY()
+	gp.resumeAlpha()
+	gp.resumeColors()
+	gp.header()
+	gp.resumeLayer()
+}
+
+// SetPage makes page n the current page, pages start at 1. Everything drawn
+// afterwards is appended to the content of that page using its own size.
+// The position and margins that were active when the page was left are
+// restored, so it is possible to return to the last page and continue.
+func (gp *Fpdf) SetPage(n int) error {
+	pages := gp.pdfObjs.typeMap[pageType]
+	if n < 1 || n > len(pages) {
+		return fmt.Errorf("page %d does not exist, the document has %d pages", n, len(pages))
+	}
+
+	gp.leavePage()
+
+	index := pages[n-1]
+	page := gp.pdfObjs.getPage(index)
+	gp.curr.IndexOfPageObj = index
+	gp.margins = page.margins
+	gp.curr.X, gp.curr.Y = page.x, page.y
+	gp.resumeAlpha()
+	gp.resumeColors()
+	gp.resumeLayer()
+	return nil
+}
+
+// PageCount returns the number of pages in the document
+func (gp *Fpdf) PageCount() int {
+	return len(gp.pdfObjs.typeMap[pageType])
+}
+
+// CurrentPageNumber returns the number of the page that is drawn on, pages
+// start at 1. 0 is returned when there are no pages.
+func (gp *Fpdf) CurrentPageNumber() int {
+	return gp.pageNumber(gp.curr.IndexOfPageObj)
+}
+
+// pageNumber returns the page number of the page object at index
+func (gp *Fpdf) pageNumber(index int) int {
+	pages := gp.pdfObjs.typeMap[pageType]
+	for x := 0; x < len(pages); x++ {
+		if pages[x] == index {
+			return x + 1
+		}
+	}
+	return 0
+}
+
+// leavePage stores the position and margins of the current page so they can
+// be restored by SetPage
+func (gp *Fpdf) leavePage() {
+	gp.suspendLayer()
+	if page := gp.currentPage(); page != nil {
+		page.margins = gp.margins
+		page.x, page.y = gp.curr.X, gp.curr.Y
+	}
 }
 
 //New creates a new Fpdf Object
@@ -946,8 +1133,35 @@ func (gp *Fpdf) Close() error {
 }
 
 func (gp *Fpdf) compilePdf(w io.Writer) error {
+	if err := gp.closePages(); err != nil {
+		return err
+	}
+	if err := gp.addPageLabelChars(); err != nil {
+		return err
+	}
+	if gp.tags.enabled {
+		gp.unmarkContent()
+		gp.structTreeRoot()
+	}
+	if gp.conformance != 0 {
+		if err := gp.checkConformance(); err != nil {
+			return err
+		}
+		gp.addConformanceObjs()
+	}
+	if gp.hasMetadata() {
+		gp.addMetadataObj()
+	}
+
+	if gp.pdfObjs.indexOfFirst(infoType) < 0 {
+		infoObj := new(PdfInfoObj)
+		infoObj.init(func() *Fpdf {
+			return gp
+		})
+		gp.addObj(infoObj)
+	}
 
-	if gp.isUseProtection() {
+	if gp.isUseProtection() && gp.pdfObjs.indexOfFirst(encryptionType) < 0 {
 		encObj := gp.pdfProtection.encryptionObj()
 		gp.addObj(encObj)
 	}
@@ -956,11 +1170,36 @@ func (gp *Fpdf) compilePdf(w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	writer := newCountingWriter(w)
+	// a signed document is signed once it is completely written
+	signature := gp.signature()
+	var signed bytes.Buffer
+	out := w
+	if signature != nil {
+		out = &signed
+	}
+
+	writer := newCountingWriter(out)
 	//io.WriteString(w, "%PDF-1.7\n\n")
 	fmt.Fprint(writer, "%PDF-1.7\n\n")
+	if gp.conformance != 0 {
+		// marks the file as binary
+		fmt.Fprint(writer, "%\xe2\xe3\xcf\xd3\n\n")
+	}
+	gp.compiling = true
+	defer func() { gp.compiling = false }()
 	linelens, err := gp.pdfObjs.write(writer)
+	if err != nil {
+		return err
+	}
 	gp.xref(writer, writer.offset, linelens, len(linelens))
+
+	if signature != nil {
+		if err := signature.sign(signed.Bytes()); err != nil {
+			return err
+		}
+		_, err = w.Write(signed.Bytes())
+		return err
+	}
 	return nil
 }
 
@@ -1009,6 +1248,7 @@ func (gp *Fpdf) Text(x, y float64, text string) error {
 		return err
 	}
 
+	gp.markContent()
 	err = gp.currentContent().AppendStreamText(text)
 	if err != nil {
 		return err
@@ -1109,11 +1349,6 @@ func (gp *Fpdf) MultiCellOpts(w, h float64, txtStr string, opts CellOption, text
 	rectangle := Rect{W: w, H: h}
 
 	for x := 0; x < len(lines); x++ {
-		if gp.curr.Y+h > gp.bottomMarginHeight() {
-			page := gp.currentPage()
-			gp.addPageWithOption(page.pageOption)
-		}
-
 		err = gp.cellWithOption(rectangle, lines[x], opts, textOpts)
 
 		if err != nil {
@@ -1195,11 +1430,14 @@ func (gp *Fpdf) CellWithOption(w, h float64, text string, opt CellOption, textOp
 }
 
 func (gp *Fpdf) cellWithOption(rect Rect, text string, opt CellOption, textOpts TextOption) error {
+	gp.pageBreak(rect.H)
+
 	err := gp.curr.Font_ISubset.AddChars(text)
 	if err != nil {
 		return err
 	}
 
+	gp.markContent()
 	err = gp.currentContent().AppendStreamSubsetFont(rect, text, opt, textOpts)
 	return err
 }
@@ -1228,18 +1466,23 @@ func (gp *Fpdf) Cell(w, h float64, text string) error {
 func (gp *Fpdf) AddExternalLink(url string, x, y, w, h float64) {
 	gp.UnitsToPointsVar(&x, &y, &w, &h)
 	page := gp.currentPage()
-	page.Links = append(page.Links, linkOption{x, gp.GetBoundaryHeight(PageBoundaryMedia) - y, w, h, url, ""})
+	page.Links = append(page.Links, linkOption{x: x, y: gp.GetBoundaryHeight(PageBoundaryMedia) - y, w: w, h: h, url: url, style: gp.curr.linkStyle})
 }
 
 func (gp *Fpdf) AddInternalLink(anchor string, x, y, w, h float64) {
 	gp.UnitsToPointsVar(&x, &y, &w, &h)
 	page := gp.currentPage()
-	page.Links = append(page.Links, linkOption{x, gp.GetBoundaryHeight(PageBoundaryMedia) - y, w, h, "", anchor})
+	page.Links = append(page.Links, linkOption{x: x, y: gp.GetBoundaryHeight(PageBoundaryMedia) - y, w: w, h: h, anchor: anchor, style: gp.curr.linkStyle})
 }
 
 func (gp *Fpdf) SetAnchor(name string) {
+	gp.anchors[name] = gp.currentAnchor()
+}
+
+// currentAnchor returns the destination of the current position on the current page
+func (gp *Fpdf) currentAnchor() anchorOption {
 	y := gp.GetBoundaryHeight(PageBoundaryMedia) - gp.curr.Y + float64(gp.curr.Font_Size)
-	gp.anchors[name] = anchorOption{gp.curr.IndexOfPageObj, y}
+	return anchorOption{gp.curr.IndexOfPageObj, y}
 }
 
 //AddTTFFontByReader add font file
@@ -1354,34 +1597,30 @@ func (gp *Fpdf) KernOverride(family string, fn FuncKernOverride) error {
 	return errors.New("font family not found")
 }
 
-//SetTextColor :  function sets the text color
-func (gp *Fpdf) SetTextColor(r uint8, g uint8, b uint8) {
-	rgb := Rgb{
-		r: r,
-		g: g,
-		b: b,
-	}
-	gp.curr.setTextColor(rgb)
-}
-
 //SetRBStrokeColor set the color for the stroke
 func (gp *Fpdf) SetRGBStrokeColor(r uint8, g uint8, b uint8) {
-	gp.currentContent().AppendStreamSetRGBColorStroke(r, g, b)
+	gp.SetStrokeColor(RGB{R: r, G: g, B: b})
 }
 
 //SetRGBFillColor set the color for the stroke
 func (gp *Fpdf) SetRGBFillColor(r uint8, g uint8, b uint8) {
-	gp.currentContent().AppendStreamSetRGBColorFill(r, g, b)
+	gp.SetFillColor(RGB{R: r, G: g, B: b})
+}
+
+//SetRGBTextColor set the color of the text, like SetTextColor(r, g, b)
+//before it accepted a Color
+func (gp *Fpdf) SetRGBTextColor(r uint8, g uint8, b uint8) {
+	gp.SetTextColor(RGB{R: r, G: g, B: b})
 }
 
 //SetCMYKStrokeColor set the color for the stroke
 func (gp *Fpdf) SetCMYKStrokeColor(c, m, y, k uint8) {
-	gp.currentContent().AppendStreamSetCMYKColorStroke(c, m, y, k)
+	gp.SetStrokeColor(CMYK{C: c, M: m, Y: y, K: k})
 }
 
 //SetCMYKFillColor set the color for the stroke
 func (gp *Fpdf) SetCMYKFillColor(c, m, y, k uint8) {
-	gp.currentContent().AppendStreamSetCMYKColorFill(c, m, y, k)
+	gp.SetFillColor(CMYK{C: c, M: m, Y: y, K: k})
 }
 
 //MeasureTextWidth : measure Width of text (use current font)
@@ -1423,6 +1662,7 @@ func (gp *Fpdf) CurveTo(cx, cy, x, y float64) {
 // The Circle() example demonstrates this method.
 func (gp *Fpdf) Curve(x0, y0, cx, cy, x1, y1 float64, styleStr string) {
 	gp.UnitsToPointsVar(&x0, &y0, &cx, &cy, &x1, &y1)
+	gp.markContent()
 	gp.currentContent().AppendStreamPoint(x0, y0)
 	gp.currentContent().AppendStreamCurve(cx, cy, x1, y1)
 	gp.currentContent().AppendStreamDrawPath(styleStr)
@@ -1476,6 +1716,13 @@ func (gp *Fpdf) init() {
 	//No underline
 	//gp.IsUnderline = false
 	gp.curr.lineWidth = 1
+	gp.layers.current = -1
+	gp.curr.fillColor = Gray(0)
+	gp.curr.strokeColor = Gray(0)
+	gp.curr.fillAlpha = 1
+	gp.curr.strokeAlpha = 1
+	gp.curr.blendMode = "Normal"
+	gp.autoPageBreak = true
 	// default to zlib.DefaultCompression
 	gp.compressLevel = zlib.DefaultCompression
 	// default units is points
@@ -1514,9 +1761,13 @@ func (gp *Fpdf) xref(w io.Writer, xrefbyteoffset int, linelens []int, i int) err
 	io.WriteString(w, "/Root 1 0 R\n")
 	if id := gp.pdfObjs.indexOfFirst(encryptionType); id >= 0 {
 		fmt.Fprintf(w, "/Encrypt %d 0 R\n", id+1)
-		io.WriteString(w, "/ID [()()]\n")
 	}
-	gp.GetInfo().write(w)
+	if fileID := gp.fileID(); fileID != nil {
+		fmt.Fprintf(w, "/ID [<%X><%X>]\n", fileID, fileID)
+	}
+	if id := gp.pdfObjs.indexOfFirst(infoType); id >= 0 {
+		fmt.Fprintf(w, "/Info %d 0 R\n", id+1)
+	}
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "startxref\n")
 	fmt.Fprintf(w, "%d", xrefbyteoffset)
@@ -1543,11 +1794,11 @@ func (gp *Fpdf) addProcsetObj(iobj ProcsetIObj) (string, int) {
 }
 
 func (gp *Fpdf) currentPage() *PageObj {
-	return gp.pdfObjs.currentPage()
+	return gp.pdfObjs.getPage(gp.curr.IndexOfPageObj)
 }
 
 func (gp *Fpdf) currentContent() *ContentObj {
-	return gp.pdfObjs.currentContent()
+	return gp.pdfObjs.getPageContent(gp.currentPage())
 }
 
 func (gp *Fpdf) procset() *ProcSetObj {
@@ -415,3 +415,52 @@ func TestPdfWithImageHolder(t *testing.T) {
 
 	pdf.WritePdf("./test/out/image_test.pdf")
 }
+
+func TestSetPage(t *testing.T) {
+	pdf, err := New(PdfOptionUnit(Unit_IN), PdfOptionPageSize(12, 12))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if pdf.PageCount() != 0 || pdf.CurrentPageNumber() != 0 {
+		t.Error("an empty document should not have pages")
+	}
+
+	pdf.AddPage()
+	pdf.Line(0, 0, 12, 12)
+	pdf.AddPageWithOption(*NewPageOption(Unit_IN, 6, 6))
+	pdf.SetXY(2, 3)
+
+	if err := pdf.SetPage(3); err == nil {
+		t.Error("expected an error for a page that does not exist")
+	}
+
+	if err := pdf.SetPage(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if pdf.CurrentPageNumber() != 1 || pdf.PageCount() != 2 {
+		t.Errorf("expected page 1 of 2, found page %d of %d", pdf.CurrentPageNumber(), pdf.PageCount())
+	}
+
+	if pdf.GetBoundaryHeight(PageBoundaryMedia) != 12*72 {
+		t.Errorf("expected the height of the first page, found %f", pdf.GetBoundaryHeight(PageBoundaryMedia))
+	}
+
+	pdf.Line(0, 12, 12, 0)
+	pages := pdf.getAllPages()
+	if l := len(pages[0].getContent().listCache.caches); l != 2 {
+		t.Errorf("expected 2 lines on the first page, found %d", l)
+	}
+
+	if err := pdf.SetPage(2); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if x, y := pdf.XY(); x != 2 || y != 3 {
+		t.Errorf("expected the position to be restored, found %f %f", x, y)
+	}
+}
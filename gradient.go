@@ -0,0 +1,134 @@
+package gofpdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GradientStop a color of a gradient. Offset is the position of the color
+// along the gradient, from 0.0 at its origin to 1.0 at its end.
+type GradientStop struct {
+	Offset  float64
+	R, G, B uint8
+}
+
+func (g GradientStop) color() string {
+	return fmt.Sprintf("%.5f %.5f %.5f",
+		float64(g.R)*rgbManipulant, float64(g.G)*rgbManipulant, float64(g.B)*rgbManipulant)
+}
+
+// LinearGradient draws a rectangular area with a blending of one color to
+// another. The rectangle is of width w and height h. Its upper left corner is
+// positioned at point (x, y).
+//
+// Each color is specified with three component values, one each for red, green
+// and blue. The first color is specified by (r1, g1, b1) and the second color
+// by (r2, g2, b2).
+//
+// The blending is controlled with a gradient vector that uses normalized
+// coordinates in which the lower left corner is position (0, 0) and the upper
+// right corner is (1, 1). The vector's origin and destination are specified by
+// the points (x1, y1) and (x2, y2). In a linear gradient, blending occurs
+// perpendicularly to the vector. Color 1 is used up to the origin of the
+// vector and color 2 is used beyond the vector's end point.
+//
+// The gradient is clipped by the current clipping path, use ClipEllipse(),
+// ClipPolygon(), etc to paint other shapes than rectangles.
+func (gp *Fpdf) LinearGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 uint8, x1, y1, x2, y2 float64) error {
+	stops := []GradientStop{{0, r1, g1, b1}, {1, r2, g2, b2}}
+	return gp.LinearGradientStops(x, y, w, h, stops, x1, y1, x2, y2)
+}
+
+// LinearGradientStops draws a rectangular area with a blending of multiple
+// colors along the gradient vector from (x1, y1) to (x2, y2). The offsets of
+// the stops must be in ascending order between 0.0 and 1.0. See
+// LinearGradient() for the other parameters.
+func (gp *Fpdf) LinearGradientStops(x, y, w, h float64, stops []GradientStop, x1, y1, x2, y2 float64) error {
+	return gp.gradient(x, y, w, h, shadingTypeAxial, stops, []float64{x1, y1, x2, y2})
+}
+
+// RadialGradient draws a rectangular area with a blending of one color to
+// another. The rectangle is of width w and height h. Its upper left corner is
+// positioned at point (x, y).
+//
+// Each color is specified with three component values, one each for red, green
+// and blue. The first color is specified by (r1, g1, b1) and the second color
+// by (r2, g2, b2).
+//
+// The blending is controlled with a point and a circle, both specified with
+// normalized coordinates in which the lower left corner of the rendered
+// rectangle is position (0, 0) and the upper right corner is (1, 1). Color 1
+// begins at the origin point specified by (x1, y1). Color 2 begins at the
+// circle specified by the center point (x2, y2) and radius r. The origin must
+// be within the circle to avoid rendering problems.
+//
+// The gradient is clipped by the current clipping path, use ClipEllipse(),
+// ClipPolygon(), etc to paint other shapes than rectangles.
+func (gp *Fpdf) RadialGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 uint8, x1, y1, x2, y2, r float64) error {
+	stops := []GradientStop{{0, r1, g1, b1}, {1, r2, g2, b2}}
+	return gp.RadialGradientStops(x, y, w, h, stops, x1, y1, x2, y2, r)
+}
+
+// RadialGradientStops draws a rectangular area with a blending of multiple
+// colors from the point (x1, y1) to the circle centered at (x2, y2) with
+// radius r. The offsets of the stops must be in ascending order between 0.0
+// and 1.0. See RadialGradient() for the other parameters.
+func (gp *Fpdf) RadialGradientStops(x, y, w, h float64, stops []GradientStop, x1, y1, x2, y2, r float64) error {
+	return gp.gradient(x, y, w, h, shadingTypeRadial, stops, []float64{x1, y1, 0, x2, y2, r})
+}
+
+func (gp *Fpdf) gradient(x, y, w, h float64, shadingType int, stops []GradientStop, coords []float64) error {
+	stops, err := normalizeGradientStops(stops)
+	if err != nil {
+		return err
+	}
+
+	gp.UnitsToPointsVar(&x, &y, &w, &h)
+	id, _ := gp.registerShading(newShadingObj(shadingType, coords, stops))
+	gp.markContent()
+	gp.currentContent().AppendStreamGradient(x, y, w, h, id)
+	return nil
+}
+
+// registerShading adds the shading and its function to the document. A copy
+// of the shading refers to the function, the shading itself may be shared
+// with other documents by a template.
+func (gp *Fpdf) registerShading(shading *ShadingObj) (string, int) {
+	if pid, id, ok := gp.pdfObjs.hasProcsetObj(shading); ok {
+		return pid, id
+	}
+
+	registered := *shading
+	registered.indexFunction = gp.addObj(shading.function)
+	return gp.addProcsetObj(&registered)
+}
+
+// normalizeGradientStops checks the order of the stops and extends the first
+// and last color to the start and the end of the gradient
+func normalizeGradientStops(stops []GradientStop) ([]GradientStop, error) {
+	if len(stops) < 2 {
+		return nil, errors.New("a gradient needs at least two stops")
+	}
+
+	for x := 0; x < len(stops); x++ {
+		if stops[x].Offset < 0 || stops[x].Offset > 1 {
+			return nil, fmt.Errorf("gradient stop offset (0.0 - 1.0) is out of range: %.3f", stops[x].Offset)
+		}
+		if x > 0 && stops[x].Offset < stops[x-1].Offset {
+			return nil, errors.New("gradient stop offsets must be in ascending order")
+		}
+	}
+
+	normalized := make([]GradientStop, 0, len(stops)+2)
+	if first := stops[0]; first.Offset > 0 {
+		first.Offset = 0
+		normalized = append(normalized, first)
+	}
+	normalized = append(normalized, stops...)
+	if last := stops[len(stops)-1]; last.Offset < 1 {
+		last.Offset = 1
+		normalized = append(normalized, last)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,179 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGradient(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+
+	if err := pdf.LinearGradient(10, 10, 80, 40, 255, 0, 0, 0, 0, 255, 0, 0, 1, 0); err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf.ClipEllipse(150, 50, 40, 20, false)
+	if err := pdf.RadialGradient(110, 30, 80, 40, 255, 255, 255, 0, 128, 0, 0.5, 0.5, 0.5, 0.5, 0.5); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.ClipEnd()
+
+	stops := []GradientStop{
+		{Offset: 0.2, R: 255},
+		{Offset: 0.5, G: 255},
+		{Offset: 1, B: 255},
+	}
+	if err := pdf.LinearGradientStops(10, 100, 180, 40, stops, 0, 0, 1, 0); err != nil {
+		t.Error(err)
+		return
+	}
+	// the same gradient is only added once
+	if err := pdf.LinearGradientStops(10, 150, 180, 40, stops, 0, 0, 1, 0); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.LinearGradientStops(10, 10, 10, 10, stops[:1], 0, 0, 1, 0); err == nil {
+		t.Error("a gradient with a single stop should fail")
+	}
+	if err := pdf.LinearGradientStops(10, 10, 10, 10, []GradientStop{{Offset: 1}, {Offset: 0}}, 0, 0, 1, 0); err == nil {
+		t.Error("a gradient with descending stops should fail")
+	}
+
+	if l := len(pdf.pdfObjs.allShadings()); l != 3 {
+		t.Errorf("expected 3 shadings, found %d", l)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, s := range []string{
+		"/Shading <<", "/ShadingType 2", "/ShadingType 3", "/FunctionType 3",
+		"/Bounds [0.20000 0.50000]", "/Encode [0 1 0 1 0 1]", " sh\n",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+
+	if err := pdf.WritePdf("./test/out/gradient.pdf"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGradientInTemplate(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+
+	tpl, err := pdf.CreateTemplate(func(tp *Fpdf) error {
+		return tp.RadialGradient(0, 0, 100, 100, 255, 0, 0, 0, 0, 0, 0.5, 0.5, 0.5, 0.5, 0.5)
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := tpl.Serialize()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	tpl, err = DeserializeTemplate(b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.UseTemplate(tpl); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err = pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	shadings := pdf.pdfObjs.allShadings()
+	if len(shadings) != 1 {
+		t.Errorf("expected 1 shading, found %d", len(shadings))
+		return
+	}
+	if pdf.pdfObjs.at(shadings[0].indexFunction) != shadings[0].function {
+		t.Error("the function of the shading should be added to the document")
+	}
+	if !bytes.Contains(b, []byte("/Shading <<\n/"+shadings[0].procsetIdentifier())) {
+		t.Error("the shading should be a resource of the template")
+	}
+}
+
+func TestGradientTemplateInDocuments(t *testing.T) {
+	tpl, err := CreateTemplate(Point{}, Unit_PT, func(tp *Fpdf) error {
+		return tp.LinearGradient(0, 0, 100, 100, 255, 0, 0, 0, 0, 255, 0, 0, 1, 0)
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the template is used in documents with different objects
+	index := tpl.(templateResources).Shadings()[0].indexFunction
+	for x := 0; x < 2; x++ {
+		pdf, err := New(PdfOptionPageSize(200, 200))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for y := 0; y <= x; y++ {
+			pdf.AddPage()
+		}
+		if err := pdf.UseTemplate(tpl); err != nil {
+			t.Error(err)
+			return
+		}
+		b, err := pdf.GetBytesPdfReturnErr()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		reader, err := newPdfReader(b)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, index, _ := pdf.pdfObjs.hasProcsetObj(tpl.(templateResources).Shadings()[0])
+		shading, _ := reader.resolve(pdfRef{Num: index + 1}).(pdfDict)
+		function, _ := reader.resolve(shading["Function"]).(pdfDict)
+		if function["FunctionType"] != 2 {
+			t.Errorf("document %d: the shading should refer to its function, found %v", x+1, function)
+		}
+	}
+
+	if tpl.(templateResources).Shadings()[0].indexFunction != index {
+		t.Error("the shading of the template should not refer to the documents")
+	}
+}
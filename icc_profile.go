@@ -0,0 +1,144 @@
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const iccProfileType = "ICCProfile"
+
+// ICCProfileObj an embedded ICC color profile
+type ICCProfileObj struct { //impl IObj
+	profile    []byte
+	components int
+	getRoot    func() *Fpdf
+}
+
+func (i *ICCProfileObj) init(funcGetRoot func() *Fpdf) {
+	i.getRoot = funcGetRoot
+}
+
+func (i *ICCProfileObj) getType() string {
+	return iccProfileType
+}
+
+func (i *ICCProfileObj) write(w io.Writer, objID int) error {
+	var buff bytes.Buffer
+	ww := zlib.NewWriter(&buff)
+	ww.Write(i.profile)
+	ww.Close()
+
+	data, err := encryptStream(i.getRoot().protection(), objID, buff.Bytes())
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /N %d\n", i.components)
+	io.WriteString(w, "  /Filter /FlateDecode\n")
+	fmt.Fprintf(w, "  /Length %d\n", len(data))
+	io.WriteString(w, ">>\n")
+	io.WriteString(w, "stream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
+	return nil
+}
+
+// srgbProfile returns an ICC version 2 display profile of the sRGB color
+// space (IEC 61966-2.1), with the primaries adapted to the D50 illuminant of
+// the profile connection space
+func srgbProfile() []byte {
+	xyz := func(x, y, z float64) []byte {
+		b := make([]byte, 20)
+		copy(b, "XYZ ")
+		for n, v := range []float64{x, y, z} {
+			binary.BigEndian.PutUint32(b[8+n*4:], uint32(int32(math.Round(v*65536))))
+		}
+		return b
+	}
+
+	// the transfer function of sRGB sampled in 1024 steps
+	const samples = 1024
+	trc := make([]byte, 12+samples*2)
+	copy(trc, "curv")
+	binary.BigEndian.PutUint32(trc[8:], samples)
+	for n := 0; n < samples; n++ {
+		v := float64(n) / (samples - 1)
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.BigEndian.PutUint16(trc[12+n*2:], uint16(math.Round(v*65535)))
+	}
+
+	description := "sRGB IEC61966-2.1"
+	desc := make([]byte, 12+len(description)+1+8+3+67)
+	copy(desc, "desc")
+	binary.BigEndian.PutUint32(desc[8:], uint32(len(description)+1))
+	copy(desc[12:], description)
+
+	copyright := "No copyright, use freely"
+	cprt := make([]byte, 8+len(copyright)+1)
+	copy(cprt, "text")
+	copy(cprt[8:], copyright)
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", desc},
+		{"cprt", cprt},
+		{"wtpt", xyz(0.9642, 1, 0.8249)},
+		{"rXYZ", xyz(0.4360747, 0.2225045, 0.0139322)},
+		{"gXYZ", xyz(0.3850649, 0.7168786, 0.0971045)},
+		{"bXYZ", xyz(0.1430804, 0.0606169, 0.7141733)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	// the data of the tags follows the header and the tag table, the
+	// transfer functions share their data
+	table := make([]byte, 4+len(tags)*12)
+	binary.BigEndian.PutUint32(table, uint32(len(tags)))
+	var data []byte
+	offset := 128 + len(table)
+	trcOffset := 0
+	for n, tag := range tags {
+		entry := table[4+n*12:]
+		copy(entry, tag.signature)
+		if tag.signature[1:] == "TRC" && trcOffset > 0 {
+			binary.BigEndian.PutUint32(entry[4:], uint32(trcOffset))
+		} else {
+			if tag.signature[1:] == "TRC" {
+				trcOffset = offset + len(data)
+			}
+			binary.BigEndian.PutUint32(entry[4:], uint32(offset+len(data)))
+			data = append(data, tag.data...)
+			for len(data)%4 != 0 {
+				data = append(data, 0)
+			}
+		}
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(tag.data)))
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header, uint32(128+len(table)+len(data)))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000)
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for n, v := range []uint16{2020, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+n*2:], v)
+	}
+	copy(header[36:], "acsp")
+	copy(header[68:], xyz(0.9642, 1, 0.8249)[8:])
+
+	profile := append(header, table...)
+	return append(profile, data...)
+}
@@ -0,0 +1,171 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// goImageFormat the format name of images created from an image.Image
+const goImageFormat = "image"
+
+// NewImageObjFromGoImage creates an image object from the samples of img,
+// without encoding it. Gray, CMYK and paletted images keep their color
+// space, any other image is written as RGB with a soft mask from its alpha
+// channel if it is not opaque. Images with the same samples have the same
+// identifier.
+func NewImageObjFromGoImage(img image.Image) (*ImageObj, error) {
+	info, err := goImageInfo(img)
+	if err != nil {
+		return nil, err
+	}
+	info.formatName = goImageFormat
+
+	sum := sha1.New()
+	fmt.Fprintf(sum, "%s %d %d %x %x\n", info.colspace, info.w, info.h, info.pal, info.trns)
+	sum.Write(info.data)
+	sum.Write(info.smask)
+
+	imgobj := &ImageObj{
+		rawImgReader: bytes.NewReader(nil),
+		imginfo:      info,
+		imageid:      fmt.Sprintf("%x", sum.Sum(nil)),
+	}
+	imgobj.procsetid = fmt.Sprintf("I%s", imgobj.imageid)
+	return imgobj, nil
+}
+
+// ImageFromGoImage draws img, writing its samples without encoding it.
+// Drawing the same image again reuses its image object.
+func (gp *Fpdf) ImageFromGoImage(img image.Image, x float64, y float64, rect Rect) error {
+	imgobj, err := NewImageObjFromGoImage(img)
+	if err != nil {
+		return err
+	}
+	return gp.ImageByObj(imgobj, x, y, rect)
+}
+
+// goImageInfo returns the image info of the samples of img
+func goImageInfo(img image.Image) (imgInfo, error) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 {
+		return imgInfo{}, errors.New("image is empty")
+	}
+	info := imgInfo{
+		w:                w,
+		h:                h,
+		bitsPerComponent: "8",
+	}
+
+	if m, ok := img.(*image.Paletted); ok {
+		if pal, trns, ok := palette(m.Palette); ok {
+			info.colspace = "Indexed"
+			info.pal = pal
+			info.trns = trns
+			samples := make([]byte, 0, w*h)
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				samples = append(samples, m.Pix[m.PixOffset(bounds.Min.X, y):m.PixOffset(bounds.Max.X, y)]...)
+			}
+			return info, info.setSamples(samples, nil, 1)
+		}
+	}
+
+	var samples, alpha []byte
+	colors := 1
+	opaque := true
+	switch m := img.(type) {
+	case *image.Gray, *image.Gray16:
+		info.colspace = "DeviceGray"
+		samples = make([]byte, 0, w*h)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				samples = append(samples, color.GrayModel.Convert(m.At(x, y)).(color.Gray).Y)
+			}
+		}
+	case *image.CMYK:
+		// CMYK images are written with an inverted decode array
+		info.colspace = "DeviceCMYK"
+		colors = 4
+		samples = make([]byte, 0, 4*w*h)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := m.CMYKAt(x, y)
+				samples = append(samples, 255-c.C, 255-c.M, 255-c.Y, 255-c.K)
+			}
+		}
+	default:
+		info.colspace = "DeviceRGB"
+		colors = 3
+		samples = make([]byte, 0, 3*w*h)
+		alpha = make([]byte, 0, w*h)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				samples = append(samples, c.R, c.G, c.B)
+				alpha = append(alpha, c.A)
+				opaque = opaque && c.A == 255
+			}
+		}
+	}
+	if opaque {
+		alpha = nil
+	}
+	return info, info.setSamples(samples, alpha, colors)
+}
+
+// palette returns the RGB components of the colors of p and the index of
+// its transparent color, false if p has more than one transparent or a
+// translucent color
+func palette(p color.Palette) ([]byte, []byte, bool) {
+	var pal, trns []byte
+	for n, c := range p {
+		nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+		switch {
+		case nrgba.A == 0 && trns == nil:
+			trns = []byte{byte(n)}
+		case nrgba.A != 255:
+			return nil, nil, false
+		}
+		pal = append(pal, nrgba.R, nrgba.G, nrgba.B)
+	}
+	return pal, trns, len(pal) > 0
+}
+
+// setSamples sets the data of the image and of its soft mask from the
+// samples of its rows and their alpha values, nil for an opaque image. Every
+// row starts with the PNG filter type None, so they are decoded with the
+// predictor of PNG images and their soft masks.
+func (info *imgInfo) setSamples(samples, alpha []byte, colors int) error {
+	bpc := 8
+	fmt.Sscan(info.bitsPerComponent, &bpc)
+	info.filter = "FlateDecode"
+	info.decodeParms = fmt.Sprintf("/Predictor 15 /Colors %d /BitsPerComponent %d /Columns %d", colors, bpc, info.w)
+
+	var err error
+	if info.data, err = compress(pngRows(samples, info.h)); err != nil {
+		return err
+	}
+	info.smask = nil
+	if alpha != nil {
+		if info.smask, err = compress(pngRows(alpha, info.h)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pngRows returns the rows of data, each preceded by the PNG filter type
+// None
+func pngRows(data []byte, rows int) []byte {
+	rowLen := len(data) / rows
+	b := make([]byte, 0, len(data)+rows)
+	for n := 0; n < rows; n++ {
+		b = append(b, 0)
+		b = append(b, data[n*rowLen:(n+1)*rowLen]...)
+	}
+	return b
+}
@@ -0,0 +1,96 @@
+package gofpdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageFromGoImage(t *testing.T) {
+	chart := image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	chart.Set(10, 10, color.NRGBA{R: 255, A: 255})
+	chart.Set(13, 11, color.NRGBA{G: 128, B: 64, A: 100})
+	gray := image.NewGray(image.Rect(0, 0, 2, 2))
+	gray.SetGray(1, 1, color.Gray{Y: 200})
+	cmyk := image.NewCMYK(image.Rect(0, 0, 1, 1))
+	cmyk.SetCMYK(0, 0, color.CMYK{C: 255, K: 10})
+
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	for _, img := range []image.Image{chart, chart, gray, cmyk} {
+		if err := pdf.ImageFromGoImage(img, 10, 10, Rect{W: 40, H: 20}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the same samples are written once
+	copied := image.NewNRGBA(chart.Bounds())
+	copy(copied.Pix, chart.Pix)
+	if err := pdf.ImageFromGoImage(copied, 10, 50, Rect{W: 40, H: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.ImageFromGoImage(image.NewRGBA(image.Rectangle{}), 10, 10, Rect{W: 10, H: 10}); err == nil {
+		t.Error("an empty image should fail")
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xobjects := reader.resolve(reader.resolve(pages[0]["Resources"]).(pdfDict)["XObject"]).(pdfDict)
+	if len(xobjects) != 3 {
+		t.Fatalf("expected 3 images, found %d", len(xobjects))
+	}
+
+	expected := map[string]struct {
+		samples []byte
+		alpha   []byte
+	}{
+		"DeviceRGB": {
+			[]byte{255, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 128, 64},
+			[]byte{255, 0, 0, 0, 0, 0, 0, 100},
+		},
+		"DeviceGray": {[]byte{0, 0, 0, 200}, nil},
+		"DeviceCMYK": {[]byte{0, 255, 255, 245}, nil},
+	}
+	for _, ref := range xobjects {
+		img := reader.resolve(ref).(pdfStream)
+		colorSpace := string(img.Dict["ColorSpace"].(pdfName))
+		e, ok := expected[colorSpace]
+		if !ok {
+			t.Errorf("unexpected color space %s", colorSpace)
+			continue
+		}
+		samples, err := reader.decodeStream(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(samples, e.samples) {
+			t.Errorf("unexpected %s samples %v", colorSpace, samples)
+		}
+		if e.alpha == nil {
+			if img.Dict["SMask"] != nil {
+				t.Errorf("the opaque %s image should have no soft mask", colorSpace)
+			}
+			continue
+		}
+		smask, err := reader.decodeStream(reader.resolve(img.Dict["SMask"]).(pdfStream))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(smask, e.alpha) {
+			t.Errorf("unexpected soft mask %v", smask)
+		}
+	}
+}
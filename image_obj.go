@@ -90,18 +90,14 @@ func (i *ImageObj) write(w io.Writer, objID int) error {
 		return err
 	}
 
-	fmt.Fprintf(w, "/Length %d\n>>\n", len(i.imginfo.data)) // /Length 62303>>\n
-	io.WriteString(w, "stream\n")
-	if i.protection() != nil {
-		tmp, err := rc4Cip(i.protection().objectkey(objID), i.imginfo.data)
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		io.WriteString(w, "\n")
-	} else {
-		w.Write(i.imginfo.data)
+	data, err := encryptStream(i.protection(), objID, i.imginfo.data)
+	if err != nil {
+		return err
 	}
+
+	fmt.Fprintf(w, "/Length %d\n>>\n", len(data)) // /Length 62303>>\n
+	io.WriteString(w, "stream\n")
+	w.Write(data)
 	io.WriteString(w, "\nendstream\n")
 
 	return nil
@@ -179,13 +175,15 @@ func (i *ImageObj) GetRect() *Rect {
 //GetRect get rect of img
 func (i *ImageObj) getRect() (*Rect, error) {
 
-	i.rawImgReader.Seek(0, 0)
-	m, _, err := image.Decode(i.rawImgReader)
-	if err != nil {
-		return nil, err
+	imageRect := image.Rect(0, 0, i.imginfo.w, i.imginfo.h)
+	if i.imginfo.w == 0 {
+		i.rawImgReader.Seek(0, 0)
+		m, _, err := image.Decode(i.rawImgReader)
+		if err != nil {
+			return nil, err
+		}
+		imageRect = m.Bounds()
 	}
-
-	imageRect := m.Bounds()
 	k := 1
 	w := -128 //init
 	h := -128 //init
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/gif"
 	"io"
 	"io/ioutil"
 	"strings"
@@ -83,6 +84,26 @@ func parseImgByPath(path string) (imgInfo, error) {
 func parseImg(raw *bytes.Reader) (imgInfo, error) {
 	//fmt.Printf("----------\n")
 	var info imgInfo
+	raw.Seek(0, 0)
+	header := make([]byte, 12)
+	n, _ := io.ReadFull(raw, header)
+	header = header[:n]
+
+	// the formats the image package does not know
+	var parse func(*bytes.Reader, *imgInfo) error
+	switch {
+	case bytes.HasPrefix(header, tiffLittleEndian) || bytes.HasPrefix(header, tiffBigEndian):
+		info.formatName, parse = "tiff", parseTiff
+	case bytes.HasPrefix(header, bmpMagicNumber):
+		info.formatName, parse = "bmp", parseBmp
+	case isWebp(header):
+		info.formatName, parse = "webp", parseWebp
+	}
+	if parse != nil {
+		err := parse(raw, &info)
+		return info, err
+	}
+
 	raw.Seek(0, 0)
 	imgConfig, formatname, err := image.DecodeConfig(raw)
 	if err != nil {
@@ -106,6 +127,11 @@ func parseImg(raw *bytes.Reader) (imgInfo, error) {
 		if err != nil {
 			return info, err
 		}
+	case "gif":
+		err = parseGif(raw, &info)
+		if err != nil {
+			return info, err
+		}
 	}
 
 	//fmt.Printf("%#v\n", info)
@@ -113,6 +139,23 @@ func parseImg(raw *bytes.Reader) (imgInfo, error) {
 	return info, nil
 }
 
+// parseGif sets the image info of the first frame of a GIF image, its
+// transparent color is masked
+func parseGif(raw *bytes.Reader, info *imgInfo) error {
+	raw.Seek(0, 0)
+	img, err := gif.Decode(raw)
+	if err != nil {
+		return err
+	}
+	decoded, err := goImageInfo(img)
+	if err != nil {
+		return err
+	}
+	decoded.formatName = info.formatName
+	*info = decoded
+	return nil
+}
+
 func parseImgJpg(info *imgInfo, imgConfig image.Config) error {
 	if imgConfig.ColorModel == color.YCbCrModel {
 		info.colspace = "DeviceRGB"
@@ -0,0 +1,272 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var bmpMagicNumber = []byte("BM")
+
+// compressions of BMP images
+const (
+	bmpRGB            = 0
+	bmpRLE8           = 1
+	bmpRLE4           = 2
+	bmpBitFields      = 3
+	bmpAlphaBitFields = 6
+)
+
+func parseBmp(raw *bytes.Reader, info *imgInfo) error {
+	raw.Seek(0, 0)
+	data, err := ioutil.ReadAll(raw)
+	if err != nil {
+		return err
+	}
+	if len(data) < 26 || !bytes.HasPrefix(data, bmpMagicNumber) {
+		return errors.New("invalid BMP header")
+	}
+	pixels := int(binary.LittleEndian.Uint32(data[10:]))
+	headerSize := int(binary.LittleEndian.Uint32(data[14:]))
+	if 14+headerSize > len(data) || pixels > len(data) {
+		return errors.New("invalid BMP header")
+	}
+	header := data[14 : 14+headerSize]
+
+	var w, h, bpp, compression, colorsUsed int
+	paletteEntry := 4
+	if headerSize == 12 {
+		// OS/2 bitmap core header
+		w = int(binary.LittleEndian.Uint16(header[4:]))
+		h = int(binary.LittleEndian.Uint16(header[6:]))
+		bpp = int(binary.LittleEndian.Uint16(header[10:]))
+		paletteEntry = 3
+	} else if headerSize >= 40 {
+		w = int(int32(binary.LittleEndian.Uint32(header[4:])))
+		h = int(int32(binary.LittleEndian.Uint32(header[8:])))
+		bpp = int(binary.LittleEndian.Uint16(header[14:]))
+		compression = int(binary.LittleEndian.Uint32(header[16:]))
+		colorsUsed = int(binary.LittleEndian.Uint32(header[32:]))
+	} else {
+		return fmt.Errorf("BMP header of %d bytes is not supported", headerSize)
+	}
+	topDown := h < 0
+	if topDown {
+		h = -h
+	}
+	if w <= 0 || h <= 0 || bpp == 0 {
+		return errors.New("invalid BMP image size")
+	}
+	info.w = w
+	info.h = h
+	info.bitsPerComponent = "8"
+
+	// the masks of the components follow the header of version 1 and are
+	// part of the later versions
+	var masks []uint32
+	tables := 14 + headerSize
+	if compression == bmpBitFields || compression == bmpAlphaBitFields {
+		n := 3
+		if compression == bmpAlphaBitFields {
+			n = 4
+		}
+		if headerSize >= 56 {
+			n = 4
+		}
+		start := 14 + 40
+		if headerSize == 40 {
+			tables += n * 4
+		}
+		if start+n*4 > len(data) {
+			return errors.New("invalid BMP color masks")
+		}
+		for x := 0; x < n; x++ {
+			masks = append(masks, binary.LittleEndian.Uint32(data[start+x*4:]))
+		}
+	}
+
+	if bpp <= 8 {
+		if colorsUsed == 0 || colorsUsed > 1<<uint(bpp) {
+			colorsUsed = 1 << uint(bpp)
+		}
+		if tables+colorsUsed*paletteEntry > len(data) {
+			return errors.New("invalid BMP palette")
+		}
+		// the palette has all the colors of the bits per pixel, so every
+		// index refers to a color
+		info.colspace = "Indexed"
+		info.pal = make([]byte, 3<<uint(bpp))
+		for x := 0; x < colorsUsed; x++ {
+			entry := data[tables+x*paletteEntry:]
+			info.pal[x*3], info.pal[x*3+1], info.pal[x*3+2] = entry[2], entry[1], entry[0]
+		}
+	}
+
+	// the rows of the pixels, from top to bottom
+	var rows [][]byte
+	switch compression {
+	case bmpRLE8, bmpRLE4:
+		if bpp != 8 && compression == bmpRLE8 || bpp != 4 && compression == bmpRLE4 {
+			return errors.New("invalid BMP run length encoding")
+		}
+		indexes, err := bmpRLEDecode(data[pixels:], w, h, compression == bmpRLE4)
+		if err != nil {
+			return err
+		}
+		bpp = 8
+		for y := 0; y < h; y++ {
+			rows = append(rows, indexes[y*w:(y+1)*w])
+		}
+	case bmpRGB, bmpBitFields, bmpAlphaBitFields:
+		// the rows are divided to not overflow with crafted sizes
+		stride := (w*bpp + 31) / 32 * 4
+		if (len(data)-pixels)/stride < h {
+			return errors.New("BMP image is truncated")
+		}
+		for y := 0; y < h; y++ {
+			rows = append(rows, data[pixels+y*stride:pixels+y*stride+(w*bpp+7)/8])
+		}
+	default:
+		return fmt.Errorf("BMP compression %d is not supported", compression)
+	}
+	if !topDown {
+		for x := 0; x < h/2; x++ {
+			rows[x], rows[h-1-x] = rows[h-1-x], rows[x]
+		}
+	}
+
+	var samples, alpha []byte
+	switch bpp {
+	case 1, 2, 4, 8:
+		info.bitsPerComponent = fmt.Sprintf("%d", bpp)
+		for _, row := range rows {
+			samples = append(samples, row...)
+		}
+		return info.setSamples(samples, nil, 1)
+	case 16:
+		if masks == nil {
+			masks = []uint32{0x7c00, 0x03e0, 0x001f}
+		}
+	case 24:
+		if masks != nil {
+			return errors.New("invalid BMP color masks")
+		}
+	case 32:
+		if masks == nil {
+			masks = []uint32{0xff0000, 0xff00, 0xff}
+		}
+	default:
+		return fmt.Errorf("BMP images with %d bits per pixel are not supported", bpp)
+	}
+
+	info.colspace = "DeviceRGB"
+	samples = make([]byte, 0, 3*w*h)
+	opaque := true
+	if len(masks) > 3 && masks[3] != 0 {
+		alpha = make([]byte, 0, w*h)
+	}
+	for _, row := range rows {
+		for x := 0; x < w; x++ {
+			if bpp == 24 {
+				samples = append(samples, row[x*3+2], row[x*3+1], row[x*3])
+				continue
+			}
+			var pixel uint32
+			if bpp == 16 {
+				pixel = uint32(binary.LittleEndian.Uint16(row[x*2:]))
+			} else {
+				pixel = binary.LittleEndian.Uint32(row[x*4:])
+			}
+			samples = append(samples, bmpComponent(pixel, masks[0]), bmpComponent(pixel, masks[1]), bmpComponent(pixel, masks[2]))
+			if alpha != nil {
+				a := bmpComponent(pixel, masks[3])
+				alpha = append(alpha, a)
+				opaque = opaque && a == 255
+			}
+		}
+	}
+	if opaque {
+		alpha = nil
+	}
+	return info.setSamples(samples, alpha, 3)
+}
+
+// bmpComponent returns the component of the pixel selected by the mask,
+// scaled to 8 bits
+func bmpComponent(pixel, mask uint32) byte {
+	if mask == 0 {
+		return 0
+	}
+	shift := uint(0)
+	for mask>>shift&1 == 0 {
+		shift++
+	}
+	max := mask >> shift
+	return byte((pixel & mask >> shift) * 255 / max)
+}
+
+// bmpRLEDecode decodes the run length encoding of BMP images, returning the
+// palette index of every pixel from the bottom row to the top
+func bmpRLEDecode(data []byte, w, h int, rle4 bool) ([]byte, error) {
+	// a run of 2 bytes has at most 255 pixels, the size is checked before
+	// the pixels are allocated
+	if (len(data)/2*255)/w < h {
+		return nil, errors.New("BMP image is truncated")
+	}
+	indexes := make([]byte, w*h)
+	x, y := 0, 0
+	set := func(index byte) {
+		if x < w && y < h {
+			indexes[y*w+x] = index
+		}
+		x++
+	}
+	for pos := 0; pos+1 < len(data); {
+		n, v := int(data[pos]), data[pos+1]
+		pos += 2
+		if n > 0 {
+			// a run of n pixels
+			for i := 0; i < n; i++ {
+				if rle4 {
+					set(v >> uint(4*(1-i%2)) & 0x0f)
+				} else {
+					set(v)
+				}
+			}
+			continue
+		}
+
+		switch v {
+		case 0:
+			x, y = 0, y+1
+		case 1:
+			return indexes, nil
+		case 2:
+			if pos+1 >= len(data) {
+				return nil, errors.New("invalid BMP run length encoding")
+			}
+			x, y = x+int(data[pos]), y+int(data[pos+1])
+			pos += 2
+		default:
+			// v pixels follow, padded to 16 bits
+			size := int(v)
+			if rle4 {
+				size = (size + 1) / 2
+			}
+			if pos+size > len(data) {
+				return nil, errors.New("invalid BMP run length encoding")
+			}
+			for i := 0; i < int(v); i++ {
+				if rle4 {
+					set(data[pos+i/2] >> uint(4*(1-i%2)) & 0x0f)
+				} else {
+					set(data[pos+i])
+				}
+			}
+			pos += (size + 1) / 2 * 2
+		}
+	}
+	return indexes, nil
+}
@@ -0,0 +1,320 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"sort"
+	"testing"
+)
+
+// imagePixel returns the color of the pixel of an image with a flate
+// compressed data
+func imagePixel(t *testing.T, info imgInfo, x, y int) color.NRGBA {
+	rows := func(data []byte) []byte {
+		decoded, err := flateDecode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rowLen := len(decoded)/info.h - 1
+		return decoded[y*(rowLen+1)+1 : (y+1)*(rowLen+1)]
+	}
+
+	bpc := 8
+	fmt.Sscan(info.bitsPerComponent, &bpc)
+	row := rows(info.data)
+	sample := func(n int) byte {
+		bit := n * bpc
+		return row[bit/8] >> uint(8-bpc-bit%8) & (1<<uint(bpc) - 1)
+	}
+
+	c := color.NRGBA{A: 255}
+	switch info.colspace {
+	case "DeviceGray":
+		v := byte(int(sample(x)) * 255 / (1<<uint(bpc) - 1))
+		c.R, c.G, c.B = v, v, v
+	case "Indexed":
+		i := int(sample(x))
+		c.R, c.G, c.B = info.pal[i*3], info.pal[i*3+1], info.pal[i*3+2]
+		if len(info.trns) > 0 && int(info.trns[0]) == i {
+			c.A = 0
+		}
+	case "DeviceRGB":
+		c.R, c.G, c.B = sample(x*3), sample(x*3+1), sample(x*3+2)
+	default:
+		t.Fatalf("unexpected color space %s", info.colspace)
+	}
+	if len(info.smask) > 0 {
+		c.A = rows(info.smask)[x]
+	}
+	return c
+}
+
+func TestImageFormats(t *testing.T) {
+	type pixel struct {
+		x, y  int
+		color color.NRGBA
+	}
+	for _, tt := range []struct {
+		path     string
+		format   string
+		colspace string
+		w, h     int
+		pixels   []pixel
+	}{
+		{"test/res/blue-purple-pink.lossy.webp", "webp", "DeviceRGB", 150, 100, []pixel{
+			{0, 0, color.NRGBA{29, 32, 29, 255}},
+			{75, 50, color.NRGBA{168, 154, 164, 255}},
+		}},
+		{"test/res/yellow_rose.lossy-with-alpha.webp", "webp", "DeviceRGB", 400, 301, []pixel{
+			{0, 0, color.NRGBA{0, 0, 0, 0}},
+			{200, 150, color.NRGBA{149, 81, 16, 255}},
+		}},
+		{"test/res/gopher-doc.1bpp.lossless.webp", "webp", "DeviceRGB", 75, 100, []pixel{
+			{0, 0, color.NRGBA{255, 255, 255, 255}},
+		}},
+		{"test/res/bw-packbits.tiff", "tiff", "DeviceGray", 153, 55, []pixel{
+			{0, 0, color.NRGBA{255, 255, 255, 255}},
+		}},
+		{"test/res/no_compress.tiff", "tiff", "DeviceRGB", 16, 15, []pixel{
+			{0, 0, color.NRGBA{255, 255, 255, 0}},
+			{8, 7, color.NRGBA{213, 186, 155, 255}},
+		}},
+		{"test/res/yellow_rose-small.bmp", "bmp", "DeviceRGB", 16, 12, []pixel{
+			{0, 0, color.NRGBA{0, 0, 0, 255}},
+			{8, 6, color.NRGBA{141, 69, 1, 255}},
+		}},
+		{"test/res/yellow_rose-small-v5.bmp", "bmp", "DeviceRGB", 16, 12, []pixel{
+			{0, 0, color.NRGBA{0, 0, 0, 0}},
+			{8, 6, color.NRGBA{141, 69, 1, 255}},
+		}},
+		{"test/res/triangle-001.gif", "gif", "Indexed", 256, 128, []pixel{
+			{0, 0, color.NRGBA{0, 0, 0, 0}},
+			{128, 64, color.NRGBA{191, 0, 2, 255}},
+		}},
+	} {
+		info, err := parseImgByPath(tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if info.formatName != tt.format || info.colspace != tt.colspace || info.w != tt.w || info.h != tt.h {
+			t.Errorf("%s: unexpected %s %s image of %dx%d", tt.path, info.formatName, info.colspace, info.w, info.h)
+			continue
+		}
+		for _, p := range tt.pixels {
+			if c := imagePixel(t, info, p.x, p.y); c != p.color {
+				t.Errorf("%s: pixel %d,%d is %v, expected %v", tt.path, p.x, p.y, c, p.color)
+			}
+		}
+	}
+}
+
+func TestImageTiffGroup4(t *testing.T) {
+	data, err := ioutil.ReadFile("test/res/bw-gopher_ccittGroup4.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseImg(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the compressed strip is written as is
+	if info.filter != "CCITTFaxDecode" || info.decodeParms != "/K -1 /Columns 153 /Rows 55" || !bytes.Contains(data, info.data) {
+		t.Errorf("unexpected %s image %s", info.filter, info.decodeParms)
+	}
+
+	// the strips of a white and a black row, coded as the vertical mode and
+	// as the horizontal mode with runs of 0 white and 8 black pixels
+	data = tiffFile(map[uint16][]uint32{
+		tiffImageWidth:      {8},
+		tiffImageLength:     {2},
+		tiffCompression:     {tiffCompressionGroup4},
+		tiffRowsPerStrip:    {1},
+		tiffStripByteCounts: {1, 3},
+	}, []byte{0x80, 0x26, 0xa2, 0x80})
+	if info, err = parseImg(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	white, black := color.NRGBA{255, 255, 255, 255}, color.NRGBA{0, 0, 0, 255}
+	if info.filter != "FlateDecode" || imagePixel(t, info, 7, 0) != white || imagePixel(t, info, 0, 1) != black {
+		t.Errorf("unexpected %s image of several strips", info.filter)
+	}
+
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	for _, path := range []string{"test/res/bw-gopher_ccittGroup4.tiff", "test/res/yellow_rose.lossy-with-alpha.webp", "test/res/yellow_rose-small.bmp"} {
+		if err := pdf.Image(path, 10, 10, Rect{W: 100, H: 50}); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+	if _, err := pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tiffFile returns a little endian TIFF image with one strip, unless the
+// strip byte counts of the fields split it
+func tiffFile(fields map[uint16][]uint32, strip []byte) []byte {
+	if _, ok := fields[tiffStripByteCounts]; !ok {
+		fields[tiffStripByteCounts] = []uint32{uint32(len(strip))}
+	}
+	offset := uint32(8)
+	fields[tiffStripOffsets] = nil
+	for _, count := range fields[tiffStripByteCounts] {
+		fields[tiffStripOffsets] = append(fields[tiffStripOffsets], offset)
+		offset += count
+	}
+	tags := make([]int, 0, len(fields))
+	for tag := range fields {
+		tags = append(tags, int(tag))
+	}
+	sort.Ints(tags)
+
+	b := append([]byte("II*\x00\x00\x00\x00\x00"), strip...)
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(b)))
+	ifd := make([]byte, 2+len(tags)*12+4)
+	binary.LittleEndian.PutUint16(ifd, uint16(len(tags)))
+	var values []byte
+	for n, tag := range tags {
+		entry := ifd[2+n*12:]
+		field := fields[uint16(tag)]
+		binary.LittleEndian.PutUint16(entry, uint16(tag))
+		binary.LittleEndian.PutUint16(entry[2:], 4)
+		binary.LittleEndian.PutUint32(entry[4:], uint32(len(field)))
+		if len(field) == 1 {
+			binary.LittleEndian.PutUint32(entry[8:], field[0])
+			continue
+		}
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(b)+len(ifd)+len(values)))
+		for _, v := range field {
+			values = append(values, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+		}
+	}
+	return append(append(b, ifd...), values...)
+}
+
+func TestImageTiffCMYK(t *testing.T) {
+	data := tiffFile(map[uint16][]uint32{
+		tiffImageWidth:                {2},
+		tiffImageLength:               {1},
+		tiffBitsPerSample:             {8, 8, 8, 8},
+		tiffSamplesPerPixel:           {4},
+		tiffPhotometricInterpretation: {tiffSeparated},
+		tiffPredictor:                 {2},
+	}, []byte{10, 20, 30, 40, 5, 5, 5, 5})
+	info, err := parseImg(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := flateDecode(info.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// CMYK images are written with an inverted decode array
+	expected := []byte{0, 245, 235, 225, 215, 240, 230, 220, 210}
+	if info.colspace != "DeviceCMYK" || !bytes.Equal(decoded, expected) {
+		t.Errorf("unexpected %s samples %v", info.colspace, decoded)
+	}
+
+	strip := []byte{1, 2, 3, 4}
+	for name, data := range map[string][]byte{
+		"YCbCr":  tiffFile(map[uint16][]uint32{tiffImageWidth: {1}, tiffImageLength: {1}, tiffBitsPerSample: {8, 8, 8}, tiffSamplesPerPixel: {3}, tiffPhotometricInterpretation: {6}}, strip),
+		"JPEG":   tiffFile(map[uint16][]uint32{tiffImageWidth: {1}, tiffImageLength: {1}, tiffBitsPerSample: {8}, tiffCompression: {7}, tiffPhotometricInterpretation: {1}}, strip),
+		"size":   tiffFile(map[uint16][]uint32{tiffImageWidth: {0}, tiffImageLength: {1}, tiffBitsPerSample: {8}, tiffPhotometricInterpretation: {1}}, strip),
+		"header": []byte("MM\x00*\x00\x00\x00"),
+	} {
+		if _, err := parseImg(bytes.NewReader(data)); err == nil {
+			t.Errorf("the %s image should fail", name)
+		}
+	}
+}
+
+// bmpFile returns a BMP image with a header of version 1 and the pixels
+func bmpFile(w, h int32, bpp uint16, compression uint32, pixels []byte) []byte {
+	header := make([]byte, 54)
+	copy(header, "BM")
+	binary.LittleEndian.PutUint32(header[2:], uint32(54+len(pixels)))
+	binary.LittleEndian.PutUint32(header[10:], 54)
+	binary.LittleEndian.PutUint32(header[14:], 40)
+	binary.LittleEndian.PutUint32(header[18:], uint32(w))
+	binary.LittleEndian.PutUint32(header[22:], uint32(h))
+	binary.LittleEndian.PutUint16(header[26:], 1)
+	binary.LittleEndian.PutUint16(header[28:], bpp)
+	binary.LittleEndian.PutUint32(header[30:], compression)
+	return append(header, pixels...)
+}
+
+func TestImageCraftedSizes(t *testing.T) {
+	// a lossy image of 65537x65536 pixels with alpha values, the raw values
+	// of the ALPH chunk are less than a row after the size overflows
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x10\x00\x00\x00\x00\x00\x01\xff\xff\x00")
+	alpha := make([]byte, 1+65537*65536%(1<<32))
+	alpha[0] = 1 << 2
+	webp = append(webp, "ALPH"...)
+	webp = append(webp, byte(len(alpha)), byte(len(alpha)>>8), byte(len(alpha)>>16), byte(len(alpha)>>24))
+	webp = append(webp, alpha...)
+
+	huge := []uint32{0x7fffffff}
+	for name, data := range map[string][]byte{
+		"TIFF": tiffFile(map[uint16][]uint32{tiffImageWidth: huge, tiffImageLength: huge, tiffBitsPerSample: {8}, tiffPhotometricInterpretation: {1}}, []byte{1, 2, 3, 4}),
+		"TIFF tiles": tiffFile(map[uint16][]uint32{
+			tiffImageWidth: huge, tiffImageLength: huge, tiffBitsPerSample: {8}, tiffPhotometricInterpretation: {1},
+			tiffTileWidth: {1}, tiffTileLength: {1}, tiffTileOffsets: {8}, tiffTileByteCounts: {4},
+		}, []byte{1, 2, 3, 4}),
+		"TIFF samples":  tiffFile(map[uint16][]uint32{tiffImageWidth: {1}, tiffImageLength: {1}, tiffBitsPerSample: {8}, tiffSamplesPerPixel: {0xffff}, tiffPhotometricInterpretation: {1}}, []byte{1, 2, 3, 4}),
+		"BMP RLE8":      bmpFile(0x7fffffff, 0x7fffffff, 8, bmpRLE8, make([]byte, 1024+12)),
+		"BMP RGB":       bmpFile(0x7fffffff, 0x7fffffff, 32, bmpRGB, make([]byte, 12)),
+		"BMP no pixels": bmpFile(1, 1, 0, bmpRGB, nil),
+		"WebP":          webp,
+	} {
+		if _, err := parseImg(bytes.NewReader(data)); err == nil {
+			t.Errorf("the %s image should fail", name)
+		}
+	}
+}
+
+func TestImageBmpRLE8(t *testing.T) {
+	pixels := []byte{
+		4, 1, // the bottom row
+		0, 0,
+		0, 3, 0, 1, 0, 0, // the top row
+		1, 1,
+		0, 1,
+	}
+	header := make([]byte, 54)
+	copy(header, "BM")
+	binary.LittleEndian.PutUint32(header[2:], uint32(54+8+len(pixels)))
+	binary.LittleEndian.PutUint32(header[10:], 54+8)
+	binary.LittleEndian.PutUint32(header[14:], 40)
+	binary.LittleEndian.PutUint32(header[18:], 4)
+	binary.LittleEndian.PutUint32(header[22:], 2)
+	binary.LittleEndian.PutUint16(header[26:], 1)
+	binary.LittleEndian.PutUint16(header[28:], 8)
+	binary.LittleEndian.PutUint32(header[30:], bmpRLE8)
+	binary.LittleEndian.PutUint32(header[46:], 2)
+	palette := []byte{0, 0, 255, 0, 255, 0, 0, 0}
+	data := append(append(header, palette...), pixels...)
+
+	info, err := parseImg(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+	expected := [][]color.NRGBA{{red, blue, red, blue}, {blue, blue, blue, blue}}
+	for y, row := range expected {
+		for x, c := range row {
+			if p := imagePixel(t, info, x, y); p != c {
+				t.Errorf("pixel %d,%d is %v, expected %v", x, y, p, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,568 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ISeeMe/gofpdf/internal/ccitt"
+)
+
+// tags of the image file directory of TIFF images
+const (
+	tiffImageWidth                = 256
+	tiffImageLength               = 257
+	tiffBitsPerSample             = 258
+	tiffCompression               = 259
+	tiffPhotometricInterpretation = 262
+	tiffFillOrder                 = 266
+	tiffStripOffsets              = 273
+	tiffSamplesPerPixel           = 277
+	tiffRowsPerStrip              = 278
+	tiffStripByteCounts           = 279
+	tiffPlanarConfiguration       = 284
+	tiffPredictor                 = 317
+	tiffColorMap                  = 320
+	tiffTileWidth                 = 322
+	tiffTileLength                = 323
+	tiffTileOffsets               = 324
+	tiffTileByteCounts            = 325
+	tiffInkSet                    = 332
+	tiffExtraSamples              = 338
+)
+
+// compressions of TIFF images
+const (
+	tiffCompressionNone     = 1
+	tiffCompressionGroup4   = 4
+	tiffCompressionLZW      = 5
+	tiffCompressionDeflate  = 8
+	tiffCompressionPackBits = 32773
+	tiffCompressionDeflate2 = 32946
+)
+
+// photometric interpretations of TIFF images
+const (
+	tiffWhiteIsZero = 0
+	tiffBlackIsZero = 1
+	tiffRGB         = 2
+	tiffPalette     = 3
+	tiffSeparated   = 5
+)
+
+var tiffLittleEndian = []byte("II*\x00")
+var tiffBigEndian = []byte("MM\x00*")
+
+// tiffIFD the fields of the first image file directory of a TIFF image
+type tiffIFD struct {
+	data   []byte
+	order  binary.ByteOrder
+	fields map[uint16][]uint32
+}
+
+// readTiffIFD reads the first image file directory of the TIFF image data
+func readTiffIFD(data []byte) (*tiffIFD, error) {
+	ifd := &tiffIFD{data: data, fields: map[uint16][]uint32{}}
+	if len(data) < 8 {
+		return nil, errors.New("invalid TIFF header")
+	}
+	switch {
+	case bytes.HasPrefix(data, tiffLittleEndian):
+		ifd.order = binary.LittleEndian
+	case bytes.HasPrefix(data, tiffBigEndian):
+		ifd.order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid TIFF header")
+	}
+
+	offset := int(ifd.order.Uint32(data[4:]))
+	if offset < 8 || offset+2 > len(data) {
+		return nil, errors.New("invalid TIFF image file directory")
+	}
+	entries := int(ifd.order.Uint16(data[offset:]))
+	if offset+2+entries*12 > len(data) {
+		return nil, errors.New("invalid TIFF image file directory")
+	}
+	for n := 0; n < entries; n++ {
+		entry := data[offset+2+n*12:]
+		tag := ifd.order.Uint16(entry)
+		count := int(ifd.order.Uint32(entry[4:]))
+
+		var size int
+		switch ifd.order.Uint16(entry[2:]) {
+		case 1:
+			size = 1
+		case 3:
+			size = 2
+		case 4:
+			size = 4
+		default:
+			// fields of other types are not needed
+			continue
+		}
+		values := entry[8:12]
+		if count*size > 4 {
+			start := int(ifd.order.Uint32(entry[8:]))
+			if count > len(data) || start < 0 || start+count*size > len(data) {
+				return nil, fmt.Errorf("invalid TIFF field %d", tag)
+			}
+			values = data[start:]
+		}
+		field := make([]uint32, count)
+		for x := range field {
+			switch size {
+			case 1:
+				field[x] = uint32(values[x])
+			case 2:
+				field[x] = uint32(ifd.order.Uint16(values[x*2:]))
+			case 4:
+				field[x] = ifd.order.Uint32(values[x*4:])
+			}
+		}
+		ifd.fields[tag] = field
+	}
+	return ifd, nil
+}
+
+// value returns the first value of the field with the tag, def if the
+// directory has no such field
+func (ifd *tiffIFD) value(tag uint16, def int) int {
+	if field := ifd.fields[tag]; len(field) > 0 {
+		return int(field[0])
+	}
+	return def
+}
+
+// chunks returns the data of the strips or tiles of the image at the offsets
+// of the field offsetsTag, their sizes are those of the field countsTag
+func (ifd *tiffIFD) chunks(offsetsTag, countsTag uint16) ([][]byte, error) {
+	offsets, counts := ifd.fields[offsetsTag], ifd.fields[countsTag]
+	if len(offsets) == 0 || len(offsets) != len(counts) {
+		return nil, errors.New("invalid TIFF strips")
+	}
+	chunks := make([][]byte, len(offsets))
+	for x := range offsets {
+		start, end := int64(offsets[x]), int64(offsets[x])+int64(counts[x])
+		if end > int64(len(ifd.data)) {
+			return nil, fmt.Errorf("TIFF strip %d is truncated", x)
+		}
+		chunks[x] = ifd.data[start:end]
+	}
+	return chunks, nil
+}
+
+// samples returns the decoded samples of the rows of the image, strips are
+// tiles as wide as the image
+func (ifd *tiffIFD) samples(w, h, spp, bps int) ([]byte, error) {
+	predictor := ifd.value(tiffPredictor, 1)
+	if predictor != 1 && predictor != 2 {
+		return nil, fmt.Errorf("TIFF predictor %d is not supported", predictor)
+	}
+	tileW, tileH := w, ifd.value(tiffRowsPerStrip, h)
+	offsetsTag, countsTag := uint16(tiffStripOffsets), uint16(tiffStripByteCounts)
+	if _, ok := ifd.fields[tiffTileWidth]; ok {
+		tileW, tileH = ifd.value(tiffTileWidth, 0), ifd.value(tiffTileLength, 0)
+		offsetsTag, countsTag = tiffTileOffsets, tiffTileByteCounts
+	}
+	if tileW <= 0 || tileH <= 0 {
+		return nil, errors.New("invalid TIFF strips")
+	}
+	if tileH > h {
+		tileH = h
+	}
+	if spp < 1 || spp > 16 || bps < 1 || bps > 16 {
+		return nil, errors.New("invalid TIFF samples")
+	}
+	chunks, err := ifd.chunks(offsetsTag, countsTag)
+	if err != nil {
+		return nil, err
+	}
+	// the counts are divided to not overflow with crafted sizes
+	across, down := (w+tileW-1)/tileW, (h+tileH-1)/tileH
+	if len(chunks)/across < down {
+		return nil, errors.New("TIFF image is truncated")
+	}
+
+	// the strips are decoded before the samples are allocated, so their size
+	// is bounded by the data actually present
+	rowLen := (w*spp*bps + 7) / 8
+	tileRowLen := (tileW*spp*bps + 7) / 8
+	tiles := make([][]byte, across*down)
+	for n := range tiles {
+		var decoded []byte
+		switch compression := ifd.value(tiffCompression, tiffCompressionNone); compression {
+		case tiffCompressionNone:
+			decoded = chunks[n]
+		case tiffCompressionLZW:
+			decoded, err = tiffLZWDecode(chunks[n])
+		case tiffCompressionDeflate, tiffCompressionDeflate2:
+			decoded, err = flateDecode(chunks[n])
+		case tiffCompressionPackBits:
+			decoded, err = packBitsDecode(chunks[n])
+		default:
+			return nil, fmt.Errorf("TIFF compression %d is not supported", compression)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("TIFF strip %d: %v", n, err)
+		}
+
+		// the last strip has the remaining rows, tiles are filled
+		rows := tileH
+		if rest := h - n/across*tileH; rows > rest {
+			rows = rest
+		}
+		if len(decoded)/tileRowLen < rows {
+			return nil, fmt.Errorf("TIFF strip %d is truncated", n)
+		}
+		if predictor == 2 {
+			if err := tiffUnpredict(decoded[:rows*tileRowLen], tileRowLen, spp, bps, ifd.order); err != nil {
+				return nil, err
+			}
+		}
+		tiles[n] = decoded
+	}
+
+	samples := make([]byte, rowLen*h)
+	for n, decoded := range tiles {
+		tx, ty := n%across, n/across
+		rows := tileH
+		if rest := h - ty*tileH; rows > rest {
+			rows = rest
+		}
+		start := tx * tileW * spp * bps / 8
+		size := tileRowLen
+		if rest := rowLen - start; size > rest {
+			size = rest
+		}
+		for row := 0; row < rows; row++ {
+			copy(samples[(ty*tileH+row)*rowLen+start:][:size], decoded[row*tileRowLen:])
+		}
+	}
+	return samples, nil
+}
+
+func parseTiff(raw *bytes.Reader, info *imgInfo) error {
+	raw.Seek(0, 0)
+	data, err := ioutil.ReadAll(raw)
+	if err != nil {
+		return err
+	}
+	ifd, err := readTiffIFD(data)
+	if err != nil {
+		return err
+	}
+
+	info.w = ifd.value(tiffImageWidth, 0)
+	info.h = ifd.value(tiffImageLength, 0)
+	if info.w <= 0 || info.h <= 0 {
+		return errors.New("invalid TIFF image size")
+	}
+	if ifd.value(tiffPlanarConfiguration, 1) != 1 {
+		return errors.New("TIFF images with separate planes are not supported")
+	}
+	bps := ifd.value(tiffBitsPerSample, 1)
+	for _, v := range ifd.fields[tiffBitsPerSample] {
+		if int(v) != bps {
+			return errors.New("TIFF images with different bits per sample are not supported")
+		}
+	}
+	spp := ifd.value(tiffSamplesPerPixel, 1)
+	photometric := ifd.value(tiffPhotometricInterpretation, -1)
+	if ifd.value(tiffCompression, tiffCompressionNone) == tiffCompressionGroup4 {
+		return parseTiffGroup4(ifd, info, bps, spp, photometric)
+	}
+
+	samples, err := ifd.samples(info.w, info.h, spp, bps)
+	if err != nil {
+		return err
+	}
+	if bps == 16 && photometric == tiffPalette {
+		return errors.New("invalid TIFF palette image")
+	}
+	if bps == 16 {
+		// only the most significant byte of the samples is kept
+		high := 0
+		if ifd.order == binary.LittleEndian {
+			high = 1
+		}
+		for x := 0; x < len(samples)/2; x++ {
+			samples[x] = samples[x*2+high]
+		}
+		samples = samples[:len(samples)/2]
+		bps = 8
+	} else if bps != 1 && bps != 2 && bps != 4 && bps != 8 {
+		return fmt.Errorf("TIFF images with %d bits per sample are not supported", bps)
+	}
+	info.bitsPerComponent = fmt.Sprintf("%d", bps)
+
+	colors := 1
+	switch photometric {
+	case tiffWhiteIsZero, tiffBlackIsZero:
+		info.colspace = "DeviceGray"
+	case tiffRGB:
+		info.colspace = "DeviceRGB"
+		colors = 3
+	case tiffPalette:
+		info.colspace = "Indexed"
+		colorMap := ifd.fields[tiffColorMap]
+		n := 1 << uint(bps)
+		if len(colorMap) != 3*n {
+			return errors.New("invalid TIFF color map")
+		}
+		info.pal = make([]byte, 3*n)
+		for x := 0; x < n; x++ {
+			for c := 0; c < 3; c++ {
+				info.pal[x*3+c] = byte(colorMap[c*n+x] >> 8)
+			}
+		}
+	case tiffSeparated:
+		if ifd.value(tiffInkSet, 1) != 1 {
+			return errors.New("TIFF images with inks other than CMYK are not supported")
+		}
+		info.colspace = "DeviceCMYK"
+		colors = 4
+	default:
+		return fmt.Errorf("TIFF photometric interpretation %d is not supported", photometric)
+	}
+	if spp < colors {
+		return fmt.Errorf("TIFF image has %d samples per pixel, expected %d", spp, colors)
+	}
+	if spp > colors && bps != 8 {
+		return errors.New("TIFF images with extra samples must have 8 bits per sample")
+	}
+
+	// the first extra sample is the alpha value if it is associated or
+	// unassociated, any other is ignored
+	var alpha []byte
+	extra := ifd.fields[tiffExtraSamples]
+	associated := len(extra) > 0 && extra[0] == 1
+	if spp > colors {
+		colorSamples := make([]byte, 0, colors*info.w*info.h)
+		if associated || len(extra) > 0 && extra[0] == 2 {
+			alpha = make([]byte, 0, info.w*info.h)
+		}
+		for x := 0; x+spp <= len(samples); x += spp {
+			pixel := samples[x : x+colors]
+			if alpha != nil {
+				a := samples[x+colors]
+				alpha = append(alpha, a)
+				if associated && a > 0 && a < 255 {
+					for c, v := range pixel {
+						pixel[c] = byte(min255(int(v) * 255 / int(a)))
+					}
+				}
+			}
+			colorSamples = append(colorSamples, pixel...)
+		}
+		samples = colorSamples
+	}
+
+	switch photometric {
+	case tiffWhiteIsZero, tiffSeparated:
+		// the samples of CMYK images are written with an inverted decode array
+		for x := range samples {
+			samples[x] = ^samples[x]
+		}
+	}
+	return info.setSamples(samples, alpha, colors)
+}
+
+// parseTiffGroup4 sets the image info of a TIFF image compressed with CCITT
+// Group 4, the data of an image of one strip is not decoded
+func parseTiffGroup4(ifd *tiffIFD, info *imgInfo, bps, spp, photometric int) error {
+	if bps != 1 || spp != 1 {
+		return errors.New("invalid TIFF Group 4 image")
+	}
+	if _, ok := ifd.fields[tiffTileWidth]; ok {
+		return errors.New("tiled TIFF Group 4 images are not supported")
+	}
+	strips, err := ifd.chunks(tiffStripOffsets, tiffStripByteCounts)
+	if err != nil {
+		return err
+	}
+	info.colspace = "DeviceGray"
+	info.bitsPerComponent = "1"
+	if len(strips) > 1 {
+		return parseTiffGroup4Strips(ifd, info, strips, photometric)
+	}
+
+	info.filter = "CCITTFaxDecode"
+	info.decodeParms = fmt.Sprintf("/K -1 /Columns %d /Rows %d", info.w, info.h)
+	if photometric == tiffBlackIsZero {
+		info.decodeParms += " /BlackIs1 true"
+	}
+	info.data = append([]byte(nil), strips[0]...)
+	if ifd.value(tiffFillOrder, 1) == 2 {
+		for x, b := range info.data {
+			info.data[x] = reverseBits(b)
+		}
+	}
+	return nil
+}
+
+// parseTiffGroup4Strips sets the samples of a TIFF image compressed with
+// CCITT Group 4 in several strips, which are compressed separately and so
+// are decoded
+func parseTiffGroup4Strips(ifd *tiffIFD, info *imgInfo, strips [][]byte, photometric int) error {
+	rowsPerStrip := ifd.value(tiffRowsPerStrip, info.h)
+	if rowsPerStrip <= 0 {
+		return errors.New("invalid TIFF strips")
+	}
+	if (info.h+rowsPerStrip-1)/rowsPerStrip > len(strips) {
+		return errors.New("TIFF image is truncated")
+	}
+	order := ccitt.MSB
+	if ifd.value(tiffFillOrder, 1) == 2 {
+		order = ccitt.LSB
+	}
+
+	// the decoded rows are padded to a byte, white is 1
+	var samples []byte
+	for n := 0; n*rowsPerStrip < info.h; n++ {
+		rows := rowsPerStrip
+		if rest := info.h - n*rowsPerStrip; rows > rest {
+			rows = rest
+		}
+		decoded, err := ioutil.ReadAll(ccitt.NewReader(bytes.NewReader(strips[n]), order, ccitt.Group4, info.w, rows, nil))
+		if err != nil {
+			return fmt.Errorf("TIFF strip %d: %v", n, err)
+		}
+		samples = append(samples, decoded...)
+	}
+	rowLen := (info.w + 7) / 8
+	if len(samples)/rowLen < info.h {
+		return errors.New("TIFF image is truncated")
+	}
+	samples = samples[:rowLen*info.h]
+	if photometric == tiffBlackIsZero {
+		for x := range samples {
+			samples[x] = ^samples[x]
+		}
+	}
+	return info.setSamples(samples, nil, 1)
+}
+
+// tiffUnpredict reverses the horizontal differencing of the samples
+func tiffUnpredict(samples []byte, rowLen, spp, bps int, order binary.ByteOrder) error {
+	switch bps {
+	case 8:
+		for row := 0; row+rowLen <= len(samples); row += rowLen {
+			for x := row + spp; x < row+rowLen; x++ {
+				samples[x] += samples[x-spp]
+			}
+		}
+	case 16:
+		for row := 0; row+rowLen <= len(samples); row += rowLen {
+			for x := row + spp*2; x+2 <= row+rowLen; x += 2 {
+				order.PutUint16(samples[x:], order.Uint16(samples[x:])+order.Uint16(samples[x-spp*2:]))
+			}
+		}
+	default:
+		return fmt.Errorf("TIFF predictor with %d bits per sample is not supported", bps)
+	}
+	return nil
+}
+
+// tiffLZWDecode decodes the LZW compression of TIFF images, which increases
+// the width of its codes one code early
+func tiffLZWDecode(data []byte) ([]byte, error) {
+	const (
+		clearCode = 256
+		eoiCode   = 257
+		firstCode = 258
+		maxCode   = 4096
+	)
+
+	var out []byte
+	var table [][]byte
+	var prev []byte
+	var bits uint32
+	var nbits uint
+	width := uint(9)
+	for pos := 0; ; {
+		for nbits < width {
+			if pos >= len(data) {
+				// the end of information code is missing in some images
+				return out, nil
+			}
+			bits = bits<<8 | uint32(data[pos])
+			pos++
+			nbits += 8
+		}
+		code := int(bits>>(nbits-width)) & (1<<width - 1)
+		nbits -= width
+
+		var entry []byte
+		switch {
+		case code == clearCode:
+			table = table[:0]
+			width = 9
+			prev = nil
+			continue
+		case code == eoiCode:
+			return out, nil
+		case code < clearCode:
+			entry = []byte{byte(code)}
+		case code-firstCode < len(table):
+			entry = table[code-firstCode]
+		case code-firstCode == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return nil, errors.New("invalid LZW code")
+		}
+		out = append(out, entry...)
+
+		if prev != nil && firstCode+len(table) < maxCode {
+			table = append(table, append(append(make([]byte, 0, len(prev)+1), prev...), entry[0]))
+		}
+		prev = entry
+		if firstCode+len(table)+1 >= 1<<width && width < 12 {
+			width++
+		}
+	}
+}
+
+// packBitsDecode decodes the PackBits run length encoding
+func packBitsDecode(data []byte) ([]byte, error) {
+	var out []byte
+	for pos := 0; pos < len(data); {
+		n := int(int8(data[pos]))
+		pos++
+		switch {
+		case n >= 0:
+			if pos+n+1 > len(data) {
+				return nil, errors.New("invalid PackBits data")
+			}
+			out = append(out, data[pos:pos+n+1]...)
+			pos += n + 1
+		case n != -128:
+			if pos >= len(data) {
+				return nil, errors.New("invalid PackBits data")
+			}
+			for x := 0; x < 1-n; x++ {
+				out = append(out, data[pos])
+			}
+			pos++
+		}
+	}
+	return out, nil
+}
+
+// reverseBits returns b with the order of its bits reversed
+func reverseBits(b byte) byte {
+	var r byte
+	for x := 0; x < 8; x++ {
+		r = r<<1 | b>>uint(x)&1
+	}
+	return r
+}
+
+// min255 returns v limited to 255
+func min255(v int) int {
+	if v > 255 {
+		return 255
+	}
+	return v
+}
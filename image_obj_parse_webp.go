@@ -0,0 +1,194 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+
+	"github.com/ISeeMe/gofpdf/internal/vp8"
+	"github.com/ISeeMe/gofpdf/internal/vp8l"
+)
+
+var errInvalidWebp = errors.New("invalid WebP image")
+
+// isWebp returns true if the header is the header of a WebP image
+func isWebp(header []byte) bool {
+	return len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP"))
+}
+
+func parseWebp(raw *bytes.Reader, info *imgInfo) error {
+	img, err := decodeWebp(raw)
+	if err != nil {
+		return err
+	}
+	decoded, err := goImageInfo(img)
+	if err != nil {
+		return err
+	}
+	decoded.formatName = info.formatName
+	*info = decoded
+	return nil
+}
+
+// decodeWebp decodes the lossy or lossless WebP image, its alpha values are
+// those of the ALPH chunk of a lossy image
+func decodeWebp(raw *bytes.Reader) (image.Image, error) {
+	raw.Seek(0, 0)
+	var header [12]byte
+	if _, err := io.ReadFull(raw, header[:]); err != nil || !isWebp(header[:]) {
+		return nil, errInvalidWebp
+	}
+
+	var (
+		alpha       []byte
+		wantAlpha   bool
+		seenVP8X    bool
+		w, h        uint32
+		chunkHeader [8]byte
+	)
+	for {
+		if _, err := io.ReadFull(raw, chunkHeader[:]); err != nil {
+			return nil, errInvalidWebp
+		}
+		size := binary.LittleEndian.Uint32(chunkHeader[4:])
+		if int64(size) > int64(raw.Len()) {
+			return nil, errInvalidWebp
+		}
+		chunk := make([]byte, size)
+		io.ReadFull(raw, chunk)
+		// chunks are padded to an even size
+		if size%2 == 1 {
+			raw.ReadByte()
+		}
+
+		switch string(chunkHeader[:4]) {
+		case "ALPH":
+			if !wantAlpha || len(chunk) < 1 {
+				return nil, errInvalidWebp
+			}
+			wantAlpha = false
+			var err error
+			if alpha, err = webpAlpha(chunk[1:], w, h, chunk[0]&0x03); err != nil {
+				return nil, err
+			}
+			if uint64(len(alpha)) != uint64(w)*uint64(h) {
+				return nil, errInvalidWebp
+			}
+			unfilterWebpAlpha(alpha, int(w), (chunk[0]>>2)&0x03)
+
+		case "VP8 ":
+			if wantAlpha {
+				return nil, errInvalidWebp
+			}
+			d := vp8.NewDecoder()
+			d.Init(bytes.NewReader(chunk), len(chunk))
+			if _, err := d.DecodeFrameHeader(); err != nil {
+				return nil, err
+			}
+			m, err := d.DecodeFrame()
+			if err != nil {
+				return nil, err
+			}
+			if alpha != nil {
+				if m.Bounds() != image.Rect(0, 0, int(w), int(h)) {
+					return nil, errInvalidWebp
+				}
+				return &image.NYCbCrA{YCbCr: *m, A: alpha, AStride: int(w)}, nil
+			}
+			return m, nil
+
+		case "VP8L":
+			if wantAlpha || alpha != nil {
+				return nil, errInvalidWebp
+			}
+			return vp8l.Decode(bytes.NewReader(chunk))
+
+		case "VP8X":
+			if seenVP8X || len(chunk) != 10 {
+				return nil, errInvalidWebp
+			}
+			seenVP8X = true
+			const (
+				animationBit = 1 << 1
+				alphaBit     = 1 << 4
+			)
+			if chunk[0]&animationBit != 0 {
+				return nil, errors.New("animated WebP images are not supported")
+			}
+			wantAlpha = chunk[0]&alphaBit != 0
+			w = (uint32(chunk[4]) | uint32(chunk[5])<<8 | uint32(chunk[6])<<16) + 1
+			h = (uint32(chunk[7]) | uint32(chunk[8])<<8 | uint32(chunk[9])<<16) + 1
+		}
+	}
+}
+
+// webpAlpha returns the alpha values of the ALPH chunk data of an image of
+// w x h pixels, they are raw or compressed as the green values of a
+// lossless image
+func webpAlpha(data []byte, w, h uint32, compression byte) ([]byte, error) {
+	switch compression {
+	case 0:
+		// the size is computed in 64 bits to not overflow with crafted sizes
+		if uint64(len(data)) < uint64(w)*uint64(h) {
+			return nil, errInvalidWebp
+		}
+		return data[:uint64(w)*uint64(h)], nil
+	case 1:
+		if w-1 > 0x3fff || h-1 > 0x3fff {
+			return nil, errInvalidWebp
+		}
+		// the header of the lossless image: the signature, the width and
+		// the height minus one in 14 bits each, no alpha and version 0
+		header := []byte{
+			0x2f,
+			uint8(w - 1),
+			uint8((w-1)>>8) | uint8((h-1)<<6),
+			uint8((h - 1) >> 2),
+			uint8((h - 1) >> 10),
+		}
+		m, err := vp8l.Decode(io.MultiReader(bytes.NewReader(header), bytes.NewReader(data)))
+		if err != nil {
+			return nil, err
+		}
+		pix := m.(*image.NRGBA).Pix
+		alpha := make([]byte, len(pix)/4)
+		for x := range alpha {
+			alpha[x] = pix[4*x+1]
+		}
+		return alpha, nil
+	}
+	return nil, errInvalidWebp
+}
+
+// unfilterWebpAlpha reverses the prediction filter of the alpha values
+func unfilterWebpAlpha(alpha []byte, stride int, filter byte) {
+	if len(alpha) == 0 || stride == 0 || filter == 0 {
+		return
+	}
+	// the first row is predicted from the left
+	for x := 1; x < stride; x++ {
+		alpha[x] += alpha[x-1]
+	}
+	for row := stride; row+stride <= len(alpha); row += stride {
+		// the first column is predicted from above
+		alpha[row] += alpha[row-stride]
+		for x := row + 1; x < row+stride; x++ {
+			switch filter {
+			case 1:
+				alpha[x] += alpha[x-1]
+			case 2:
+				alpha[x] += alpha[x-stride]
+			case 3:
+				v := int(alpha[x-1]) + int(alpha[x-stride]) - int(alpha[x-stride-1])
+				if v < 0 {
+					v = 0
+				} else if v > 255 {
+					v = 255
+				}
+				alpha[x] += byte(v)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+const importedType = "Imported"
+
+// ImportedObj an object copied from an existing document
+type ImportedObj struct { //impl IObj
+	source  string
+	value   interface{}
+	getRoot func() *Fpdf
+}
+
+func (i *ImportedObj) init(funcGetRoot func() *Fpdf) {
+	i.getRoot = funcGetRoot
+}
+
+func (i *ImportedObj) getType() string {
+	return importedType
+}
+
+func (i *ImportedObj) write(w io.Writer, objID int) error {
+	if err := i.getRoot().writeImportedValue(w, i.source, i.value, objID); err != nil {
+		return err
+	}
+	io.WriteString(w, "\n")
+	return nil
+}
+
+// writeImportedValue writes a value read from an existing document. The
+// references are renumbered to the objects copied into the document, strings
+// and streams are encrypted with the key of the object objID.
+func (gp *Fpdf) writeImportedValue(w io.Writer, source string, v interface{}, objID int) error {
+	switch value := v.(type) {
+	case nil:
+		io.WriteString(w, "null")
+	case bool:
+		fmt.Fprintf(w, "%t", value)
+	case int:
+		fmt.Fprintf(w, "%d", value)
+	case float64:
+		io.WriteString(w, strconv.FormatFloat(value, 'f', -1, 64))
+	case pdfName:
+		fmt.Fprintf(w, "/%s", escapeName(string(value)))
+	case pdfString:
+		s, err := encodeString([]byte(value), gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, s)
+	case pdfRef:
+		if index, ok := gp.importedObjs[importedObjKey(source, value.Num)]; ok {
+			fmt.Fprintf(w, "%d 0 R", index+1)
+		} else {
+			io.WriteString(w, "null")
+		}
+	case pdfArray:
+		io.WriteString(w, "[")
+		for x, item := range value {
+			if x > 0 {
+				io.WriteString(w, " ")
+			}
+			if err := gp.writeImportedValue(w, source, item, objID); err != nil {
+				return err
+			}
+		}
+		io.WriteString(w, "]")
+	case pdfDict:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		io.WriteString(w, "<<")
+		for _, key := range keys {
+			fmt.Fprintf(w, " /%s ", escapeName(key))
+			if err := gp.writeImportedValue(w, source, value[key], objID); err != nil {
+				return err
+			}
+		}
+		io.WriteString(w, " >>")
+	case pdfStream:
+		data, err := encryptStream(gp.protection(), objID, value.Data)
+		if err != nil {
+			return err
+		}
+
+		dict := pdfDict{}
+		for key, item := range value.Dict {
+			dict[key] = item
+		}
+		dict["Length"] = len(data)
+		if err := gp.writeImportedValue(w, source, dict, objID); err != nil {
+			return err
+		}
+		io.WriteString(w, "\nstream\n")
+		w.Write(data)
+		io.WriteString(w, "\nendstream")
+	default:
+		return fmt.Errorf("unexpected value %v", v)
+	}
+	return nil
+}
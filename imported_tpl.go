@@ -0,0 +1,285 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ISeeMe/gofpdf/bp"
+	"github.com/ISeeMe/gofpdf/geh"
+)
+
+// importedPage a page of an existing document
+type importedPage struct {
+	Box       [4]float64 // lower left and upper right corners of the page boundary
+	Rotate    int        // clockwise rotation, 0, 90, 180 or 270 degrees
+	Resources interface{}
+	Content   []byte
+	Objects   []int // numbers of the objects used by the resources
+}
+
+// ImportedTpl is a Template holding the pages of an existing document. The
+// objects used by the resources of a page are copied into the documents the
+// page is used in.
+type ImportedTpl struct {
+	source  string // hash of the imported document
+	pages   []importedPage
+	objects map[int]interface{}
+	page    int
+}
+
+// ImportPages reads the PDF document from r and returns a template per page,
+// to place letterheads or pre-designed forms under generated content with
+// UseTemplate or UseTemplateScaled. box is the page boundary used as size of
+// the templates: PageBoundaryMedia, PageBoundaryCrop, PageBoundaryBleed,
+// PageBoundaryTrim or PageBoundaryArt. A missing boundary defaults to the
+// crop box, the crop box to the media box. Encrypted documents are not
+// supported.
+func ImportPages(r io.Reader, box int) ([]Template, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newPdfReader(b)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := reader.pages()
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("the document has no pages")
+	}
+
+	tpl := &ImportedTpl{
+		source:  fmt.Sprintf("%x", sha1.Sum(b)),
+		objects: make(map[int]interface{}),
+	}
+	for x, page := range pages {
+		content, err := reader.pageContent(page)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %s", x+1, err)
+		}
+
+		pageBox, err := reader.pageBox(page, box)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %s", x+1, err)
+		}
+
+		rotate, _ := reader.resolve(page["Rotate"]).(int)
+		rotate = ((rotate%360 + 360) % 360) / 90 * 90
+
+		used := make(map[int]bool)
+		tpl.collect(reader, page["Resources"], used)
+		objects := make([]int, 0, len(used))
+		for num := range used {
+			objects = append(objects, num)
+		}
+		sort.Ints(objects)
+
+		tpl.pages = append(tpl.pages, importedPage{
+			Box:       pageBox,
+			Rotate:    rotate,
+			Resources: page["Resources"],
+			Content:   content,
+			Objects:   objects,
+		})
+	}
+
+	return tpl.FromPages(), nil
+}
+
+// ImportPagesFromFile reads the PDF document at path and returns a template
+// per page. See ImportPages.
+func ImportPagesFromFile(path string, box int) ([]Template, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ImportPages(bytes.NewReader(b), box)
+}
+
+// collect gathers the objects referenced by v. References to pages are not
+// followed, they are written as null.
+func (t *ImportedTpl) collect(r *pdfReader, v interface{}, used map[int]bool) {
+	switch value := v.(type) {
+	case pdfRef:
+		if used[value.Num] {
+			return
+		}
+
+		obj := r.resolve(value)
+		if obj == nil {
+			return
+		}
+		if dict, ok := obj.(pdfDict); ok && (dict["Type"] == pdfName("Page") || dict["Type"] == pdfName("Pages")) {
+			return
+		}
+
+		used[value.Num] = true
+		t.objects[value.Num] = obj
+		t.collect(r, obj, used)
+	case pdfArray:
+		for _, item := range value {
+			t.collect(r, item, used)
+		}
+	case pdfDict:
+		for _, item := range value {
+			t.collect(r, item, used)
+		}
+	case pdfStream:
+		t.collect(r, value.Dict, used)
+	}
+}
+
+// ID returns the global template identifier
+func (t *ImportedTpl) ID() string {
+	page := t.pages[t.page]
+	key := fmt.Sprintf("%s %d %v %d", t.source, t.page, page.Box, page.Rotate)
+	return fmt.Sprintf("%x", sha1.Sum([]byte(key)))
+}
+
+// Size gives the bounding dimensions of the page, rotated like the page is
+// displayed
+func (t *ImportedTpl) Size() (corner Point, size Rect) {
+	page := t.pages[t.page]
+	w, h := page.Box[2]-page.Box[0], page.Box[3]-page.Box[1]
+	if page.Rotate == 90 || page.Rotate == 270 {
+		w, h = h, w
+	}
+	return Point{}, Rect{W: w, H: h}
+}
+
+// matrix maps the page boundary of the page to the template space
+func (p *importedPage) matrix() [6]float64 {
+	llx, lly, urx, ury := p.Box[0], p.Box[1], p.Box[2], p.Box[3]
+	switch p.Rotate {
+	case 90:
+		return [6]float64{0, -1, 1, 0, -lly, urx}
+	case 180:
+		return [6]float64{-1, 0, 0, -1, urx, ury}
+	case 270:
+		return [6]float64{0, 1, -1, 0, ury, -llx}
+	}
+	return [6]float64{1, 0, 0, 1, -llx, -lly}
+}
+
+// Bytes returns the content of the page, not including resources
+func (t *ImportedTpl) Bytes() []byte {
+	return t.pages[t.page].Content
+}
+
+// Images returns nil, the images of the page are part of its resources
+func (t *ImportedTpl) Images() []*ImageObj {
+	return nil
+}
+
+// Fonts returns nil, the fonts of the page are part of its resources
+func (t *ImportedTpl) Fonts() []*SubsetFontObj {
+	return nil
+}
+
+// ExtGStates returns nil, the graphics states of the page are part of its
+// resources
+func (t *ImportedTpl) ExtGStates() []*ExtGStateObj {
+	return nil
+}
+
+// Shadings returns nil, the shadings of the page are part of its resources
+func (t *ImportedTpl) Shadings() []*ShadingObj {
+	return nil
+}
+
+// SpotColors returns nil, the color spaces of the page are part of its
+// resources
+func (t *ImportedTpl) SpotColors() []*SpotColorObj {
+	return nil
+}
+
+// Templates returns nil, the forms of the page are part of its resources
+func (t *ImportedTpl) Templates() []Template {
+	return nil
+}
+
+// NumPages returns the number of pages of the imported document
+func (t *ImportedTpl) NumPages() int {
+	return len(t.pages)
+}
+
+// FromPage creates a template of a specific page, pages start at 1
+func (t *ImportedTpl) FromPage(page int) (Template, error) {
+	page--
+	if page < 0 || page >= t.NumPages() {
+		return nil, fmt.Errorf("the imported document does not have a page %d", page+1)
+	}
+
+	if t.page == page {
+		return t, nil
+	}
+
+	t2 := *t
+	t2.page = page
+	return &t2, nil
+}
+
+// FromPages creates a template slice with all the pages of the imported
+// document
+func (t *ImportedTpl) FromPages() []Template {
+	p := make([]Template, t.NumPages())
+	for x := 0; x < t.NumPages(); x++ {
+		p[x], _ = t.FromPage(x + 1)
+	}
+	return p
+}
+
+// Serialize turns a template into a byte string for later deserialization
+func (t *ImportedTpl) Serialize() ([]byte, error) {
+	b := bp.GetBuffer()
+	enc := gob.NewEncoder(b)
+	err := enc.Encode(t)
+	return b.Bytes(), err
+}
+
+// GobEncode encodes the receiving template into a byte buffer. Use GobDecode
+// to decode the byte buffer back to a template.
+func (t *ImportedTpl) GobEncode() ([]byte, error) {
+	return geh.EncodeMany(t.source, t.pages, t.objects, t.page)
+}
+
+// GobDecode decodes the specified byte buffer into the receiving template.
+func (t *ImportedTpl) GobDecode(buf []byte) error {
+	return geh.DecodeMany(buf, &t.source, &t.pages, &t.objects, &t.page)
+}
+
+// registerImportedObjs copies the objects used by the page of an imported
+// template into the document, objects shared by pages are copied once
+func (gp *Fpdf) registerImportedObjs(t *ImportedTpl) {
+	if gp.importedObjs == nil {
+		gp.importedObjs = make(map[string]int)
+	}
+
+	for _, num := range t.pages[t.page].Objects {
+		key := importedObjKey(t.source, num)
+		if _, ok := gp.importedObjs[key]; ok {
+			continue
+		}
+
+		obj := &ImportedObj{source: t.source, value: t.objects[num]}
+		obj.init(func() *Fpdf {
+			return gp
+		})
+		gp.importedObjs[key] = gp.addObj(obj)
+	}
+}
+
+func importedObjKey(source string, num int) string {
+	return fmt.Sprintf("%s %d", source, num)
+}
@@ -0,0 +1,328 @@
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/gob"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestImportPages(t *testing.T) {
+	src, err := New(PdfOptionPageSize(300, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	src.AddPage()
+	if err := src.SetFont("times", "", 14); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Cell(100, 20, "Letterhead"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Image("test/res/gopher01.jpg", 200, 20, Rect{W: 50, H: 50}); err != nil {
+		t.Fatal(err)
+	}
+	opt := PageOption{}
+	opt.AddPageBoundary(src.NewPageSizeBoundary(100, 150))
+	src.AddPageWithOption(opt)
+	src.Line(10, 10, 90, 140)
+
+	b, err := src.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpls, err := ImportPages(bytes.NewReader(b), PageBoundaryMedia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tpls) != 2 {
+		t.Fatalf("expected 2 pages, found %d", len(tpls))
+	}
+	if _, size := tpls[0].Size(); size != (Rect{W: 300, H: 200}) {
+		t.Errorf("unexpected size %v of page 1", size)
+	}
+	if _, size := tpls[1].Size(); size != (Rect{W: 100, H: 150}) {
+		t.Errorf("unexpected size %v of page 2", size)
+	}
+	if !bytes.Contains(tpls[0].Bytes(), []byte(" Tf\n")) {
+		t.Errorf("unexpected content of page 1 %q", tpls[0].Bytes())
+	}
+
+	pdf, err := New(PdfOptionPageSize(300, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.UseTemplate(tpls[0]); err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.UseTemplateScaled(tpls[0], Point{X: 0, Y: 0}, Rect{W: 150, H: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.UseTemplate(tpls[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the objects of the first page are copied once
+	if n := len(pdf.pdfObjs.allOf(importedType)); n != 7 {
+		t.Errorf("expected 7 copied objects, found %d", n)
+	}
+
+	// the written document is read back, the form of the first page uses the
+	// copied font and image
+	reader, err := newPdfReader(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, found %d", len(pages))
+	}
+
+	resources := reader.resolve(pages[0]["Resources"]).(pdfDict)
+	xobjects := reader.resolve(resources["XObject"]).(pdfDict)
+	form, ok := reader.resolve(xobjects["TPL"+tpls[0].ID()]).(pdfStream)
+	if !ok {
+		t.Fatal("the form of the imported page was not found")
+	}
+	content, err := reader.decodeStream(form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, tpls[0].Bytes()) {
+		t.Error("the content of the form should be the content of the imported page")
+	}
+
+	formResources := reader.resolve(form.Dict["Resources"]).(pdfDict)
+	fonts := reader.resolve(formResources["Font"]).(pdfDict)
+	images := reader.resolve(formResources["XObject"]).(pdfDict)
+	if len(fonts) != 1 || len(images) != 1 {
+		t.Fatalf("unexpected resources %v", formResources)
+	}
+	for _, font := range fonts {
+		if dict := reader.resolve(font).(pdfDict); dict["Type"] != pdfName("Font") {
+			t.Errorf("unexpected font %v", dict)
+		}
+	}
+	for _, image := range images {
+		if stream := reader.resolve(image).(pdfStream); stream.Dict["Subtype"] != pdfName("Image") {
+			t.Errorf("unexpected image %v", stream.Dict)
+		}
+	}
+}
+
+func TestImportPagesDamagedXref(t *testing.T) {
+	src, err := New(PdfOptionPageSize(100, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddPage()
+	src.Line(10, 10, 90, 90)
+	b, err := src.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := bytes.LastIndex(b, []byte("startxref\n")) + len("startxref\n")
+	damaged := append(append(append([]byte{}, b[:i]...), "12"...), b[i:]...)
+
+	tpls, err := ImportPages(bytes.NewReader(damaged), PageBoundaryCrop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tpls) != 1 || !bytes.Contains(tpls[0].Bytes(), []byte(" l S\n")) {
+		t.Error("the page should be imported from the rebuilt cross-reference")
+	}
+}
+
+func TestImportPagesCorrupted(t *testing.T) {
+	src, err := New(PdfOptionPageSize(100, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddPage()
+	src.Line(10, 10, 90, 90)
+	b, err := src.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// truncated documents fail or are imported, without panicking
+	for _, size := range []int{0, 10, len(b) / 3, len(b) / 2, len(b) - 20} {
+		ImportPages(bytes.NewReader(b[:size]), PageBoundaryMedia)
+	}
+	if _, err := ImportPages(bytes.NewReader(b[:len(b)/3]), PageBoundaryMedia); err == nil {
+		t.Error("the truncated document should fail")
+	}
+
+	// offsets of the cross-reference past the end of the document
+	corrupted := append([]byte{}, b...)
+	xref := bytes.LastIndex(corrupted, []byte("xref\n"))
+	for i := xref; ; {
+		j := bytes.Index(corrupted[i:], []byte(" 00000 n"))
+		if j < 0 {
+			break
+		}
+		copy(corrupted[i+j-10:], "9999999999")
+		i += j + 1
+	}
+	tpls, err := ImportPages(bytes.NewReader(corrupted), PageBoundaryMedia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tpls) != 1 || !bytes.Contains(tpls[0].Bytes(), []byte(" l S\n")) {
+		t.Error("the page should be imported from the rebuilt cross-reference")
+	}
+
+	// a stream with invalid predictor parameters
+	b = buildObjectStreamPdf(t)
+	corrupted = bytes.Replace(b, []byte("/Columns 5"), []byte("/Columns -5"), 1)
+	if bytes.Equal(corrupted, b) {
+		t.Fatal("the predictor parameters were not found")
+	}
+	if _, err := ImportPages(bytes.NewReader(corrupted), PageBoundaryMedia); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportPagesObjectStreams(t *testing.T) {
+	b := buildObjectStreamPdf(t)
+
+	tpls, err := ImportPages(bytes.NewReader(b), PageBoundaryCrop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tpls) != 1 {
+		t.Fatalf("expected 1 page, found %d", len(tpls))
+	}
+
+	tpl := tpls[0].(*ImportedTpl)
+	if _, size := tpl.Size(); size != (Rect{W: 100, H: 200}) {
+		t.Errorf("the rotated page should be 100 by 200, found %v", size)
+	}
+	if string(tpl.Bytes()) != "BT /F1 12 Tf (Hello) Tj ET\n" {
+		t.Errorf("unexpected content %q", tpl.Bytes())
+	}
+	font, ok := tpl.objects[5].(pdfDict)
+	if !ok || font["BaseFont"] != pdfName("Helvetica") || font["Name"] != pdfString("A(b)\n") {
+		t.Errorf("unexpected font %v", tpl.objects[5])
+	}
+
+	// the template survives serialization
+	s, err := tpl.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ImportedTpl
+	if err := gob.NewDecoder(bytes.NewReader(s)).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != tpl.ID() {
+		t.Error("the serialized template should keep its ID")
+	}
+
+	pdf, err := New(PdfOptionPageSize(100, 200), PdfOptionProtectionAES128(PermissionsPrint, "", "owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.UseTemplate(tpl); err != nil {
+		t.Fatal(err)
+	}
+	out, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"/Matrix [0.0000 -1.0000 1.0000 0.0000 -0.0000 200.0000]",
+		"/BaseFont /Helvetica",
+	} {
+		if !bytes.Contains(out, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+	if bytes.Contains(out, []byte("Hello")) {
+		t.Error("the content of the imported page should be encrypted")
+	}
+}
+
+// buildObjectStreamPdf builds a PDF 1.5 document with an object stream and a
+// cross-reference stream using the PNG up predictor
+func buildObjectStreamPdf(t *testing.T) []byte {
+	compressed := map[int]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 200 100] /Resources << /Font << /F1 5 0 R >> >> >>",
+		3: "<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Rotate 90 >>",
+		5: "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Name (A\\(b\\)\\n) >>",
+	}
+
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	offsets := make(map[int]int)
+
+	offsets[4] = buff.Len()
+	content := fmt.Sprintf("%X>", "BT /F1 12 Tf (Hello) Tj ET")
+	fmt.Fprintf(&buff, "4 0 obj\n<< /Filter /ASCIIHexDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content)
+
+	var header, body strings.Builder
+	for _, num := range []int{1, 2, 3, 5} {
+		fmt.Fprintf(&header, "%d %d ", num, body.Len())
+		body.WriteString(compressed[num] + "\n")
+	}
+	objStm := deflate(t, []byte(header.String()+body.String()))
+	offsets[6] = buff.Len()
+	fmt.Fprintf(&buff, "6 0 obj\n<< /Type /ObjStm /N 4 /First %d /Filter /FlateDecode /Length %d >>\nstream\n", header.Len(), len(objStm))
+	buff.Write(objStm)
+	buff.WriteString("\nendstream\nendobj\n")
+
+	// rows of type, offset or object stream, and index, each preceded by
+	// the up filter
+	offsets[7] = buff.Len()
+	var rows []byte
+	prev := make([]byte, 5)
+	for num := 0; num < 8; num++ {
+		row := make([]byte, 5)
+		if offset, ok := offsets[num]; ok {
+			row = []byte{1, byte(offset >> 16), byte(offset >> 8), byte(offset), 0}
+		} else if _, ok := compressed[num]; ok {
+			index := map[int]int{1: 0, 2: 1, 3: 2, 5: 3}[num]
+			row = []byte{2, 0, 0, 6, byte(index)}
+		}
+		rows = append(rows, 2)
+		for x := range row {
+			rows = append(rows, row[x]-prev[x])
+		}
+		prev = row
+	}
+	xref := deflate(t, rows)
+	fmt.Fprintf(&buff, "7 0 obj\n<< /Type /XRef /Size 8 /Root 1 0 R /W [1 3 1] /Filter /FlateDecode /DecodeParms << /Columns 5 /Predictor 12 >> /Length %d >>\nstream\n", len(xref))
+	buff.Write(xref)
+	buff.WriteString("\nendstream\nendobj\n")
+	fmt.Fprintf(&buff, "startxref\n%d\n%%%%EOF\n", offsets[7])
+	return buff.Bytes()
+}
+
+func deflate(t *testing.T, b []byte) []byte {
+	var buff bytes.Buffer
+	w := zlib.NewWriter(&buff)
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return buff.Bytes()
+}
@@ -0,0 +1,221 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const ocgType = "OCG"
+
+// usages of a layer
+const (
+	// LayerUsageAll the layer is shown and printed according to its visibility
+	LayerUsageAll = iota
+	// LayerUsagePrint the layer is only printed, it is hidden on screen
+	LayerUsagePrint
+	// LayerUsageView the layer is only shown on screen, it is never printed
+	LayerUsageView
+)
+
+// LayerOption option of a layer
+type LayerOption struct {
+	Visible bool // the layer is initially visible
+	Usage   int  // LayerUsageAll|LayerUsagePrint|LayerUsageView
+}
+
+// OCGObj optional content group, a layer that can be shown or hidden by the
+// document reader
+type OCGObj struct { //impl IObj, ProcsetIObj
+	name    string
+	option  LayerOption
+	id      int
+	index   int
+	getRoot func() *Fpdf
+}
+
+func (o *OCGObj) init(funcGetRoot func() *Fpdf) {
+	o.getRoot = funcGetRoot
+}
+
+func (o *OCGObj) getType() string {
+	return ocgType
+}
+
+func (o *OCGObj) write(w io.Writer, objID int) error {
+	name, err := encodeTextString(o.name, o.getRoot().protection(), objID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Type /%s\n", o.getType())
+	fmt.Fprintf(w, "  /Name %s\n", name)
+	switch o.option.Usage {
+	case LayerUsagePrint:
+		io.WriteString(w, "  /Usage << /Print << /PrintState /ON >> /View << /ViewState /OFF >> >>\n")
+	case LayerUsageView:
+		io.WriteString(w, "  /Usage << /Print << /PrintState /OFF >> /View << /ViewState /ON >> >>\n")
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+func (o *OCGObj) procsetIdentifier() string {
+	return fmt.Sprintf("OC%d", o.id)
+}
+
+// visible returns true if the layer is shown when the document is opened
+func (o *OCGObj) visible() bool {
+	return o.option.Visible && o.option.Usage != LayerUsagePrint
+}
+
+// layers the layers of the document
+type layers struct {
+	list        []*OCGObj
+	radioGroups [][]int
+	current     int
+	openPane    bool
+}
+
+// AddLayer defines a layer that can be shown or hidden when the document is
+// displayed. name specifies the layer name that the document reader will
+// display in the layer list. visible specifies whether the layer will be
+// initially visible. The return value is an ID that is used in a call to
+// BeginLayer().
+func (gp *Fpdf) AddLayer(name string, visible bool) int {
+	return gp.AddLayerWithOption(name, LayerOption{Visible: visible})
+}
+
+// AddLayerWithOption defines a layer like AddLayer. The usage of the option
+// restricts the layer to be only printed or only shown on screen.
+func (gp *Fpdf) AddLayerWithOption(name string, opt LayerOption) int {
+	ocg := &OCGObj{
+		name:   name,
+		option: opt,
+		id:     len(gp.layers.list),
+	}
+	ocg.init(func() *Fpdf {
+		return gp
+	})
+
+	_, ocg.index = gp.addProcsetObj(ocg)
+	gp.layers.list = append(gp.layers.list, ocg)
+	return ocg.id
+}
+
+// AddLayerRadioGroup makes the layers mutually exclusive, showing one of them
+// hides the others.
+func (gp *Fpdf) AddLayerRadioGroup(ids ...int) error {
+	for _, id := range ids {
+		if id < 0 || id >= len(gp.layers.list) {
+			return fmt.Errorf("layer %d does not exist", id)
+		}
+	}
+
+	gp.layers.radioGroups = append(gp.layers.radioGroups, ids)
+	return nil
+}
+
+// BeginLayer is called to begin adding content to the specified layer. All
+// content added between a call to BeginLayer and a call to EndLayer is added
+// to the layer specified by id, also when pages are added. Headers and footers
+// are not part of the layer.
+func (gp *Fpdf) BeginLayer(id int) error {
+	if id < 0 || id >= len(gp.layers.list) {
+		return fmt.Errorf("layer %d does not exist", id)
+	}
+
+	gp.EndLayer()
+	gp.layers.current = id
+	gp.resumeLayer()
+	return nil
+}
+
+// EndLayer is called to stop adding content to the currently active layer.
+// See BeginLayer for more details.
+func (gp *Fpdf) EndLayer() {
+	gp.suspendLayer()
+	gp.layers.current = -1
+}
+
+// OpenLayerPane advises the document reader to open the layer pane when the
+// document is initially displayed.
+func (gp *Fpdf) OpenLayerPane() {
+	gp.layers.openPane = true
+}
+
+// suspendLayer ends the marked content of the active layer on the current page
+func (gp *Fpdf) suspendLayer() {
+	gp.unmarkContent()
+	page := gp.currentPage()
+	if page == nil || !page.layerOpen {
+		return
+	}
+
+	gp.currentContent().AppendStreamLayerEnd()
+	page.layerOpen = false
+}
+
+// resumeLayer begins the marked content of the active layer on the current page
+func (gp *Fpdf) resumeLayer() {
+	gp.unmarkContent()
+	page := gp.currentPage()
+	if page == nil || page.layerOpen || gp.layers.current < 0 {
+		return
+	}
+
+	gp.currentContent().AppendStreamLayerBegin(gp.layers.list[gp.layers.current].procsetIdentifier())
+	page.layerOpen = true
+}
+
+// writeOCProperties writes the optional content properties of the catalog,
+// the default configuration is named as PDF/A requires if named is true
+func (l *layers) writeOCProperties(w io.Writer, named bool) {
+	refs := func(ocgs []*OCGObj) string {
+		s := make([]string, len(ocgs))
+		for x := 0; x < len(ocgs); x++ {
+			s[x] = fmt.Sprintf("%d 0 R", ocgs[x].index+1)
+		}
+		return strings.Join(s, " ")
+	}
+
+	// layers restricted to print or view follow the usage of the event
+	var off, usage []*OCGObj
+	for _, ocg := range l.list {
+		if !ocg.visible() {
+			off = append(off, ocg)
+		}
+		if ocg.option.Usage != LayerUsageAll {
+			usage = append(usage, ocg)
+		}
+	}
+
+	io.WriteString(w, "  /OCProperties <<\n")
+	fmt.Fprintf(w, "    /OCGs [%s]\n", refs(l.list))
+	io.WriteString(w, "    /D <<\n")
+	if named {
+		io.WriteString(w, "      /Name (Layers)\n")
+	}
+	fmt.Fprintf(w, "      /Order [%s]\n", refs(l.list))
+	fmt.Fprintf(w, "      /OFF [%s]\n", refs(off))
+	if len(l.radioGroups) > 0 {
+		io.WriteString(w, "      /RBGroups [")
+		for _, group := range l.radioGroups {
+			ocgs := make([]*OCGObj, len(group))
+			for x, id := range group {
+				ocgs[x] = l.list[id]
+			}
+			fmt.Fprintf(w, "[%s]", refs(ocgs))
+		}
+		io.WriteString(w, "]\n")
+	}
+	if len(usage) > 0 {
+		io.WriteString(w, "      /AS [\n")
+		fmt.Fprintf(w, "        << /Event /View /OCGs [%s] /Category [/View] >>\n", refs(usage))
+		fmt.Fprintf(w, "        << /Event /Print /OCGs [%s] /Category [/Print] >>\n", refs(usage))
+		io.WriteString(w, "      ]\n")
+	}
+	io.WriteString(w, "    >>\n")
+	io.WriteString(w, "  >>\n")
+}
@@ -0,0 +1,94 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestLayers(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+
+	footers := 0
+	pdf.SetFooterFunc(func() {
+		footers++
+		pdf.Line(10, 190, 190, 190)
+	})
+
+	customer := pdf.AddLayer("Customer copy", true)
+	internal := pdf.AddLayerWithOption("Internal annotations", LayerOption{Visible: true, Usage: LayerUsageView})
+	watermark := pdf.AddLayerWithOption("Watermark", LayerOption{Visible: true, Usage: LayerUsagePrint})
+	pdf.OpenLayerPane()
+
+	if err := pdf.AddLayerRadioGroup(customer, internal); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.AddLayerRadioGroup(customer, 5); err == nil {
+		t.Error("a radio group with an unknown layer should fail")
+	}
+	if err := pdf.BeginLayer(5); err == nil {
+		t.Error("beginning an unknown layer should fail")
+	}
+
+	pdf.AddPage()
+	if err := pdf.BeginLayer(customer); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.Line(10, 10, 100, 10)
+	if err := pdf.BeginLayer(internal); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.Line(10, 20, 100, 20)
+
+	// the layer continues on the next page and stays open at the end
+	pdf.AddPage()
+	pdf.Line(10, 30, 100, 30)
+	if err := pdf.BeginLayer(watermark); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if bytes.Count(b, []byte(" BDC\n")) != bytes.Count(b, []byte("EMC\n")) {
+		t.Error("the marked content should be balanced")
+	}
+
+	ocgs := pdf.layers.list
+	for _, s := range []string{
+		"/Type /OCG",
+		"/Properties <<\n/OC0 ",
+		"/OC /OC0 BDC\n",
+		"/OC /OC1 BDC\n",
+		"/OC /OC2 BDC\n",
+		"/Usage << /Print << /PrintState /OFF >> /View << /ViewState /ON >> >>",
+		"/OFF [" + refString(ocgs[watermark]) + "]",
+		"/RBGroups [[" + refString(ocgs[customer]) + " " + refString(ocgs[internal]) + "]]",
+		"/Event /Print",
+		"/PageMode /UseOC",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+
+	if footers != 2 {
+		t.Errorf("expected 2 footers, found %d", footers)
+	}
+}
+
+func refString(o *OCGObj) string {
+	return fmt.Sprintf("%d 0 R", o.index+1)
+}
@@ -1,5 +1,39 @@
 package gofpdf
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// highlighting of links when they are clicked
+const (
+	// HighlightInvert inverts the area of the link
+	HighlightInvert = iota
+	// HighlightNone does not highlight the link
+	HighlightNone
+	// HighlightOutline inverts the border of the link
+	HighlightOutline
+	// HighlightPush shows the area of the link pushed down
+	HighlightPush
+)
+
+var highlightNames = map[int]string{
+	HighlightInvert:  "I",
+	HighlightNone:    "N",
+	HighlightOutline: "O",
+	HighlightPush:    "P",
+}
+
+// LinkStyle the border and highlighting of links
+type LinkStyle struct {
+	BorderWidth float64   // the width of the border in units, 0 for none
+	BorderColor Color     // the color of the border, nil for black
+	Dash        []float64 // the dash pattern of the border in units, nil for solid
+	Highlight   int       // one of the Highlight constants
+}
+
 type anchorOption struct {
 	page int
 	y    float64
@@ -9,4 +43,163 @@ type linkOption struct {
 	x, y, w, h float64
 	url        string
 	anchor     string
+	dest       *Destination
+	file       string // the document of a remote destination or the launched file
+	style      LinkStyle
+}
+
+// SetLinkStyle sets the border and highlighting of the links added
+// afterwards. Links have no border and are inverted by default.
+func (gp *Fpdf) SetLinkStyle(style LinkStyle) error {
+	if _, ok := highlightNames[style.Highlight]; !ok {
+		return fmt.Errorf("highlight %d is not valid", style.Highlight)
+	}
+	if style.BorderColor != nil && colorComponents(style.BorderColor) == "" {
+		return errors.New("the border color of a link must be gray, RGB or CMYK")
+	}
+
+	gp.UnitsToPointsVar(&style.BorderWidth)
+	dash := make([]float64, len(style.Dash))
+	for x, d := range style.Dash {
+		dash[x] = gp.UnitsToPoints(d)
+	}
+	if len(dash) > 0 {
+		style.Dash = dash
+	}
+	gp.curr.linkStyle = style
+	return nil
+}
+
+// AddPageLink adds a link to a page of the document or to a named
+// destination, see Destination and AddNamedDestination
+func (gp *Fpdf) AddPageLink(dest Destination, x, y, w, h float64) error {
+	if err := dest.validate(); err != nil {
+		return err
+	}
+	dest = dest.toPoints(gp)
+	return gp.addLink(x, y, w, h, linkOption{dest: &dest})
+}
+
+// AddRemoteLink adds a link to a page or a named destination of the
+// document in file, the pages of the other document are assumed to be of
+// the size of the pages of this document
+func (gp *Fpdf) AddRemoteLink(file string, dest Destination, x, y, w, h float64) error {
+	if file == "" {
+		return errors.New("a remote link needs a file")
+	}
+	if err := dest.validate(); err != nil {
+		return err
+	}
+	dest = dest.toPoints(gp)
+	return gp.addLink(x, y, w, h, linkOption{dest: &dest, file: file})
+}
+
+// AddLaunchLink adds a link that opens file with the application the viewer
+// associates with it
+func (gp *Fpdf) AddLaunchLink(file string, x, y, w, h float64) error {
+	if file == "" {
+		return errors.New("a launch link needs a file")
+	}
+	return gp.addLink(x, y, w, h, linkOption{file: file})
+}
+
+// addLink adds the link to the current page at x, y, w, h with the current
+// link style
+func (gp *Fpdf) addLink(x, y, w, h float64, link linkOption) error {
+	page := gp.currentPage()
+	if page == nil {
+		return errors.New("a link needs a page")
+	}
+	gp.UnitsToPointsVar(&x, &y, &w, &h)
+	link.x, link.y, link.w, link.h = x, gp.GetBoundaryHeight(PageBoundaryMedia)-y, w, h
+	link.style = gp.curr.linkStyle
+	page.Links = append(page.Links, link)
+	return nil
+}
+
+// writeLink writes the annotation of the link, links to missing anchors are
+// skipped
+func (p *PageObj) writeLink(w io.Writer, l linkOption, objID int) error {
+	gp := p.getRoot()
+	var target string
+	switch {
+	case l.url != "":
+		url, err := encodeLinkURL(gp.protection(), objID, l.url)
+		if err != nil {
+			return err
+		}
+		target = fmt.Sprintf("/A <</S /URI /URI (%s)>>", url)
+	case l.anchor != "":
+		a, ok := gp.anchors[l.anchor]
+		if !ok {
+			return nil
+		}
+		target = fmt.Sprintf("/Dest [%d 0 R /XYZ 0 %.2f null]", a.page+1, a.y)
+	case l.dest != nil && l.file != "":
+		file, err := encodeString([]byte(l.file), gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		dest, err := gp.remoteDestinationArray(*l.dest, objID)
+		if err != nil {
+			return err
+		}
+		target = fmt.Sprintf("/A <</S /GoToR /F %s /D %s>>", file, dest)
+	case l.dest != nil:
+		dest, err := gp.destinationArray(*l.dest, objID)
+		if err != nil {
+			return err
+		}
+		target = "/Dest " + dest
+	default:
+		file, err := encodeString([]byte(l.file), gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		target = fmt.Sprintf("/A <</S /Launch /F %s>>", file)
+	}
+
+	_, err := fmt.Fprintf(w, "<</Type /Annot /Subtype /Link /F 4 /Rect [%.2f %.2f %.2f %.2f] %s %s>>",
+		l.x, l.y, l.x+l.w, l.y-l.h, l.style.entries(), target)
+	return err
+}
+
+// entries returns the entries of the link annotation setting the style
+func (s LinkStyle) entries() string {
+	if s.BorderWidth <= 0 {
+		if s.Highlight == HighlightInvert {
+			return "/Border [0 0 0]"
+		}
+		return fmt.Sprintf("/Border [0 0 0] /H /%s", highlightNames[s.Highlight])
+	}
+
+	var b strings.Builder
+	if len(s.Dash) > 0 {
+		fmt.Fprintf(&b, "/BS <</W %.2f /S /D /D [%s]>>", s.BorderWidth, formatNumbers(s.Dash))
+	} else {
+		fmt.Fprintf(&b, "/BS <</W %.2f /S /S>>", s.BorderWidth)
+	}
+	if s.BorderColor != nil {
+		fmt.Fprintf(&b, " /C [%s]", colorComponents(s.BorderColor))
+	}
+	if s.Highlight != HighlightInvert {
+		fmt.Fprintf(&b, " /H /%s", highlightNames[s.Highlight])
+	}
+	return b.String()
+}
+
+// encodeLinkURL returns the escaped url of a link, encrypted for objID
+func encodeLinkURL(protection *PDFProtection, objID int, url string) (string, error) {
+	if protection != nil {
+		tmp, err := protection.encrypt(objID, []byte(url))
+		if err != nil {
+			return "", err
+		}
+		url = string(tmp)
+	}
+	url = strings.Replace(url, "\\", "\\\\", -1)
+	url = strings.Replace(url, "(", "\\(", -1)
+	url = strings.Replace(url, ")", "\\)", -1)
+	url = strings.Replace(url, "\r", "\\r", -1)
+	return url, nil
 }
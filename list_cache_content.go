@@ -65,6 +65,7 @@ func (l *listCacheContent) appendContentText(cache cacheContentText, text string
 }
 
 func (l *listCacheContent) write(w io.Writer, protection *PDFProtection) error {
+	layerNest := 0
 	for _, cache := range l.caches {
 		if _, ok := cache.(*cacheContentTransformEnd); ok {
 			l.transformNest--
@@ -99,6 +100,18 @@ func (l *listCacheContent) write(w io.Writer, protection *PDFProtection) error {
 		if _, ok := cache.(*cacheContentClipBegin); ok {
 			l.clipNest++
 		}
+
+		switch cache.(type) {
+		case *cacheContentLayerBegin:
+			layerNest++
+		case *cacheContentLayerEnd:
+			layerNest--
+		}
+	}
+
+	// a layer still active on the page is closed with the content
+	for ; layerNest > 0; layerNest-- {
+		io.WriteString(w, "EMC\n")
 	}
 	return nil
 }
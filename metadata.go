@@ -0,0 +1,327 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+	"unicode"
+)
+
+const metadataType = "Metadata"
+
+// MetadataObj the XMP metadata stream of the document
+type MetadataObj struct { //impl IObj
+	getRoot func() *Fpdf
+}
+
+func (m *MetadataObj) init(funcGetRoot func() *Fpdf) {
+	m.getRoot = funcGetRoot
+}
+
+func (m *MetadataObj) getType() string {
+	return metadataType
+}
+
+func (m *MetadataObj) write(w io.Writer, objID int) error {
+	gp := m.getRoot()
+	data, err := encryptStream(gp.protection(), objID, gp.xmpPacket())
+	if err != nil {
+		return err
+	}
+
+	// the metadata is not compressed, so it can be found without parsing
+	// the document
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /Metadata\n")
+	io.WriteString(w, "  /Subtype /XML\n")
+	fmt.Fprintf(w, "  /Length %d\n", len(data))
+	io.WriteString(w, ">>\n")
+	io.WriteString(w, "stream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
+	return nil
+}
+
+// namespaces of the properties written from the information dictionary,
+// prefix -> uri
+var xmpNamespaces = map[string]string{
+	"x":             "adobe:ns:meta/",
+	"rdf":           "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"xml":           "http://www.w3.org/XML/1998/namespace",
+	"dc":            "http://purl.org/dc/elements/1.1/",
+	"pdf":           "http://ns.adobe.com/pdf/1.3/",
+	"xmp":           "http://ns.adobe.com/xap/1.0/",
+	"pdfaid":        "http://www.aiim.org/pdfa/ns/id/",
+	"pdfaExtension": "http://www.aiim.org/pdfa/ns/extension/",
+	"pdfaSchema":    "http://www.aiim.org/pdfa/ns/schema#",
+	"pdfaProperty":  "http://www.aiim.org/pdfa/ns/property#",
+}
+
+// xmpMetadata the XMP metadata of the document
+type xmpMetadata struct {
+	// enabled is true if the metadata is written without a conformance level
+	enabled bool
+	// packet replaces the generated metadata if it is not nil
+	packet []byte
+	// custom properties by namespace, in the order they are added
+	namespaces []*xmpNamespace
+}
+
+// xmpNamespace the custom properties of a namespace, name -> value
+type xmpNamespace struct {
+	prefix     string
+	uri        string
+	properties map[string]string
+}
+
+type xmpPdfOption struct{}
+
+func (x *xmpPdfOption) apply(gp *Fpdf) error {
+	gp.xmp.enabled = true
+	return nil
+}
+
+// PdfOptionXMPMetadata creates a PdfOption that writes XMP metadata
+// mirroring the document information dictionary. A document with a
+// conformance level, custom XMP properties or an XMP packet always has
+// metadata.
+func PdfOptionXMPMetadata() PdfOption {
+	return &xmpPdfOption{}
+}
+
+// SetXMPMetadata sets the XMP packet written as the metadata of the
+// document instead of the generated one, nil restores the generated
+// metadata. The packet must be well-formed XML; it is written as is, so it
+// should match the information dictionary.
+func (gp *Fpdf) SetXMPMetadata(packet []byte) error {
+	if packet == nil {
+		gp.xmp.packet = nil
+		return nil
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(packet))
+	elements := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid XMP packet: %v", err)
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			elements++
+		}
+	}
+	if elements == 0 {
+		return errors.New("invalid XMP packet: no element")
+	}
+	gp.xmp.packet = append([]byte(nil), packet...)
+	return nil
+}
+
+// AddXMPProperty adds a property with a text value to the generated XMP
+// metadata, in the namespace uri with the prefix, e.g. an invoice schema.
+// Adding a property again replaces its value. The namespaces of the
+// properties mirrored from the information dictionary cannot be used.
+func (gp *Fpdf) AddXMPProperty(prefix, uri, name, value string) error {
+	if !isXMLName(prefix) || !isXMLName(name) {
+		return fmt.Errorf("invalid XMP property %s:%s", prefix, name)
+	}
+	if uri == "" {
+		return fmt.Errorf("the namespace of prefix %s has no uri", prefix)
+	}
+	if reserved, ok := xmpNamespaces[prefix]; ok {
+		return fmt.Errorf("prefix %s is reserved for namespace %s", prefix, reserved)
+	}
+	for reserved, u := range xmpNamespaces {
+		if u == uri {
+			return fmt.Errorf("namespace %s is reserved for prefix %s", uri, reserved)
+		}
+	}
+
+	for _, ns := range gp.xmp.namespaces {
+		if ns.prefix == prefix && ns.uri != uri {
+			return fmt.Errorf("prefix %s is already bound to namespace %s", prefix, ns.uri)
+		}
+		if ns.uri == uri && ns.prefix != prefix {
+			return fmt.Errorf("namespace %s is already bound to prefix %s", uri, ns.prefix)
+		}
+		if ns.uri == uri {
+			ns.properties[name] = value
+			return nil
+		}
+	}
+	gp.xmp.namespaces = append(gp.xmp.namespaces, &xmpNamespace{
+		prefix:     prefix,
+		uri:        uri,
+		properties: map[string]string{name: value},
+	})
+	return nil
+}
+
+// hasMetadata returns true if the document is written with XMP metadata
+func (gp *Fpdf) hasMetadata() bool {
+	return gp.xmp.enabled || gp.xmp.packet != nil || len(gp.xmp.namespaces) > 0 || gp.conformance != 0
+}
+
+// addMetadataObj adds the metadata stream of the document
+func (gp *Fpdf) addMetadataObj() {
+	if gp.pdfObjs.indexOfFirst(metadataType) < 0 {
+		metadata := new(MetadataObj)
+		metadata.init(func() *Fpdf {
+			return gp
+		})
+		gp.addObj(metadata)
+	}
+}
+
+// writeMetadata writes the metadata entry of the catalog
+func (gp *Fpdf) writeMetadata(w io.Writer) {
+	if index := gp.pdfObjs.indexOfFirst(metadataType); index >= 0 {
+		fmt.Fprintf(w, "  /Metadata %d 0 R\n", index+1)
+	}
+}
+
+// xmpPacket returns the XMP metadata of the document, the packet set by
+// SetXMPMetadata or one mirroring its information dictionary
+func (gp *Fpdf) xmpPacket() []byte {
+	if gp.xmp.packet != nil {
+		return gp.xmp.packet
+	}
+	info := gp.GetInfo()
+	date := xmpDate(gp.documentDate())
+
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\xef\xbb\xbf\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+
+	b.WriteString("  <rdf:Description rdf:about=\"\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if info.Title != "" {
+		fmt.Fprintf(&b, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(info.Title))
+	}
+	if info.Author != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(info.Author))
+	}
+	if info.Subject != "" {
+		fmt.Fprintf(&b, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(info.Subject))
+	}
+	if gp.lang != "" {
+		fmt.Fprintf(&b, "   <dc:language><rdf:Bag><rdf:li>%s</rdf:li></rdf:Bag></dc:language>\n", xmlEscape(gp.lang))
+	}
+	b.WriteString("  </rdf:Description>\n")
+
+	b.WriteString("  <rdf:Description rdf:about=\"\" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\">\n")
+	if info.Keywords != "" {
+		fmt.Fprintf(&b, "   <pdf:Keywords>%s</pdf:Keywords>\n", xmlEscape(info.Keywords))
+	}
+	if info.Producer != "" {
+		fmt.Fprintf(&b, "   <pdf:Producer>%s</pdf:Producer>\n", xmlEscape(info.Producer))
+	}
+	b.WriteString("  </rdf:Description>\n")
+
+	b.WriteString("  <rdf:Description rdf:about=\"\" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\">\n")
+	if info.Creator != "" {
+		fmt.Fprintf(&b, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", xmlEscape(info.Creator))
+	}
+	fmt.Fprintf(&b, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", date)
+	fmt.Fprintf(&b, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", date)
+	fmt.Fprintf(&b, "   <xmp:MetadataDate>%s</xmp:MetadataDate>\n", date)
+	b.WriteString("  </rdf:Description>\n")
+
+	for _, ns := range gp.xmp.namespaces {
+		fmt.Fprintf(&b, "  <rdf:Description rdf:about=\"\" xmlns:%s=\"%s\">\n", ns.prefix, xmlEscape(ns.uri))
+		for _, name := range ns.names() {
+			fmt.Fprintf(&b, "   <%s:%s>%s</%s:%s>\n", ns.prefix, name, xmlEscape(ns.properties[name]), ns.prefix, name)
+		}
+		b.WriteString("  </rdf:Description>\n")
+	}
+
+	if gp.conformance == PDFA2B {
+		b.WriteString("  <rdf:Description rdf:about=\"\" xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\">\n")
+		b.WriteString("   <pdfaid:part>2</pdfaid:part>\n")
+		b.WriteString("   <pdfaid:conformance>B</pdfaid:conformance>\n")
+		b.WriteString("  </rdf:Description>\n")
+		if len(gp.xmp.namespaces) > 0 {
+			gp.writeXMPExtensionSchemas(&b)
+		}
+	}
+
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString("<?xpacket end=\"w\"?>")
+	return b.Bytes()
+}
+
+// writeXMPExtensionSchemas writes the descriptions of the custom namespaces
+// PDF/A requires for the properties that are not predefined by XMP
+func (gp *Fpdf) writeXMPExtensionSchemas(b *bytes.Buffer) {
+	b.WriteString("  <rdf:Description rdf:about=\"\" xmlns:pdfaExtension=\"http://www.aiim.org/pdfa/ns/extension/\"")
+	b.WriteString(" xmlns:pdfaSchema=\"http://www.aiim.org/pdfa/ns/schema#\" xmlns:pdfaProperty=\"http://www.aiim.org/pdfa/ns/property#\">\n")
+	b.WriteString("   <pdfaExtension:schemas><rdf:Bag>\n")
+	for _, ns := range gp.xmp.namespaces {
+		b.WriteString("    <rdf:li rdf:parseType=\"Resource\">\n")
+		fmt.Fprintf(b, "     <pdfaSchema:schema>%s properties</pdfaSchema:schema>\n", ns.prefix)
+		fmt.Fprintf(b, "     <pdfaSchema:namespaceURI>%s</pdfaSchema:namespaceURI>\n", xmlEscape(ns.uri))
+		fmt.Fprintf(b, "     <pdfaSchema:prefix>%s</pdfaSchema:prefix>\n", ns.prefix)
+		b.WriteString("     <pdfaSchema:property><rdf:Seq>\n")
+		for _, name := range ns.names() {
+			b.WriteString("      <rdf:li rdf:parseType=\"Resource\">\n")
+			fmt.Fprintf(b, "       <pdfaProperty:name>%s</pdfaProperty:name>\n", name)
+			b.WriteString("       <pdfaProperty:valueType>Text</pdfaProperty:valueType>\n")
+			b.WriteString("       <pdfaProperty:category>external</pdfaProperty:category>\n")
+			fmt.Fprintf(b, "       <pdfaProperty:description>%s</pdfaProperty:description>\n", name)
+			b.WriteString("      </rdf:li>\n")
+		}
+		b.WriteString("     </rdf:Seq></pdfaSchema:property>\n")
+		b.WriteString("    </rdf:li>\n")
+	}
+	b.WriteString("   </rdf:Bag></pdfaExtension:schemas>\n")
+	b.WriteString("  </rdf:Description>\n")
+}
+
+// names returns the names of the properties in order
+func (ns *xmpNamespace) names() []string {
+	names := make([]string, 0, len(ns.properties))
+	for name := range ns.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isXMLName returns true if s is an XML name without a colon
+func isXMLName(s string) bool {
+	for n, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (n == 0 || !unicode.IsDigit(r) && r != '-' && r != '.') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// documentDate returns the creation date of the document, set when it is
+// written first
+func (gp *Fpdf) documentDate() time.Time {
+	if gp.creationDate.IsZero() {
+		gp.creationDate = time.Now()
+	}
+	return gp.creationDate
+}
+
+// xmpDate formats the date as required by XMP
+func xmpDate(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05-07:00")
+}
+
+// xmlEscape returns s escaped as XML character data
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
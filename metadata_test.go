@@ -0,0 +1,142 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func metadataOf(t *testing.T, pdf *Fpdf) []byte {
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	if catalog["Metadata"] == nil {
+		return nil
+	}
+	return reader.resolve(catalog["Metadata"]).(pdfStream).Data
+}
+
+func TestXMPMetadata(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionLanguage("en"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if metadata := metadataOf(t, pdf); metadata != nil {
+		t.Errorf("a document should have no metadata by default, found %q", metadata)
+	}
+
+	pdf.SetInfo(PdfInfo{Title: "Invoice", Author: "Billing & Co", Subject: "March", Keywords: "invoice", Creator: "erp", Producer: "gofpdf"})
+	for _, p := range []struct{ prefix, uri, name, value string }{
+		{"inv", "http://example.com/invoice/", "Number", "2021-0042"},
+		{"inv", "http://example.com/invoice/", "Total", "<42.00>"},
+		{"dcterms", "http://purl.org/dc/terms/", "audience", "customers"},
+		{"inv", "http://example.com/invoice/", "Number", "2021-0043"},
+	} {
+		if err := pdf.AddXMPProperty(p.prefix, p.uri, p.name, p.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []struct{ prefix, uri, name string }{
+		{"dc", "http://purl.org/dc/elements/1.1/", "rights"},
+		{"ext", "http://ns.adobe.com/xap/1.0/", "Rating"},
+		{"inv", "http://example.com/other/", "Number"},
+		{"bill", "http://example.com/invoice/", "Number"},
+		{"inv", "http://example.com/invoice/", "1st"},
+		{"in:v", "http://example.com/invoice/", "Number"},
+		{"inv", "", "Number"},
+	} {
+		if err := pdf.AddXMPProperty(p.prefix, p.uri, p.name, ""); err == nil {
+			t.Errorf("%s:%s in %s should fail", p.prefix, p.name, p.uri)
+		}
+	}
+
+	metadata := metadataOf(t, pdf)
+	for _, s := range []string{
+		"<rdf:li xml:lang=\"x-default\">Invoice</rdf:li>",
+		"<rdf:li>Billing &amp; Co</rdf:li>",
+		"<rdf:li xml:lang=\"x-default\">March</rdf:li>",
+		"<rdf:li>en</rdf:li>",
+		"<pdf:Keywords>invoice</pdf:Keywords>",
+		"<pdf:Producer>gofpdf</pdf:Producer>",
+		"<xmp:CreatorTool>erp</xmp:CreatorTool>",
+		"<xmp:ModifyDate>",
+		"xmlns:inv=\"http://example.com/invoice/\"",
+		"<inv:Number>2021-0043</inv:Number>",
+		"<inv:Total>&lt;42.00&gt;</inv:Total>",
+		"<dcterms:audience>customers</dcterms:audience>",
+	} {
+		if !bytes.Contains(metadata, []byte(s)) {
+			t.Errorf("%q not found in the metadata", s)
+		}
+	}
+	if bytes.Contains(metadata, []byte("pdfaExtension")) {
+		t.Error("the extension schemas are only required by PDF/A")
+	}
+	if err := xml.Unmarshal(metadata, new(interface{})); err != nil {
+		t.Errorf("the metadata should be well-formed: %v", err)
+	}
+
+	packet := []byte("<?xpacket begin=\"\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?><x:xmpmeta xmlns:x=\"adobe:ns:meta/\"/><?xpacket end=\"w\"?>")
+	for _, invalid := range []string{"", "<x:xmpmeta>", "<?xpacket end=\"w\"?>"} {
+		if err := pdf.SetXMPMetadata([]byte(invalid)); err == nil {
+			t.Errorf("%q should fail", invalid)
+		}
+	}
+	if err := pdf.SetXMPMetadata(packet); err != nil {
+		t.Fatal(err)
+	}
+	if metadata := metadataOf(t, pdf); !bytes.Equal(metadata, packet) {
+		t.Errorf("the packet should be written as is, found %q", metadata)
+	}
+	pdf.SetXMPMetadata(nil)
+	if metadata := metadataOf(t, pdf); !bytes.Contains(metadata, []byte("<inv:Number>")) {
+		t.Errorf("the generated metadata should be restored, found %q", metadata)
+	}
+}
+
+func TestXMPMetadataOption(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionXMPMetadata(), PdfOptionTitle("Report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	metadata := metadataOf(t, pdf)
+	if !bytes.Contains(metadata, []byte("<dc:format>application/pdf</dc:format>")) || bytes.Contains(metadata, []byte("pdfaid")) {
+		t.Errorf("unexpected metadata %q", metadata)
+	}
+}
+
+func TestXMPMetadataConformance(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionConformance(PDFA2B))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.AddXMPProperty("inv", "http://example.com/invoice/", "Number", "42"); err != nil {
+		t.Fatal(err)
+	}
+	metadata := metadataOf(t, pdf)
+	for _, s := range []string{
+		"<pdfaSchema:namespaceURI>http://example.com/invoice/</pdfaSchema:namespaceURI>",
+		"<pdfaSchema:prefix>inv</pdfaSchema:prefix>",
+		"<pdfaProperty:name>Number</pdfaProperty:name>",
+	} {
+		if !bytes.Contains(metadata, []byte(s)) {
+			t.Errorf("%q not found in the metadata", s)
+		}
+	}
+
+	if err := pdf.SetXMPMetadata([]byte("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\"/>")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdf.GetBytesPdfReturnErr(); err == nil {
+		t.Error("a packet without the conformance level should fail")
+	}
+}
@@ -0,0 +1,190 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+const outlinesType = "Outlines"
+const outlineType = "Outline"
+
+// BookmarkOption option of a bookmark
+type BookmarkOption struct {
+	Closed bool  // the children of the bookmark are hidden when the document is opened
+	Style  int   // Regular|Bold|Italic
+	Color  Color // the color of the bookmark title, Gray or RGB, black by default
+}
+
+// OutlinesObj root of the document outline
+type OutlinesObj struct { //impl IObj
+	index    int
+	children []*OutlineObj
+	getRoot  func() *Fpdf
+}
+
+func (o *OutlinesObj) init(funcGetRoot func() *Fpdf) {
+	o.getRoot = funcGetRoot
+	o.children = make([]*OutlineObj, 0)
+}
+
+func (o *OutlinesObj) getType() string {
+	return outlinesType
+}
+
+func (o *OutlinesObj) write(w io.Writer, objID int) error {
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Type /%s\n", o.getType())
+	if len(o.children) > 0 {
+		fmt.Fprintf(w, "  /First %d 0 R\n", o.children[0].index+1)
+		fmt.Fprintf(w, "  /Last %d 0 R\n", o.children[len(o.children)-1].index+1)
+	}
+	fmt.Fprintf(w, "  /Count %d\n", countOutlines(o.children))
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// lastAtLevel returns the latest outline item at the given level, or nil
+// when the item would be a direct child of the root
+func (o *OutlinesObj) lastAtLevel(level int) *OutlineObj {
+	var item *OutlineObj
+	children := o.children
+	for x := 0; x <= level && len(children) > 0; x++ {
+		item = children[len(children)-1]
+		children = item.children
+	}
+	return item
+}
+
+// depth returns the level of the latest outline item, -1 if there is none
+func (o *OutlinesObj) depth() int {
+	depth := -1
+	children := o.children
+	for len(children) > 0 {
+		depth++
+		children = children[len(children)-1].children
+	}
+	return depth
+}
+
+// OutlineObj a single item of the document outline
+type OutlineObj struct { //impl IObj
+	title   string
+	dest    anchorOption
+	option  BookmarkOption
+	index   int
+	parent  int
+	prev    int
+	next    int
+	getRoot func() *Fpdf
+
+	children []*OutlineObj
+}
+
+func (o *OutlineObj) init(funcGetRoot func() *Fpdf) {
+	o.getRoot = funcGetRoot
+	o.children = make([]*OutlineObj, 0)
+	o.prev = -1
+	o.next = -1
+}
+
+func (o *OutlineObj) getType() string {
+	return outlineType
+}
+
+func (o *OutlineObj) write(w io.Writer, objID int) error {
+	title, err := encodeTextString(o.title, o.getRoot().protection(), objID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Title %s\n", title)
+	fmt.Fprintf(w, "  /Parent %d 0 R\n", o.parent+1)
+	if o.prev >= 0 {
+		fmt.Fprintf(w, "  /Prev %d 0 R\n", o.prev+1)
+	}
+	if o.next >= 0 {
+		fmt.Fprintf(w, "  /Next %d 0 R\n", o.next+1)
+	}
+	if len(o.children) > 0 {
+		fmt.Fprintf(w, "  /First %d 0 R\n", o.children[0].index+1)
+		fmt.Fprintf(w, "  /Last %d 0 R\n", o.children[len(o.children)-1].index+1)
+		count := countOutlines(o.children)
+		if o.option.Closed {
+			count = -count
+		}
+		fmt.Fprintf(w, "  /Count %d\n", count)
+	}
+	if o.dest.page >= 0 {
+		fmt.Fprintf(w, "  /Dest [%d 0 R /XYZ 0 %.2f null]\n", o.dest.page+1, o.dest.y)
+	}
+	if style := o.option.Style & (Bold | Italic); style != 0 {
+		fmt.Fprintf(w, "  /F %d\n", style)
+	}
+	// the color of an outline item is always in DeviceRGB
+	switch c := o.option.Color.(type) {
+	case Gray:
+		g := fixRange10(float64(c))
+		fmt.Fprintf(w, "  /C [%.2f %.2f %.2f]\n", g, g, g)
+	case RGB:
+		fmt.Fprintf(w, "  /C [%.2f %.2f %.2f]\n",
+			float64(c.R)*rgbManipulant, float64(c.G)*rgbManipulant, float64(c.B)*rgbManipulant)
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// countOutlines counts the visible descendants of an outline level
+func countOutlines(children []*OutlineObj) int {
+	count := len(children)
+	for x := 0; x < len(children); x++ {
+		if !children[x].option.Closed {
+			count += countOutlines(children[x].children)
+		}
+	}
+	return count
+}
+
+// AddBookmark adds an entry to the document outline pointing at the current
+// position of the current page. level 0 is a top level entry, any deeper
+// level is nested below the previous bookmark of the level above it.
+func (gp *Fpdf) AddBookmark(title string, level int) {
+	gp.AddBookmarkWithOption(title, level, BookmarkOption{})
+}
+
+// AddBookmarkWithOption adds an entry to the document outline pointing at the
+// current position of the current page using the given style.
+func (gp *Fpdf) AddBookmarkWithOption(title string, level int, opt BookmarkOption) {
+	outlines := gp.pdfObjs.outlines()
+
+	if level < 0 {
+		level = 0
+	}
+	// a bookmark can only be nested one level below the latest one
+	if depth := outlines.depth(); level > depth+1 {
+		level = depth + 1
+	}
+
+	item := new(OutlineObj)
+	item.init(func() *Fpdf {
+		return gp
+	})
+	item.title = title
+	item.option = opt
+	item.dest = gp.currentAnchor()
+	item.index = gp.addObj(item)
+
+	siblings := &outlines.children
+	item.parent = outlines.index
+	if parent := outlines.lastAtLevel(level - 1); parent != nil {
+		siblings = &parent.children
+		item.parent = parent.index
+	}
+
+	if l := len(*siblings); l > 0 {
+		prev := (*siblings)[l-1]
+		prev.next = item.index
+		item.prev = prev.index
+	}
+	*siblings = append(*siblings, item)
+}
@@ -0,0 +1,66 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	pdf, err := New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf.AddPage()
+	pdf.AddBookmark("Chapter 1", 0)
+	pdf.AddBookmark("Section 1.1", 1)
+	pdf.AddBookmarkWithOption("Section 1.2", 1, BookmarkOption{Closed: true, Style: Bold, Color: RGB{R: 255}})
+	pdf.AddBookmark("Section 1.2.1", 3)
+	pdf.AddPage()
+	pdf.AddBookmarkWithOption("Chapter 2", 0, BookmarkOption{Color: Gray(0.5)})
+
+	outlines := pdf.pdfObjs.outlines()
+	if len(outlines.children) != 2 {
+		t.Errorf("expected 2 top level bookmarks, found %d", len(outlines.children))
+		return
+	}
+
+	chapter1 := outlines.children[0]
+	if len(chapter1.children) != 2 {
+		t.Errorf("expected 2 sections, found %d", len(chapter1.children))
+		return
+	}
+
+	if chapter1.next != outlines.children[1].index || outlines.children[1].prev != chapter1.index {
+		t.Error("top level bookmarks are not linked")
+	}
+
+	// the level is clamped to one below the previous bookmark
+	section := chapter1.children[1]
+	if len(section.children) != 1 || section.children[0].parent != section.index {
+		t.Error("bookmark was not nested below the previous one")
+	}
+
+	// the closed section hides its child
+	if count := countOutlines(outlines.children); count != 4 {
+		t.Errorf("expected 4 visible bookmarks, found %d", count)
+	}
+
+	if outlines.children[1].dest.page != pdf.curr.IndexOfPageObj {
+		t.Error("bookmark does not point at the current page")
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !bytes.Contains(b, []byte("/PageMode /UseOutlines")) {
+		t.Error("catalog does not reference the outlines")
+	}
+	if !bytes.Contains(b, []byte("/C [1.00 0.00 0.00]")) || !bytes.Contains(b, []byte("/C [0.50 0.50 0.50]")) {
+		t.Error("the colors of the bookmarks are missing")
+	}
+}
@@ -0,0 +1,130 @@
+package gofpdf
+
+// SetHeaderFunc sets the function that lets the application render the page
+// header. The specified function is automatically called by AddPage() and
+// should not be called directly by the application. fn will typically be a
+// closure that has access to the Fpdf instance and other document generation
+// variables. Automatic page breaks are disabled while the header is rendered.
+func (gp *Fpdf) SetHeaderFunc(fn func()) {
+	gp.headerFunc = fn
+}
+
+// SetFooterFunc sets the function that lets the application render the page
+// footer. The specified function is called once for every page, when the page
+// is left by AddPage() or when the document is written, and should not be
+// called directly by the application. Automatic page breaks are disabled
+// while the footer is rendered.
+func (gp *Fpdf) SetFooterFunc(fn func()) {
+	gp.footerFunc = fn
+}
+
+// SetAutoPageBreak enables or disables the automatic page breaking mode. When
+// enabling, the second parameter is the distance from the bottom of the page
+// that defines the triggering limit, it replaces the bottom margin. By
+// default, the mode is on.
+func (gp *Fpdf) SetAutoPageBreak(auto bool, margin float64) {
+	gp.autoPageBreak = auto
+	gp.SetMarginBottom(margin)
+}
+
+// AutoPageBreak returns true if automatic pages breaks are enabled, false
+// otherwise. This is followed by the triggering limit from the bottom of the
+// page.
+func (gp *Fpdf) AutoPageBreak() (bool, float64) {
+	return gp.autoPageBreak, gp.MarginBottom()
+}
+
+// SetAcceptPageBreakFunc allows the application to control where page breaks
+// occur. fn is called whenever a page break condition is met, the break is
+// issued if true is returned. The default implementation returns a value
+// according to the mode selected by SetAutoPageBreak. fn can be used to move
+// the current position instead of breaking, for example to start the next
+// column of a multi column layout.
+func (gp *Fpdf) SetAcceptPageBreakFunc(fn func() bool) {
+	gp.acceptPageBreakFunc = fn
+}
+
+func (gp *Fpdf) acceptPageBreak() bool {
+	if gp.acceptPageBreakFunc != nil {
+		return gp.acceptPageBreakFunc()
+	}
+	return gp.autoPageBreak
+}
+
+// pageBreak adds a new page when an element of height h (in points) does not
+// fit below the current position anymore. The horizontal position is kept.
+// It returns true when a page was added.
+func (gp *Fpdf) pageBreak(h float64) bool {
+	return gp.pageBreakAt(gp.curr.Y, h)
+}
+
+// pageBreakAt adds a new page when an element of height h placed at y does
+// not fit on the current page anymore.
+func (gp *Fpdf) pageBreakAt(y, h float64) bool {
+	if gp.inHeader || gp.inFooter || gp.currentPage() == nil {
+		return false
+	}
+
+	if y+h <= gp.bottomMarginHeight() || !gp.acceptPageBreak() {
+		return false
+	}
+
+	x := gp.curr.X
+	gp.AddPage()
+	gp.curr.X = x
+	return true
+}
+
+// header renders the header of a newly added page
+func (gp *Fpdf) header() {
+	if gp.headerFunc == nil || gp.inHeader {
+		return
+	}
+
+	gp.suspendLayer()
+	gp.inHeader = true
+	gp.headerFunc()
+	gp.inHeader = false
+	gp.resumeLayer()
+}
+
+// footer renders the footer of the current page, once per page
+func (gp *Fpdf) footer() {
+	page := gp.currentPage()
+	if gp.footerFunc == nil || gp.inFooter || page == nil || page.footerDone {
+		return
+	}
+
+	gp.suspendLayer()
+	gp.inFooter = true
+	page.footerDone = true
+	gp.footerFunc()
+	gp.inFooter = false
+	gp.resumeLayer()
+}
+
+// closePages renders the footers of all pages that did not get one yet. The
+// current page is restored afterwards.
+func (gp *Fpdf) closePages() error {
+	if gp.footerFunc == nil {
+		return nil
+	}
+
+	current := gp.CurrentPageNumber()
+	pages := gp.getAllPages()
+	for x := 0; x < len(pages); x++ {
+		if pages[x].footerDone {
+			continue
+		}
+
+		if err := gp.SetPage(x + 1); err != nil {
+			return err
+		}
+		gp.footer()
+	}
+
+	if current > 0 {
+		return gp.SetPage(current)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFooter(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionMargin(10, 10, 10, 10))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	headers, footers := 0, 0
+	pdf.SetHeaderFunc(func() {
+		headers++
+		pdf.SetY(30)
+	})
+	pdf.SetFooterFunc(func() {
+		footers++
+		// page breaks are disabled in the footer
+		pdf.SetY(195)
+		pdf.Cell(100, 20, "footer")
+	})
+
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if pdf.Y() != 30 {
+		t.Errorf("the header should move the position, found %f", pdf.Y())
+	}
+
+	for x := 0; x < 20; x++ {
+		if err := pdf.Cell(50, 20, "cell"); err != nil {
+			t.Error(err)
+			return
+		}
+		pdf.SetX(10)
+		pdf.SetY(pdf.Y() + 20)
+	}
+
+	if headers != pdf.PageCount() || pdf.PageCount() < 3 {
+		t.Errorf("expected a header per page, found %d headers and %d pages", headers, pdf.PageCount())
+	}
+
+	if _, err := pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if footers != pdf.PageCount() {
+		t.Errorf("expected a footer per page, found %d footers and %d pages", footers, pdf.PageCount())
+	}
+}
+
+func TestAcceptPageBreak(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionMargin(10, 10, 10, 10))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// continue in a second column before breaking the page
+	column := 0
+	pdf.SetAcceptPageBreakFunc(func() bool {
+		if column == 0 {
+			column++
+			pdf.SetXY(100, 10)
+			return false
+		}
+		return true
+	})
+
+	lines := ""
+	for x := 0; x < 25; x++ {
+		lines += "line\n"
+	}
+
+	if err := pdf.MultiCell(80, 20, lines); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if pdf.PageCount() != 2 || pdf.X() != 100 {
+		t.Errorf("expected the text to use two columns, found %d pages", pdf.PageCount())
+	}
+
+	pdf.SetAcceptPageBreakFunc(nil)
+	pdf.SetAutoPageBreak(false, 10)
+	pdf.SetY(190)
+	if err := pdf.Cell(80, 20, "overflow"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if pdf.PageCount() != 2 {
+		t.Error("no page should be added when the automatic page break is disabled")
+	}
+}
+
+func TestImagePageBreak(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionMargin(10, 10, 10, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the image is 354x241 pixels, its height is computed from the width
+	pdf.AddPage()
+	if err := pdf.Image("test/res/gopher01.jpg", 10, 150, Rect{W: 100}); err != nil {
+		t.Fatal(err)
+	}
+	// the image is drawn in its own size
+	pdf.AddPage()
+	if err := pdf.Image("test/res/gopher01.jpg", 10, 100, Rect{}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 4 {
+		t.Fatalf("expected the images to break the page, found %d pages", len(pages))
+	}
+
+	// the images are drawn below the top margin of the new pages
+	height := 100 * 241 / 354.0
+	for x, expected := range map[int]string{
+		1: fmt.Sprintf("q 100.00 0 0 %0.2f 10.00 %0.2f cm", height, 190-height),
+		3: "q 199.00 0 0 135.00 10.00 55.00 cm",
+	} {
+		content, err := reader.decodeStream(reader.resolve(pages[x]["Contents"]).(pdfStream))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("%q not found in the content of page %d: %q", expected, x+1, content)
+		}
+	}
+}
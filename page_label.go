@@ -0,0 +1,198 @@
+package gofpdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// numbering styles of page labels
+const (
+	// PageLabelDecimal numbers pages 1, 2, 3
+	PageLabelDecimal = iota
+	// PageLabelUpperRoman numbers pages I, II, III
+	PageLabelUpperRoman
+	// PageLabelLowerRoman numbers pages i, ii, iii
+	PageLabelLowerRoman
+	// PageLabelUpperLetters numbers pages A to Z, then AA to ZZ
+	PageLabelUpperLetters
+	// PageLabelLowerLetters numbers pages a to z, then aa to zz
+	PageLabelLowerLetters
+	// PageLabelNone labels pages with the prefix only
+	PageLabelNone
+)
+
+// names of the numbering styles in the page label dictionary
+var pageLabelStyles = map[int]string{
+	PageLabelDecimal:      "D",
+	PageLabelUpperRoman:   "R",
+	PageLabelLowerRoman:   "r",
+	PageLabelUpperLetters: "A",
+	PageLabelLowerLetters: "a",
+}
+
+// pageLabel the labels of a range of pages
+type pageLabel struct {
+	style  int
+	prefix string
+	start  int
+}
+
+// number returns the label of the page at offset n within the range
+func (l pageLabel) number(n int) string {
+	n += l.start
+	switch l.style {
+	case PageLabelDecimal:
+		return l.prefix + strconv.Itoa(n)
+	case PageLabelUpperRoman:
+		return l.prefix + romanNumeral(n)
+	case PageLabelLowerRoman:
+		return l.prefix + strings.ToLower(romanNumeral(n))
+	case PageLabelUpperLetters:
+		return l.prefix + letterNumeral(n)
+	case PageLabelLowerLetters:
+		return l.prefix + strings.ToLower(letterNumeral(n))
+	}
+	return l.prefix
+}
+
+// SetPageLabel labels the pages starting at page fromPage up to the next
+// range of labels, the page number alias renders the label. The pages are
+// numbered in the style, one of the PageLabel constants, starting at start,
+// 1 if it is 0. The prefix precedes the number, e.g. "A-" for "A-1". Pages
+// before the first range are labeled with their page number.
+func (gp *Fpdf) SetPageLabel(fromPage int, style int, prefix string, start int) error {
+	if fromPage < 1 {
+		return errors.New("pages start at 1")
+	}
+	if _, ok := pageLabelStyles[style]; !ok && style != PageLabelNone {
+		return fmt.Errorf("unknown page label style %d", style)
+	}
+	if start < 0 {
+		return fmt.Errorf("page labels can not start at %d", start)
+	}
+	if start == 0 {
+		start = 1
+	}
+
+	if gp.pageLabels == nil {
+		gp.pageLabels = make(map[int]pageLabel)
+	}
+	gp.pageLabels[fromPage] = pageLabel{style: style, prefix: prefix, start: start}
+	return nil
+}
+
+// pageLabel returns the label of the page object at index
+func (gp *Fpdf) pageLabel(index int) string {
+	n := gp.pageNumber(index)
+	if n == 0 {
+		return ""
+	}
+
+	from := 0
+	for page := range gp.pageLabels {
+		if page <= n && page > from {
+			from = page
+		}
+	}
+	if from == 0 {
+		return strconv.Itoa(n)
+	}
+	return gp.pageLabels[from].number(n - from)
+}
+
+// addPageLabelChars adds the characters of the page labels to the fonts, the
+// page number alias may be rendered with any of them
+func (gp *Fpdf) addPageLabelChars() error {
+	if len(gp.pageLabels) == 0 {
+		return nil
+	}
+	hasAlias := false
+	for _, kind := range gp.aliases {
+		hasAlias = hasAlias || kind == aliasPageNumber
+	}
+	if !hasAlias {
+		return nil
+	}
+
+	var labels strings.Builder
+	for _, index := range gp.pdfObjs.typeMap[pageType] {
+		labels.WriteString(gp.pageLabel(index))
+	}
+	fonts := gp.getAllSubsetFonts()
+	for _, obj := range gp.pdfObjs.allOf(templateType) {
+		fonts = append(fonts, obj.(*TemplateObj).fonts...)
+	}
+	for _, font := range fonts {
+		if err := font.AddChars(labels.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePageLabels writes the number tree of the page labels of the catalog
+func (gp *Fpdf) writePageLabels(w io.Writer, objID int) error {
+	pages := make([]int, 0, len(gp.pageLabels))
+	for page := range gp.pageLabels {
+		if page <= gp.PageCount() {
+			pages = append(pages, page)
+		}
+	}
+	if len(pages) == 0 {
+		return nil
+	}
+	sort.Ints(pages)
+
+	io.WriteString(w, "  /PageLabels << /Nums [")
+	if pages[0] > 1 {
+		// the tree starts at the first page, the pages before the first
+		// range are labeled with their page number
+		io.WriteString(w, " 0 << /S /D >>")
+	}
+	for _, page := range pages {
+		label := gp.pageLabels[page]
+		fmt.Fprintf(w, " %d <<", page-1)
+		if style, ok := pageLabelStyles[label.style]; ok {
+			fmt.Fprintf(w, " /S /%s", style)
+		}
+		if label.prefix != "" {
+			prefix, err := encodeTextString(label.prefix, gp.protection(), objID)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, " /P %s", prefix)
+		}
+		if label.start != 1 {
+			fmt.Fprintf(w, " /St %d", label.start)
+		}
+		io.WriteString(w, " >>")
+	}
+	io.WriteString(w, " ] >>\n")
+	return nil
+}
+
+// romanNumeral returns n in upper case roman numerals
+func romanNumeral(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var buff strings.Builder
+	for x, v := range values {
+		for n >= v {
+			buff.WriteString(symbols[x])
+			n -= v
+		}
+	}
+	return buff.String()
+}
+
+// letterNumeral returns n as upper case letters, A to Z for 1 to 26, AA to
+// ZZ for 27 to 52 and so on
+func letterNumeral(n int) string {
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
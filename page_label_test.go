@@ -0,0 +1,149 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPageLabels(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionNoCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetPageLabel(0, PageLabelDecimal, "", 1); err == nil {
+		t.Error("a label before the first page should fail")
+	}
+	if err := pdf.SetPageLabel(1, 42, "", 1); err == nil {
+		t.Error("an unknown style should fail")
+	}
+
+	pdf.AliasPageNumber("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(180)
+		pdf.Cell(100, 10, "Page {pn}")
+	})
+	for x := 0; x < 7; x++ {
+		pdf.AddPage()
+		if err := pdf.SetFont("times", "", 12); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// front matter, the body and an appendix
+	labels := []struct {
+		from, style int
+		prefix      string
+		start       int
+	}{
+		{1, PageLabelLowerRoman, "", 0},
+		{4, PageLabelDecimal, "", 1},
+		{6, PageLabelDecimal, "A-", 1},
+		{20, PageLabelUpperLetters, "", 1},
+	}
+	for _, l := range labels {
+		if err := pdf.SetPageLabel(l.from, l.style, l.prefix, l.start); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	nums := reader.resolve(reader.resolve(catalog["PageLabels"]).(pdfDict)["Nums"]).(pdfArray)
+	if len(nums) != 6 {
+		t.Fatalf("expected 3 ranges beginning on a page, found %d entries", len(nums))
+	}
+	for x, expected := range []struct {
+		page  float64
+		style string
+	}{{0, "r"}, {3, "D"}, {5, "D"}} {
+		page, _ := pdfNumber(nums[x*2])
+		label := reader.resolve(nums[x*2+1]).(pdfDict)
+		if page != expected.page || label["S"] != pdfName(expected.style) {
+			t.Errorf("unexpected label %v at %v", label, page)
+		}
+	}
+	appendix := reader.resolve(nums[5]).(pdfDict)
+	if string(textString(appendix["P"])) != "A-" {
+		t.Errorf("unexpected prefix %v", appendix["P"])
+	}
+
+	font := pdf.curr.Font_ISubset
+	for _, text := range []string{"Page i", "Page iii", "Page 1", "Page 2", "Page A-1", "Page A-2"} {
+		var glyphs bytes.Buffer
+		if err := writeGlyphs(&glyphs, font, text); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b, glyphs.Bytes()) {
+			t.Errorf("%q not found in the document", text)
+		}
+	}
+}
+
+func TestPageLabelsFromLaterPage(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 6; x++ {
+		pdf.AddPage()
+	}
+	if err := pdf.SetPageLabel(5, PageLabelUpperRoman, "", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	nums := reader.resolve(reader.resolve(catalog["PageLabels"]).(pdfDict)["Nums"]).(pdfArray)
+	if len(nums) != 4 {
+		t.Fatalf("expected 2 ranges, found %d entries", len(nums))
+	}
+	// the pages before the label keep their page number
+	for x, expected := range []struct {
+		page  float64
+		style string
+	}{{0, "D"}, {4, "R"}} {
+		page, _ := pdfNumber(nums[x*2])
+		label := reader.resolve(nums[x*2+1]).(pdfDict)
+		if page != expected.page || label["S"] != pdfName(expected.style) || label["St"] != nil {
+			t.Errorf("unexpected label %v at %v", label, page)
+		}
+	}
+	if label := pdf.pageLabel(pdf.pdfObjs.typeMap[pageType][3]); label != "4" {
+		t.Errorf("page 4 should be labeled 4, found %q", label)
+	}
+}
+
+func TestPageLabelNumbers(t *testing.T) {
+	for _, tt := range []struct {
+		label    pageLabel
+		n        int
+		expected string
+	}{
+		{pageLabel{style: PageLabelUpperRoman, start: 1}, 13, "XIV"},
+		{pageLabel{style: PageLabelLowerRoman, start: 1990}, 0, "mcmxc"},
+		{pageLabel{style: PageLabelUpperLetters, start: 1}, 27, "BB"},
+		{pageLabel{style: PageLabelLowerLetters, prefix: "p.", start: 3}, 0, "p.c"},
+		{pageLabel{style: PageLabelNone, prefix: "Cover", start: 1}, 0, "Cover"},
+	} {
+		if s := tt.label.number(tt.n); s != tt.expected {
+			t.Errorf("expected %q, found %q", tt.expected, s)
+		}
+	}
+}
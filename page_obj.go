@@ -3,7 +3,6 @@ package gofpdf
 import (
 	"fmt"
 	"io"
-	"strings"
 )
 
 const pageType = "Page"
@@ -15,12 +14,39 @@ type PageObj struct { //impl IObj
 	Links             []linkOption
 	indexOfContentObj int
 	getRoot           func() *Fpdf
+
+	// indexes of the form fields and the other annotations shown on the page
+	fields      []int
+	annotations []int
+
+	// the state of the page when it was left, restored by SetPage
+	margins Margins
+	x, y    float64
+
+	footerDone bool
+	// the graphics state of SetAlpha at the end of the content, empty while
+	// it is opaque
+	extGState string
+	// the colors of SetFillColor and SetStrokeColor at the end of the content,
+	// nil while they are black
+	fillColor, strokeColor Color
+	// the marked content of the active layer is open on the page
+	layerOpen bool
+
+	// the key of the page in the parent tree, -1 without marked content
+	structParents int
+	// the structure elements of the marked content by mcid
+	mcids []*StructElemObj
+	// marked content of the element, nil for an artifact, is open on the page
+	marked     bool
+	markedElem *StructElemObj
 }
 
 func (p *PageObj) init(funcGetRoot func() *Fpdf) {
 	p.getRoot = funcGetRoot
 	p.Links = make([]linkOption, 0)
 	p.indexOfContentObj = -1
+	p.structParents = -1
 }
 
 func (p *PageObj) setOption(opt PageOption) {
@@ -37,21 +63,26 @@ func (p *PageObj) write(w io.Writer, objID int) error {
 	io.WriteString(w, "  /Parent 2 0 R\n")
 	fmt.Fprintf(w, "  /Resources %s\n", p.ResourcesRelate)
 
-	var err error
-	gp := p.getRoot()
-	if len(p.Links) > 0 {
+	if len(p.Links) > 0 || len(p.fields) > 0 || len(p.annotations) > 0 {
 		io.WriteString(w, "  /Annots [")
 		for _, l := range p.Links {
-			if l.url != "" {
-				err = p.writeExternalLink(w, l, objID)
-			} else {
-				err = p.writeInternalLink(w, l, gp.anchors)
-			}
-			if err != nil {
+			if err := p.writeLink(w, l, objID); err != nil {
 				return err
 			}
 		}
+		for _, index := range p.fields {
+			fmt.Fprintf(w, " %d 0 R", index+1)
+		}
+		for _, index := range p.annotations {
+			fmt.Fprintf(w, " %d 0 R", index+1)
+		}
 		io.WriteString(w, "]\n")
+		if p.getRoot().tags.enabled {
+			io.WriteString(w, "  /Tabs /S\n")
+		}
+	}
+	if p.structParents >= 0 {
+		fmt.Fprintf(w, "  /StructParents %d\n", p.structParents)
 	}
 
 	fmt.Fprintf(w, "  /Contents %d 0 R\n", p.indexOfContentObj+1)
@@ -60,36 +91,6 @@ func (p *PageObj) write(w io.Writer, objID int) error {
 	return nil
 }
 
-func (p *PageObj) writeExternalLink(w io.Writer, l linkOption, objID int) error {
-	protection := p.getRoot().protection()
-	url := l.url
-	if protection != nil {
-		tmp, err := rc4Cip(protection.objectkey(objID), []byte(url))
-		if err != nil {
-			return err
-		}
-		url = string(tmp)
-	}
-	url = strings.Replace(url, "\\", "\\\\", -1)
-	url = strings.Replace(url, "(", "\\(", -1)
-	url = strings.Replace(url, ")", "\\)", -1)
-	url = strings.Replace(url, "\r", "\\r", -1)
-
-	_, err := fmt.Fprintf(w, "<</Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] /A <</S /URI /URI (%s)>>>>",
-		l.x, l.y, l.x+l.w, l.y-l.h, url)
-	return err
-}
-
-func (p *PageObj) writeInternalLink(w io.Writer, l linkOption, anchors map[string]anchorOption) error {
-	a, ok := anchors[l.anchor]
-	if !ok {
-		return nil
-	}
-	_, err := fmt.Fprintf(w, "<</Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] /Dest [%d 0 R /XYZ 0 %.2f null]>>",
-		l.x, l.y, l.x+l.w, l.y-l.h, a.page+1, a.y)
-	return err
-}
-
 func (p *PageObj) getType() string {
 	return pageType
 }
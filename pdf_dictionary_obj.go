@@ -60,21 +60,17 @@ func (p *PdfDictionaryObj) write(w io.Writer, objID int) error {
 	}
 	gzipwriter.Close()
 
-	fmt.Fprintf(w, "<</Length %d\n", zbuff.Len())
+	data, err := encryptStream(p.protection(), objID, zbuff.Bytes())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "<</Length %d\n", len(data))
 	io.WriteString(w, "/Filter /FlateDecode\n")
 	fmt.Fprintf(w, "/Length1 %d\n", len(b))
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
-	if p.protection() != nil {
-		tmp, err := rc4Cip(p.protection().objectkey(objID), zbuff.Bytes())
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		//p.buffer.WriteString("\n")
-	} else {
-		w.Write(zbuff.Bytes())
-	}
+	w.Write(data)
 	io.WriteString(w, "\nendstream\n")
 
 	return nil
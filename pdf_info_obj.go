@@ -16,36 +16,59 @@ type PdfInfo struct {
 	Keywords string // The keywords associated with the document
 }
 
-// writeInfo writes the pdf info object to a writer in the pdf spec
-func (info *PdfInfo) write(w io.Writer) {
-	io.WriteString(w, "/Info <<\n")
+const infoType = "Info"
 
-	if info.Author != "" {
-		fmt.Fprintf(w, "/Author <FEFF%s>\n", encodeUtf8(info.Author))
-	}
+// PdfInfoObj the document information dictionary object
+type PdfInfoObj struct { //impl IObj
+	getRoot func() *Fpdf
+}
 
-	if info.Keywords != "" {
-		fmt.Fprintf(w, "/Keywords <FEFF%s>\n", encodeUtf8(info.Keywords))
-	}
+func (p *PdfInfoObj) init(funcGetRoot func() *Fpdf) {
+	p.getRoot = funcGetRoot
+}
 
-	if info.Title != "" {
-		fmt.Fprintf(w, "/Title <FEFF%s>\n", encodeUtf8(info.Title))
-	}
+func (p *PdfInfoObj) getType() string {
+	return infoType
+}
 
-	if info.Subject != "" {
-		fmt.Fprintf(w, "/Subject <FEFF%s>\n", encodeUtf8(info.Subject))
-	}
+func (p *PdfInfoObj) write(w io.Writer, objID int) error {
+	return p.getRoot().GetInfo().write(w, p.getRoot().protection(), objID, p.getRoot().documentDate())
+}
 
-	if info.Creator != "" {
-		fmt.Fprintf(w, "/Creator <FEFF%s>\n", encodeUtf8(info.Creator))
+// writeInfo writes the pdf info object to a writer in the pdf spec, the
+// strings are encrypted with the key of the object when protection is enabled
+func (info *PdfInfo) write(w io.Writer, protection *PDFProtection, objID int, created time.Time) error {
+	entries := []struct {
+		key   string
+		value string
+	}{
+		{"Author", info.Author},
+		{"Keywords", info.Keywords},
+		{"Title", info.Title},
+		{"Subject", info.Subject},
+		{"Creator", info.Creator},
+		{"Producer", info.Producer},
 	}
 
-	if info.Producer != "" {
-		fmt.Fprintf(w, "/Producer <FEFF%s>\n", encodeUtf8(info.Producer))
+	io.WriteString(w, "<<\n")
+	for _, entry := range entries {
+		if entry.value == "" {
+			continue
+		}
+		s, err := encodeTextString(entry.value, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "/%s %s\n", entry.key, s)
 	}
 
-	fmt.Fprintf(w, "/CreationDate(D:%s)>>\n", infodate(time.Now()))
-	io.WriteString(w, " >>\n")
+	date, err := encodeString([]byte("D:"+infodate(created)), protection, objID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "/CreationDate %s\n", date)
+	io.WriteString(w, ">>\n")
+	return nil
 }
 
 //SetInfo set Document Information Dictionary
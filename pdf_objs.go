@@ -61,15 +61,40 @@ func (p *pdfObjs) addProcsetObj(iobj ProcsetIObj) (string, int) {
 		procset := p.procset()
 		index = p.addObj(iobj)
 		p.procsetMap[pid] = index
-		if subfont, ok := iobj.(*SubsetFontObj); ok {
+		switch obj := iobj.(type) {
+		case *SubsetFontObj:
 			procset.Realtes = append(procset.Realtes,
 				RelateFont{
-					Family:     subfont.Family,
+					Family:     obj.Family,
 					IndexOfObj: index,
 					IdOfObj:    pid,
-					Style:      subfont.ttfFontOption.Style &^ Underline,
+					Style:      obj.ttfFontOption.Style &^ Underline,
 				})
-		} else {
+		case *ExtGStateObj:
+			procset.ExtGStates = append(procset.ExtGStates,
+				RealteXobject{
+					IndexOfObj: index,
+					IdOfObj:    pid,
+				})
+		case *ShadingObj:
+			procset.Shadings = append(procset.Shadings,
+				RealteXobject{
+					IndexOfObj: index,
+					IdOfObj:    pid,
+				})
+		case *SpotColorObj:
+			procset.ColorSpaces = append(procset.ColorSpaces,
+				RealteXobject{
+					IndexOfObj: index,
+					IdOfObj:    pid,
+				})
+		case *OCGObj:
+			procset.Properties = append(procset.Properties,
+				RealteXobject{
+					IndexOfObj: index,
+					IdOfObj:    pid,
+				})
+		default:
 			procset.RealteXobjs = append(procset.RealteXobjs,
 				RealteXobject{
 					IndexOfObj: index,
@@ -163,6 +188,51 @@ func (p *pdfObjs) allImages() []*ImageObj {
 	return iobjs
 }
 
+func (p *pdfObjs) allExtGStates() []*ExtGStateObj {
+	obs, ok := p.typeMap[extGStateType]
+	if !ok {
+		return make([]*ExtGStateObj, 0)
+	}
+
+	l := len(obs)
+	iobjs := make([]*ExtGStateObj, l)
+	for x := 0; x < l; x++ {
+		iobjs[x] = p.objs[obs[x]].(*ExtGStateObj)
+	}
+
+	return iobjs
+}
+
+func (p *pdfObjs) allShadings() []*ShadingObj {
+	obs, ok := p.typeMap[shadingType]
+	if !ok {
+		return make([]*ShadingObj, 0)
+	}
+
+	l := len(obs)
+	iobjs := make([]*ShadingObj, l)
+	for x := 0; x < l; x++ {
+		iobjs[x] = p.objs[obs[x]].(*ShadingObj)
+	}
+
+	return iobjs
+}
+
+func (p *pdfObjs) allSpotColors() []*SpotColorObj {
+	obs, ok := p.typeMap[spotColorType]
+	if !ok {
+		return make([]*SpotColorObj, 0)
+	}
+
+	l := len(obs)
+	iobjs := make([]*SpotColorObj, l)
+	for x := 0; x < l; x++ {
+		iobjs[x] = p.objs[obs[x]].(*SpotColorObj)
+	}
+
+	return iobjs
+}
+
 func (p *pdfObjs) allSubsetFonts() []*SubsetFontObj {
 	obs, ok := p.typeMap[subsetFontType]
 	if !ok {
@@ -191,9 +261,17 @@ func (p *pdfObjs) procset() *ProcSetObj {
 	return procset
 }
 
-func (p *pdfObjs) currentPage() *PageObj {
-	index := p.indexOfLatest(pageType)
-	return p.getPage(index)
+func (p *pdfObjs) outlines() *OutlinesObj {
+	index := p.indexOfFirst(outlinesType)
+	var outlines *OutlinesObj
+	if index < 0 {
+		outlines = new(OutlinesObj)
+		outlines.init(p.getRoot)
+		outlines.index = p.addObj(outlines)
+	} else {
+		outlines = p.objs[index].(*OutlinesObj)
+	}
+	return outlines
 }
 
 func (p *pdfObjs) pages() *PagesObj {
@@ -217,11 +295,6 @@ func (p *pdfObjs) getPage(index int) *PageObj {
 	return nil
 }
 
-func (p *pdfObjs) currentContent() *ContentObj {
-	page := p.currentPage()
-	return p.getPageContent(page)
-}
-
 func (p *pdfObjs) getPageContent(page *PageObj) *ContentObj {
 	var content *ContentObj
 	if page == nil {
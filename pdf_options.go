@@ -117,6 +117,7 @@ func PdfOptionNoCompress() PdfOption {
 }
 
 type protectionPdfOption struct {
+	encryption  int
 	permissions int
 	userpass    []byte
 	ownerpass   []byte
@@ -124,7 +125,7 @@ type protectionPdfOption struct {
 
 func (p *protectionPdfOption) apply(gp *Fpdf) error {
 	pro := new(PDFProtection)
-	err := pro.SetProtection(p.permissions, p.userpass, p.ownerpass)
+	err := pro.SetProtectionWithEncryption(p.encryption, p.permissions, p.userpass, p.ownerpass)
 
 	if err != nil {
 		return err
@@ -143,6 +144,28 @@ func PdfOptionProtection(permissions int, userpass string, ownerpass string) Pdf
 	}
 }
 
+// PdfOptionProtectionAES128 creates a PdfOption that set the protection for
+// the document, encrypted with 128-bit AES
+func PdfOptionProtectionAES128(permissions int, userpass string, ownerpass string) PdfOption {
+	return &protectionPdfOption{
+		encryption:  EncryptionAES128,
+		permissions: permissions,
+		userpass:    []byte(userpass),
+		ownerpass:   []byte(ownerpass),
+	}
+}
+
+// PdfOptionProtectionAES256 creates a PdfOption that set the protection for
+// the document, encrypted with 256-bit AES
+func PdfOptionProtectionAES256(permissions int, userpass string, ownerpass string) PdfOption {
+	return &protectionPdfOption{
+		encryption:  EncryptionAES256,
+		permissions: permissions,
+		userpass:    []byte(userpass),
+		ownerpass:   []byte(ownerpass),
+	}
+}
+
 type titlePdfOption struct {
 	title string
 }
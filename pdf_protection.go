@@ -1,9 +1,17 @@
 package gofpdf
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/md5"
+	crand "crypto/rand"
 	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
 	"math/rand"
 	"time"
 )
@@ -19,6 +27,16 @@ const (
 	PermissionsAnnotForms = 32
 )
 
+// encryption methods of the document
+const (
+	//EncryptionRC4 40-bit RC4 (V1/R2)
+	EncryptionRC4 = iota
+	//EncryptionAES128 128-bit AES with crypt filters (V4/R4)
+	EncryptionAES128
+	//EncryptionAES256 256-bit AES (V5/R6)
+	EncryptionAES256
+)
+
 var protectionPadding = []byte{
 	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
 	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
@@ -34,10 +52,15 @@ type PDFProtectionConfig struct {
 
 //PDFProtection protection in pdf
 type PDFProtection struct {
-	encrypted bool   //whether document is protected
-	uValue    []byte //U entry in pdf document
-	oValue    []byte //O entry in pdf document
-	pValue    int    //P entry in pdf document
+	encrypted  bool   //whether document is protected
+	encryption int    //EncryptionRC4|EncryptionAES128|EncryptionAES256
+	uValue     []byte //U entry in pdf document
+	oValue     []byte //O entry in pdf document
+	pValue     int    //P entry in pdf document
+	ueValue    []byte //UE entry in pdf document (AES-256)
+	oeValue    []byte //OE entry in pdf document (AES-256)
+	permsValue []byte //Perms entry in pdf document (AES-256)
+	fileID     []byte //first element of the ID of the trailer
 	//var $enc_obj_id;         //encryption object id
 	encryptionKey []byte
 }
@@ -47,12 +70,30 @@ func (p *PDFProtection) SetProtection(permissions int, userPass []byte, ownerPas
 	return p.setProtection(permissions, userPass, ownerPass)
 }
 
-func (p *PDFProtection) setProtection(permissions int, userPass []byte, ownerPass []byte) error {
+//SetProtectionWithEncryption set protection infomation, encrypting the
+//document with EncryptionRC4, EncryptionAES128 or EncryptionAES256
+func (p *PDFProtection) SetProtectionWithEncryption(encryption int, permissions int, userPass []byte, ownerPass []byte) error {
 	protection := 192 | permissions
 	if ownerPass == nil || len(ownerPass) == 0 {
 		ownerPass = p.randomPass(24)
 	}
-	return p.generateEncryptionKey(userPass, ownerPass, protection)
+
+	switch encryption {
+	case EncryptionRC4:
+		p.encryption = encryption
+		return p.generateEncryptionKey(userPass, ownerPass, protection)
+	case EncryptionAES128:
+		p.encryption = encryption
+		return p.generateAES128Key(userPass, ownerPass, protection)
+	case EncryptionAES256:
+		p.encryption = encryption
+		return p.generateAES256Key(userPass, ownerPass, protection)
+	}
+	return fmt.Errorf("unknown encryption %d", encryption)
+}
+
+func (p *PDFProtection) setProtection(permissions int, userPass []byte, ownerPass []byte) error {
+	return p.SetProtectionWithEncryption(EncryptionRC4, permissions, userPass, ownerPass)
 }
 
 func (p *PDFProtection) generateEncryptionKey(userPass []byte, ownerPass []byte, protection int) error {
@@ -75,6 +116,8 @@ func (p *PDFProtection) generateEncryptionKey(userPass []byte, ownerPass []byte,
 	}
 	p.uValue = uValue
 	p.pValue = -((protection ^ 255) + 1)
+	// the key of revision 2 is computed with an empty identifier
+	p.fileID = []byte{}
 
 	return nil
 }
@@ -86,12 +129,109 @@ func (p *PDFProtection) EncryptionObj() *EncryptionObj {
 
 func (p *PDFProtection) encryptionObj() *EncryptionObj {
 	var en EncryptionObj
+	en.encryption = p.encryption
 	en.oValue = p.oValue
 	en.pValue = p.pValue
 	en.uValue = p.uValue
+	en.oeValue = p.oeValue
+	en.ueValue = p.ueValue
+	en.permsValue = p.permsValue
 	return &en
 }
 
+// generateAES128Key computes the O and U entries and the encryption key of
+// revision 4 (algorithms 2, 3 and 5 of ISO 32000-1)
+func (p *PDFProtection) generateAES128Key(userPass []byte, ownerPass []byte, protection int) error {
+	fileID, err := randomBytes(16)
+	if err != nil {
+		return err
+	}
+	p.fileID = fileID
+	p.pValue = -((protection ^ 255) + 1)
+
+	userPassWithPadding := padPassword(userPass)
+	ownerKey := md5Rounds(padPassword(ownerPass))
+	p.oValue, err = rc4Rounds(ownerKey, userPassWithPadding)
+	if err != nil {
+		return err
+	}
+
+	var key []byte
+	key = append(key, userPassWithPadding...)
+	key = append(key, p.oValue...)
+	key = append(key, p.permissionsBytes()...)
+	key = append(key, p.fileID...)
+	p.encryptionKey = md5Rounds(key)
+
+	m := md5.New()
+	m.Write(protectionPadding)
+	m.Write(p.fileID)
+	uValue, err := rc4Rounds(p.encryptionKey, m.Sum(nil))
+	if err != nil {
+		return err
+	}
+	p.uValue = append(uValue, protectionPadding[:16]...)
+	return nil
+}
+
+// generateAES256Key computes the O, U, OE, UE and Perms entries of revision 6
+// around a random encryption key (algorithms 8, 9 and 10 of ISO 32000-2)
+func (p *PDFProtection) generateAES256Key(userPass []byte, ownerPass []byte, protection int) error {
+	fileID, err := randomBytes(16)
+	if err != nil {
+		return err
+	}
+	p.fileID = fileID
+	p.pValue = -((protection ^ 255) + 1)
+
+	p.encryptionKey, err = randomBytes(32)
+	if err != nil {
+		return err
+	}
+
+	// validation and key salts of the user and the owner
+	salts, err := randomBytes(32)
+	if err != nil {
+		return err
+	}
+
+	userPass = truncatePassword(userPass)
+	ownerPass = truncatePassword(ownerPass)
+
+	p.uValue = append(hardenedHash(userPass, salts[0:8], nil), salts[0:16]...)
+	p.ueValue, err = aesCipNoPadding(hardenedHash(userPass, salts[8:16], nil), p.encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	p.oValue = append(hardenedHash(ownerPass, salts[16:24], p.uValue), salts[16:32]...)
+	p.oeValue, err = aesCipNoPadding(hardenedHash(ownerPass, salts[24:32], p.uValue), p.encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	perms := make([]byte, 16)
+	copy(perms, p.permissionsBytes())
+	copy(perms[4:], []byte{0xff, 0xff, 0xff, 0xff, 'T', 'a', 'd', 'b'})
+	if _, err := io.ReadFull(crand.Reader, perms[12:]); err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(p.encryptionKey)
+	if err != nil {
+		return err
+	}
+	p.permsValue = make([]byte, 16)
+	block.Encrypt(p.permsValue, perms)
+	return nil
+}
+
+// permissionsBytes returns the P entry as 4 bytes, low-order byte first
+func (p *PDFProtection) permissionsBytes() []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(p.pValue))
+	return b
+}
+
 func (p *PDFProtection) createOValue(userPassWithPadding []byte, ownerPassWithPadding []byte) ([]byte, error) {
 	tmp := md5.Sum(ownerPassWithPadding)
 	ownerRC4key := tmp[0:5]
@@ -137,11 +277,40 @@ func (p *PDFProtection) Objectkey(objID int) []byte {
 }
 
 func (p *PDFProtection) objectkey(n int) []byte {
+	if p.encryption == EncryptionAES256 {
+		return p.encryptionKey
+	}
+
 	tmp := make([]byte, 8, 8)
 	binary.LittleEndian.PutUint32(tmp, uint32(n))
-	tmp2 := append(p.encryptionKey, tmp[0], tmp[1], tmp[2], 0, 0)
+	tmp2 := append(append([]byte{}, p.encryptionKey...), tmp[0], tmp[1], tmp[2], 0, 0)
+	if p.encryption == EncryptionAES128 {
+		tmp2 = append(tmp2, "sAlT"...)
+	}
 	tmp3 := md5.Sum(tmp2)
-	return tmp3[0:10]
+
+	size := len(p.encryptionKey) + 5
+	if size > 16 {
+		size = 16
+	}
+	return tmp3[0:size]
+}
+
+// encrypt encrypts the data of a string or a stream of the object objID
+func (p *PDFProtection) encrypt(objID int, data []byte) ([]byte, error) {
+	if p.encryption == EncryptionRC4 {
+		return rc4Cip(p.objectkey(objID), data)
+	}
+	return aesCip(p.objectkey(objID), data)
+}
+
+// encryptStream returns the data of the stream of the object objID, encrypted
+// when the document is protected
+func encryptStream(protection *PDFProtection, objID int, data []byte) ([]byte, error) {
+	if protection == nil {
+		return data, nil
+	}
+	return protection.encrypt(objID, data)
 }
 
 func rc4Cip(key []byte, src []byte) ([]byte, error) {
@@ -153,3 +322,137 @@ func rc4Cip(key []byte, src []byte) ([]byte, error) {
 	cip.XORKeyStream(dest, src)
 	return dest, nil
 }
+
+// rc4Rounds encrypts src with the key and 19 more times with the key XORed
+// with the round number
+func rc4Rounds(key []byte, src []byte) ([]byte, error) {
+	dest, err := rc4Cip(key, src)
+	if err != nil {
+		return nil, err
+	}
+
+	roundKey := make([]byte, len(key))
+	for i := 1; i <= 19; i++ {
+		for j := range key {
+			roundKey[j] = key[j] ^ byte(i)
+		}
+		dest, err = rc4Cip(roundKey, dest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dest, nil
+}
+
+// aesCip encrypts src with AES in CBC mode, the random initialization vector
+// is prepended to the result
+func aesCip(key []byte, src []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	pad := aes.BlockSize - len(src)%aes.BlockSize
+	dest := make([]byte, aes.BlockSize+len(src)+pad)
+	iv := dest[:aes.BlockSize]
+	if _, err := io.ReadFull(crand.Reader, iv); err != nil {
+		return nil, err
+	}
+	copy(dest[aes.BlockSize:], src)
+	for i := len(dest) - pad; i < len(dest); i++ {
+		dest[i] = byte(pad)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(dest[aes.BlockSize:], dest[aes.BlockSize:])
+	return dest, nil
+}
+
+// aesCipNoPadding encrypts src with AES in CBC mode with a zero
+// initialization vector, the length of src is a multiple of the block size
+func aesCipNoPadding(key []byte, src []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dest := make([]byte, len(src))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(dest, src)
+	return dest, nil
+}
+
+// hardenedHash computes the hash of a password of revision 6 (algorithm 2.B
+// of ISO 32000-2), udata is the U entry when hashing the owner password
+func hardenedHash(password []byte, salt []byte, udata []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(udata)
+	k := h.Sum(nil)
+
+	for i := 0; ; {
+		var k1 []byte
+		for j := 0; j < 64; j++ {
+			k1 = append(k1, password...)
+			k1 = append(k1, k...)
+			k1 = append(k1, udata...)
+		}
+
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// the first 16 bytes of e as a number modulo 3
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		i++
+		if i >= 64 && int(e[len(e)-1]) <= i-32 {
+			break
+		}
+	}
+	return k[:32]
+}
+
+// padPassword pads or truncates the password to 32 bytes
+func padPassword(password []byte) []byte {
+	b := append(append([]byte{}, password...), protectionPadding...)
+	return b[:32]
+}
+
+// truncatePassword truncates the UTF-8 password of revision 6 to 127 bytes
+func truncatePassword(password []byte) []byte {
+	if len(password) > 127 {
+		return password[:127]
+	}
+	return password
+}
+
+// md5Rounds hashes b and hashes the result 50 more times
+func md5Rounds(b []byte) []byte {
+	sum := md5.Sum(b)
+	for i := 0; i < 50; i++ {
+		sum = md5.Sum(sum[:])
+	}
+	return sum[:]
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(crand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -1,6 +1,18 @@
 package gofpdf
 
-import "testing"
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"math/big"
+	"testing"
+)
 
 func TestSetProtection(t *testing.T) {
 
@@ -68,3 +80,286 @@ func isSliceEq(a, b []byte) bool {
 
 	return true
 }
+
+func TestRC4ProtectionID(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionProtection(PermissionsPrint, "user", "owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the trailer of an encrypted document has an identifier, empty as the
+	// one of the key
+	if !bytes.Contains(b, []byte("/Encrypt ")) || !bytes.Contains(b, []byte("/ID [<><>]\n")) {
+		t.Errorf("the trailer should have an identifier, found %q", b[bytes.LastIndex(b, []byte("trailer")):])
+	}
+}
+
+func TestAESProtection(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		option PdfOption
+		key    func(t *testing.T, b []byte, userPass []byte) []byte
+	}{
+		{"AES-128", PdfOptionProtectionAES128(PermissionsPrint, "user", "owner"), referenceAES128Key},
+		{"AES-256", PdfOptionProtectionAES256(PermissionsPrint, "user", "owner"), referenceAES256Key},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			pdf, err := New(PdfOptionPageSize(200, 200), PdfOptionNoCompress(), test.option)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pdf.SetTitle("Secret report")
+			if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+				t.Fatal(err)
+			}
+			pdf.AddPage()
+			if err := pdf.SetFont("times", "", 12); err != nil {
+				t.Fatal(err)
+			}
+			if err := pdf.Cell(50, 20, "secret"); err != nil {
+				t.Fatal(err)
+			}
+			if err := pdf.Image("test/res/gopher01.jpg", 10, 50, Rect{W: 50, H: 50}); err != nil {
+				t.Fatal(err)
+			}
+			pdf.AddExternalLink("https://example.com/secret", 10, 10, 50, 20)
+
+			b, err := pdf.GetBytesPdfReturnErr()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(b, []byte("example.com")) {
+				t.Error("the link should be encrypted")
+			}
+
+			key := test.key(t, b, []byte("user"))
+			objKey := func(objID int) []byte {
+				if len(key) == 32 {
+					return key
+				}
+				m := md5.New()
+				m.Write(key)
+				m.Write([]byte{byte(objID), byte(objID >> 8), byte(objID >> 16), 0, 0})
+				m.Write([]byte("sAlT"))
+				return m.Sum(nil)
+			}
+			decrypt := func(objID int, data []byte) []byte {
+				plain, err := referenceAESDecrypt(objKey(objID), data)
+				if err != nil {
+					t.Fatalf("object %d: %s", objID, err)
+				}
+				return plain
+			}
+
+			contentID := pdf.pdfObjs.indexOfFirst(contentType) + 1
+			if content := decrypt(contentID, streamData(t, b, contentID)); !bytes.Contains(content, []byte(" TJ\n")) {
+				t.Errorf("unexpected content stream %q", content)
+			}
+
+			imageID := pdf.pdfObjs.indexOfFirst(imageType) + 1
+			if image := decrypt(imageID, streamData(t, b, imageID)); !bytes.HasPrefix(image, []byte{0xff, 0xd8}) {
+				t.Error("the image stream should decrypt to the jpeg data")
+			}
+
+			infoID := pdf.pdfObjs.indexOfFirst(infoType) + 1
+			title := decrypt(infoID, hexString(t, object(t, b, infoID), "/Title"))
+			if !bytes.Equal(title, []byte("\xfe\xff\x00S\x00e\x00c\x00r\x00e\x00t\x00 \x00r\x00e\x00p\x00o\x00r\x00t")) {
+				t.Errorf("unexpected title %q", title)
+			}
+
+			pageID := pdf.pdfObjs.indexOfFirst(pageType) + 1
+			if uri := decrypt(pageID, literalString(t, object(t, b, pageID), "/URI")); string(uri) != "https://example.com/secret" {
+				t.Errorf("unexpected link %q", uri)
+			}
+		})
+	}
+}
+
+// referenceAES128Key computes the key of revision 4 from the user password
+// and checks it against the U entry
+func referenceAES128Key(t *testing.T, b []byte, userPass []byte) []byte {
+	encrypt := encryptionDictionary(t, b)
+	for _, s := range []string{"/V 4\n", "/R 4\n", "/CFM /AESV2"} {
+		if !bytes.Contains(encrypt, []byte(s)) {
+			t.Fatalf("%q not found in the encryption dictionary", s)
+		}
+	}
+
+	o := literalString(t, encrypt, "/O")
+	u := literalString(t, encrypt, "/U")
+	var p int32
+	fmt.Sscanf(string(encrypt[bytes.Index(encrypt, []byte("/P "))+3:]), "%d", &p)
+	id := hexString(t, b[bytes.LastIndex(b, []byte("trailer")):], "/ID [")
+
+	m := md5.New()
+	m.Write(append(append([]byte{}, userPass...), protectionPadding...)[:32])
+	m.Write(o)
+	m.Write([]byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)})
+	m.Write(id)
+	key := m.Sum(nil)
+	for i := 0; i < 50; i++ {
+		sum := md5.Sum(key)
+		key = sum[:]
+	}
+
+	m = md5.New()
+	m.Write(protectionPadding)
+	m.Write(id)
+	check := m.Sum(nil)
+	for i := 0; i < 20; i++ {
+		k := make([]byte, len(key))
+		for j := range key {
+			k[j] = key[j] ^ byte(i)
+		}
+		c, _ := rc4.NewCipher(k)
+		c.XORKeyStream(check, check)
+	}
+	if !bytes.Equal(check, u[:16]) {
+		t.Fatal("the user password does not match the U entry")
+	}
+	return key
+}
+
+// referenceAES256Key decrypts the key of revision 6 with the user password
+// and checks it against the Perms entry
+func referenceAES256Key(t *testing.T, b []byte, userPass []byte) []byte {
+	encrypt := encryptionDictionary(t, b)
+	for _, s := range []string{"/V 5\n", "/R 6\n", "/CFM /AESV3"} {
+		if !bytes.Contains(encrypt, []byte(s)) {
+			t.Fatalf("%q not found in the encryption dictionary", s)
+		}
+	}
+
+	u := literalString(t, encrypt, "/U")
+	ue := literalString(t, encrypt, "/UE")
+	perms := literalString(t, encrypt, "/Perms")
+	if len(u) != 48 || len(ue) != 32 || len(perms) != 16 {
+		t.Fatalf("unexpected lengths of U, UE and Perms: %d %d %d", len(u), len(ue), len(perms))
+	}
+
+	if !bytes.Equal(referenceHash2B(userPass, u[32:40]), u[:32]) {
+		t.Fatal("the user password does not match the U entry")
+	}
+
+	block, _ := aes.NewCipher(referenceHash2B(userPass, u[40:48]))
+	key := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, ue)
+
+	block, _ = aes.NewCipher(key)
+	block.Decrypt(perms, perms)
+	if string(perms[9:12]) != "adb" || perms[0]&PermissionsPrint == 0 {
+		t.Fatalf("unexpected permissions %x", perms)
+	}
+	return key
+}
+
+// referenceHash2B computes the hash of a user password following algorithm
+// 2.B of ISO 32000-2 step by step
+func referenceHash2B(password []byte, salt []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, password...), salt...))
+	k := sum[:]
+	hashes := []func() hash.Hash{sha256.New, sha512.New384, sha512.New}
+	for round := 1; ; round++ {
+		// K1 is the sequence of the password and K repeated 64 times
+		k1 := bytes.Repeat(append(append([]byte{}, password...), k...), 64)
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// the first 16 bytes of E as an unsigned big endian number modulo 3
+		// select the next hash function
+		mod := new(big.Int).Mod(new(big.Int).SetBytes(e[:16]), big.NewInt(3))
+		h := hashes[mod.Int64()]()
+		h.Write(e)
+		k = h.Sum(nil)
+
+		if round >= 64 && int(e[len(e)-1]) <= round-32 {
+			return k[:32]
+		}
+	}
+}
+
+func referenceAESDecrypt(key []byte, data []byte) ([]byte, error) {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid length %d", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(plain, data[aes.BlockSize:])
+	pad := int(plain[len(plain)-1])
+	if pad < 1 || pad > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding %d", pad)
+	}
+	return plain[:len(plain)-pad], nil
+}
+
+func encryptionDictionary(t *testing.T, b []byte) []byte {
+	start := bytes.Index(b, []byte("/Filter /Standard\n"))
+	if start < 0 {
+		t.Fatal("encryption dictionary not found")
+	}
+	obj := b[start:]
+	return obj[:bytes.Index(obj, []byte("endobj\n"))]
+}
+
+// object returns the content of the object objID
+func object(t *testing.T, b []byte, objID int) []byte {
+	start := bytes.Index(b, []byte(fmt.Sprintf("\n%d 0 obj\n", objID)))
+	if start < 0 {
+		t.Fatalf("object %d not found", objID)
+	}
+	obj := b[start:]
+	return obj[:bytes.Index(obj, []byte("endobj\n"))]
+}
+
+// streamData returns the data of the stream of the object objID
+func streamData(t *testing.T, b []byte, objID int) []byte {
+	obj := object(t, b, objID)
+	var length int
+	fmt.Sscanf(string(obj[bytes.Index(obj, []byte("/Length "))+8:]), "%d", &length)
+	start := bytes.Index(obj, []byte("stream\n")) + 7
+	return obj[start : start+length]
+}
+
+// hexString returns the bytes of the hexadecimal string following key
+func hexString(t *testing.T, b []byte, key string) []byte {
+	i := bytes.Index(b, []byte(key+" <"))
+	if i < 0 {
+		i = bytes.Index(b, []byte(key+"<"))
+		if i < 0 {
+			t.Fatalf("%s not found", key)
+		}
+		i--
+	}
+	s := b[i+len(key)+2:]
+	var v []byte
+	fmt.Sscanf(string(s[:bytes.IndexByte(s, '>')]), "%X", &v)
+	return v
+}
+
+// literalString returns the bytes of the escaped literal string following key
+func literalString(t *testing.T, b []byte, key string) []byte {
+	i := bytes.Index(b, []byte(key+" ("))
+	if i < 0 {
+		t.Fatalf("%s not found", key)
+	}
+	var v []byte
+	for s := b[i+len(key)+2:]; s[0] != ')'; s = s[1:] {
+		if s[0] == '\\' {
+			s = s[1:]
+			if s[0] == 'r' {
+				v = append(v, '\r')
+				continue
+			}
+		}
+		v = append(v, s[0])
+	}
+	return v
+}
@@ -0,0 +1,1073 @@
+package gofpdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// values of the objects read from an existing document, numbers are int or
+// float64, booleans are bool and null is nil
+type (
+	pdfName   string
+	pdfString string
+	pdfArray  []interface{}
+	pdfDict   map[string]interface{}
+	pdfRef    struct{ Num, Gen int }
+	pdfStream struct {
+		Dict pdfDict
+		Data []byte // encoded data
+	}
+)
+
+func init() {
+	gob.Register(pdfName(""))
+	gob.Register(pdfString(""))
+	gob.Register(pdfArray{})
+	gob.Register(pdfDict{})
+	gob.Register(pdfRef{})
+	gob.Register(pdfStream{})
+}
+
+// pdfLexer reads the values of a document
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPdfWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPdfDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// skipSpace skips whitespaces and comments
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPdfWhitespace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// readRegular reads a token of regular characters, a number or a keyword
+func (l *pdfLexer) readRegular() string {
+	if l.pos > len(l.data) {
+		l.pos = len(l.data)
+	}
+	start := l.pos
+	for l.pos < len(l.data) && !isPdfWhitespace(l.data[l.pos]) && !isPdfDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// readKeyword returns true and skips the keyword if it follows
+func (l *pdfLexer) readKeyword(keyword string) bool {
+	l.skipSpace()
+	if l.pos >= len(l.data) || !bytes.HasPrefix(l.data[l.pos:], []byte(keyword)) {
+		return false
+	}
+	l.pos += len(keyword)
+	return true
+}
+
+func (l *pdfLexer) readInt() (int, error) {
+	l.skipSpace()
+	tok := l.readRegular()
+	n, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, fmt.Errorf("integer expected at offset %d, found %q", l.pos, tok)
+	}
+	return n, nil
+}
+
+func (l *pdfLexer) readValue() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	switch c := l.data[l.pos]; c {
+	case '/':
+		l.pos++
+		return l.readName(), nil
+	case '(':
+		return l.readLiteralString()
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			return l.readDict()
+		}
+		return l.readHexString()
+	case '[':
+		return l.readArray()
+	case ')', '>', ']', '{', '}':
+		return nil, fmt.Errorf("unexpected %q at offset %d", c, l.pos)
+	}
+
+	start := l.pos
+	tok := l.readRegular()
+	switch tok {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	if n, err := strconv.Atoi(tok); err == nil {
+		// an integer followed by an integer and R is a reference
+		pos := l.pos
+		l.skipSpace()
+		if gen, err := strconv.Atoi(l.readRegular()); err == nil {
+			l.skipSpace()
+			if l.readRegular() == "R" {
+				return pdfRef{Num: n, Gen: gen}, nil
+			}
+		}
+		l.pos = pos
+		return n, nil
+	}
+
+	f, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok, start)
+	}
+	return f, nil
+}
+
+func (l *pdfLexer) readName() pdfName {
+	var buff bytes.Buffer
+	for l.pos < len(l.data) && !isPdfWhitespace(l.data[l.pos]) && !isPdfDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if b, err := hex.DecodeString(string(l.data[l.pos+1 : l.pos+3])); err == nil {
+				buff.Write(b)
+				l.pos += 3
+				continue
+			}
+		}
+		buff.WriteByte(c)
+		l.pos++
+	}
+	return pdfName(buff.String())
+}
+
+func (l *pdfLexer) readLiteralString() (interface{}, error) {
+	var buff bytes.Buffer
+	depth := 0
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				l.pos++
+				return pdfString(buff.String()), nil
+			}
+			depth--
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				break
+			}
+			c = l.data[l.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// a backslash at the end of a line continues the string
+				if l.pos+1 < len(l.data) && l.data[l.pos+1] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := 0
+					for n := 0; n < 3 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; n++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					l.pos--
+					c = byte(v)
+				}
+			}
+		}
+		buff.WriteByte(c)
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+func (l *pdfLexer) readHexString() (interface{}, error) {
+	var digits []byte
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		if c == '>' {
+			l.pos++
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			b, err := hex.DecodeString(string(digits))
+			if err != nil {
+				return nil, err
+			}
+			return pdfString(b), nil
+		}
+		if !isPdfWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+func (l *pdfLexer) readArray() (interface{}, error) {
+	array := pdfArray{}
+	l.pos++
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return array, nil
+		}
+		v, err := l.readValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, v)
+	}
+}
+
+func (l *pdfLexer) readDict() (interface{}, error) {
+	dict := pdfDict{}
+	l.pos += 2
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if l.readKeyword(">>") {
+			return dict, nil
+		}
+		if l.data[l.pos] != '/' {
+			return nil, fmt.Errorf("name expected at offset %d", l.pos)
+		}
+		l.pos++
+		key := l.readName()
+		v, err := l.readValue()
+		if err != nil {
+			return nil, err
+		}
+		// a null value is the same as an absent entry
+		if v != nil {
+			dict[string(key)] = v
+		}
+	}
+}
+
+// xrefEntry locates an object, either at an offset of the document or in an
+// object stream
+type xrefEntry struct {
+	offset int
+	stream int // number of the object stream, 0 for objects at an offset
+	index  int // index within the object stream
+}
+
+// pdfReader reads the objects of an existing document
+type pdfReader struct {
+	data    []byte
+	xref    map[int]xrefEntry
+	trailer pdfDict
+	objects map[int]interface{}
+	// decoded object streams
+	objectStreams map[int][]byte
+	// the cross-reference was rebuilt by scanning the document
+	rebuilt bool
+}
+
+func newPdfReader(data []byte) (*pdfReader, error) {
+	r := &pdfReader{
+		data:          data,
+		xref:          make(map[int]xrefEntry),
+		objects:       make(map[int]interface{}),
+		objectStreams: make(map[int][]byte),
+	}
+
+	if err := r.readXref(); err != nil {
+		// the cross-reference information is damaged, it is rebuilt from the objects
+		if err := r.rebuildXref(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := r.trailer["Encrypt"]; ok {
+		return nil, errors.New("encrypted documents are not supported")
+	}
+	return r, nil
+}
+
+// readXref reads the cross-reference sections, starting with the latest one
+func (r *pdfReader) readXref() error {
+	i := bytes.LastIndex(r.data, []byte("startxref"))
+	if i < 0 {
+		return errors.New("startxref not found")
+	}
+
+	l := &pdfLexer{data: r.data, pos: i + len("startxref")}
+	offset, err := l.readInt()
+	if err != nil {
+		return err
+	}
+
+	visited := make(map[int]bool)
+	for !visited[offset] {
+		visited[offset] = true
+
+		trailer, err := r.readXrefSection(offset)
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+
+		// hybrid files list the compressed objects in a cross-reference stream
+		if stm, ok := trailer["XRefStm"].(int); ok {
+			if _, err := r.readXrefSection(stm); err != nil {
+				return err
+			}
+		}
+
+		prev, ok := trailer["Prev"].(int)
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+
+	if r.trailer["Root"] == nil {
+		return errors.New("document catalog not found")
+	}
+	return nil
+}
+
+// readXrefSection reads a cross-reference table or stream and returns its
+// trailer. Entries of later sections have precedence and are kept.
+func (r *pdfReader) readXrefSection(offset int) (pdfDict, error) {
+	if offset < 0 || offset >= len(r.data) {
+		return nil, fmt.Errorf("invalid cross-reference offset %d", offset)
+	}
+
+	l := &pdfLexer{data: r.data, pos: offset}
+	if l.readKeyword("xref") {
+		return r.readXrefTable(l)
+	}
+	return r.readXrefStream(offset)
+}
+
+func (r *pdfReader) readXrefTable(l *pdfLexer) (pdfDict, error) {
+	for {
+		if l.readKeyword("trailer") {
+			v, err := l.readValue()
+			if err != nil {
+				return nil, err
+			}
+			trailer, ok := v.(pdfDict)
+			if !ok {
+				return nil, errors.New("invalid trailer")
+			}
+			return trailer, nil
+		}
+
+		start, err := l.readInt()
+		if err != nil {
+			return nil, err
+		}
+		count, err := l.readInt()
+		if err != nil {
+			return nil, err
+		}
+
+		for x := 0; x < count; x++ {
+			offset, err := l.readInt()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := l.readInt(); err != nil {
+				return nil, err
+			}
+			l.skipSpace()
+			kind := l.readRegular()
+
+			if _, ok := r.xref[start+x]; !ok && kind == "n" {
+				r.xref[start+x] = xrefEntry{offset: offset}
+			}
+		}
+	}
+}
+
+func (r *pdfReader) readXrefStream(offset int) (pdfDict, error) {
+	_, v, err := r.readObjectAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := v.(pdfStream)
+	if !ok || stream.Dict["Type"] != pdfName("XRef") {
+		return nil, fmt.Errorf("no cross-reference at offset %d", offset)
+	}
+
+	data, err := r.decodeStream(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	w, ok := r.resolve(stream.Dict["W"]).(pdfArray)
+	if !ok || len(w) != 3 {
+		return nil, errors.New("invalid cross-reference stream")
+	}
+	var widths [3]int
+	for x := range widths {
+		widths[x], _ = r.resolve(w[x]).(int)
+	}
+
+	index, ok := r.resolve(stream.Dict["Index"]).(pdfArray)
+	if !ok {
+		size, _ := r.resolve(stream.Dict["Size"]).(int)
+		index = pdfArray{0, size}
+	}
+
+	pos := 0
+	field := func(width int, def int) int {
+		if width == 0 {
+			return def
+		}
+		v := 0
+		for x := 0; x < width; x++ {
+			v = v<<8 | int(data[pos])
+			pos++
+		}
+		return v
+	}
+
+	for x := 0; x+1 < len(index); x += 2 {
+		start, _ := r.resolve(index[x]).(int)
+		count, _ := r.resolve(index[x+1]).(int)
+		for y := 0; y < count; y++ {
+			if pos+widths[0]+widths[1]+widths[2] > len(data) {
+				return nil, errors.New("cross-reference stream too short")
+			}
+			kind := field(widths[0], 1)
+			f2 := field(widths[1], 0)
+			f3 := field(widths[2], 0)
+
+			if _, ok := r.xref[start+y]; ok {
+				continue
+			}
+			switch kind {
+			case 1:
+				r.xref[start+y] = xrefEntry{offset: f2}
+			case 2:
+				r.xref[start+y] = xrefEntry{stream: f2, index: f3}
+			}
+		}
+	}
+
+	return stream.Dict, nil
+}
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// rebuildXref locates the objects by scanning the document
+func (r *pdfReader) rebuildXref() error {
+	r.rebuilt = true
+	r.xref = make(map[int]xrefEntry)
+	r.trailer = nil
+
+	var nums []int
+	for _, m := range objectHeader.FindAllSubmatchIndex(r.data, -1) {
+		if m[0] > 0 && !isPdfWhitespace(r.data[m[0]-1]) {
+			continue
+		}
+		num, _ := strconv.Atoi(string(r.data[m[2]:m[3]]))
+		r.xref[num] = xrefEntry{offset: m[0]}
+		nums = append(nums, num)
+	}
+
+	// objects of object streams and the trailer of cross-reference streams
+	for _, num := range nums {
+		_, v, err := r.readObjectAt(r.xref[num].offset)
+		if err != nil {
+			continue
+		}
+		stream, ok := v.(pdfStream)
+		if !ok {
+			continue
+		}
+		switch stream.Dict["Type"] {
+		case pdfName("XRef"):
+			if stream.Dict["Root"] != nil {
+				r.trailer = stream.Dict
+			}
+		case pdfName("ObjStm"):
+			contained, _, err := r.objectStreamHeader(num, stream)
+			if err != nil {
+				continue
+			}
+			for x, n := range contained {
+				if _, ok := r.xref[n]; !ok {
+					r.xref[n] = xrefEntry{stream: num, index: x}
+				}
+			}
+		}
+	}
+
+	if i := bytes.LastIndex(r.data, []byte("trailer")); i >= 0 {
+		l := &pdfLexer{data: r.data, pos: i + len("trailer")}
+		if v, err := l.readValue(); err == nil {
+			if trailer, ok := v.(pdfDict); ok && trailer["Root"] != nil {
+				r.trailer = trailer
+			}
+		}
+	}
+
+	if r.trailer == nil {
+		for num := range r.xref {
+			if dict, ok := r.resolve(pdfRef{Num: num}).(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				r.trailer = pdfDict{"Root": pdfRef{Num: num}}
+				break
+			}
+		}
+	}
+
+	if r.trailer == nil {
+		return errors.New("document catalog not found")
+	}
+	return nil
+}
+
+// readObjectAt reads the indirect object at offset
+func (r *pdfReader) readObjectAt(offset int) (int, interface{}, error) {
+	if offset < 0 || offset >= len(r.data) {
+		return 0, nil, fmt.Errorf("invalid object offset %d", offset)
+	}
+	l := &pdfLexer{data: r.data, pos: offset}
+	num, err := l.readInt()
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, err := l.readInt(); err != nil {
+		return 0, nil, err
+	}
+	if !l.readKeyword("obj") {
+		return 0, nil, fmt.Errorf("object %d: obj expected", num)
+	}
+
+	v, err := l.readValue()
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %d: %s", num, err)
+	}
+
+	dict, ok := v.(pdfDict)
+	if !ok || !l.readKeyword("stream") {
+		return num, v, nil
+	}
+
+	// the stream keyword is followed by CRLF or LF
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+
+	data, err := r.streamData(dict, l.pos)
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %d: %s", num, err)
+	}
+	return num, pdfStream{Dict: dict, Data: data}, nil
+}
+
+// streamData returns the encoded data of a stream starting at offset
+func (r *pdfReader) streamData(dict pdfDict, offset int) ([]byte, error) {
+	length, ok := r.resolve(dict["Length"]).(int)
+	if ok && length >= 0 && offset+length <= len(r.data) {
+		l := &pdfLexer{data: r.data, pos: offset + length}
+		if l.readKeyword("endstream") {
+			return r.data[offset : offset+length], nil
+		}
+	}
+
+	// the length is missing or wrong, the data ends at endstream
+	end := bytes.Index(r.data[offset:], []byte("endstream"))
+	if end < 0 {
+		return nil, errors.New("endstream not found")
+	}
+	data := r.data[offset : offset+end]
+	if bytes.HasSuffix(data, []byte("\r\n")) {
+		return data[:len(data)-2], nil
+	}
+	if bytes.HasSuffix(data, []byte("\n")) || bytes.HasSuffix(data, []byte("\r")) {
+		return data[:len(data)-1], nil
+	}
+	return data, nil
+}
+
+// object returns the value of the object num, missing objects are null
+func (r *pdfReader) object(num int) (interface{}, error) {
+	if v, ok := r.objects[num]; ok {
+		return v, nil
+	}
+
+	entry, ok := r.xref[num]
+	if !ok {
+		return nil, nil
+	}
+
+	// guard against objects referring to themselves while they are read
+	r.objects[num] = nil
+
+	var v interface{}
+	var err error
+	if entry.stream > 0 {
+		v, err = r.objectInStream(entry.stream, entry.index, num)
+	} else {
+		var found int
+		found, v, err = r.readObjectAt(entry.offset)
+		if (err != nil || found != num) && !r.rebuilt {
+			// the offset is wrong, the cross-reference is rebuilt from the
+			// objects of the document
+			delete(r.objects, num)
+			if err := r.rebuildXref(); err != nil {
+				return nil, err
+			}
+			return r.object(num)
+		}
+	}
+	if err != nil {
+		delete(r.objects, num)
+		return nil, err
+	}
+
+	r.objects[num] = v
+	return v, nil
+}
+
+// resolve returns the value of references, objects that cannot be read are
+// null like in a PDF reader
+func (r *pdfReader) resolve(v interface{}) interface{} {
+	if ref, ok := v.(pdfRef); ok {
+		v, _ = r.object(ref.Num)
+	}
+	return v
+}
+
+// objectStreamHeader returns the numbers and offsets of the objects of an
+// object stream and its decoded data
+func (r *pdfReader) objectStreamHeader(num int, stream pdfStream) ([]int, []int, error) {
+	data, ok := r.objectStreams[num]
+	if !ok {
+		var err error
+		if data, err = r.decodeStream(stream); err != nil {
+			return nil, nil, err
+		}
+		r.objectStreams[num] = data
+	}
+
+	n, _ := r.resolve(stream.Dict["N"]).(int)
+	first, _ := r.resolve(stream.Dict["First"]).(int)
+	if n < 0 || n > len(data) || first < 0 {
+		return nil, nil, fmt.Errorf("invalid object stream %d", num)
+	}
+	l := &pdfLexer{data: data}
+	nums := make([]int, n)
+	offsets := make([]int, n)
+	var err error
+	for x := 0; x < n; x++ {
+		if nums[x], err = l.readInt(); err != nil {
+			return nil, nil, err
+		}
+		if offsets[x], err = l.readInt(); err != nil {
+			return nil, nil, err
+		}
+		offsets[x] += first
+	}
+	return nums, offsets, nil
+}
+
+func (r *pdfReader) objectInStream(streamNum int, index int, num int) (interface{}, error) {
+	stream, ok := r.resolve(pdfRef{Num: streamNum}).(pdfStream)
+	if !ok {
+		return nil, fmt.Errorf("object stream %d not found", streamNum)
+	}
+
+	nums, offsets, err := r.objectStreamHeader(streamNum, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	if index >= len(nums) || nums[index] != num {
+		index = -1
+		for x := range nums {
+			if nums[x] == num {
+				index = x
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("object %d not found in object stream %d", num, streamNum)
+		}
+	}
+
+	data := r.objectStreams[streamNum]
+	if offsets[index] < 0 || offsets[index] > len(data) {
+		return nil, fmt.Errorf("object %d not found in object stream %d", num, streamNum)
+	}
+	l := &pdfLexer{data: data, pos: offsets[index]}
+	return l.readValue()
+}
+
+// decodeStream returns the decoded data of a stream
+func (r *pdfReader) decodeStream(stream pdfStream) ([]byte, error) {
+	filters := r.resolve(stream.Dict["Filter"])
+	params := r.resolve(stream.Dict["DecodeParms"])
+	if _, ok := filters.(pdfArray); !ok {
+		filters = pdfArray{filters}
+		params = pdfArray{params}
+	}
+	paramList, _ := params.(pdfArray)
+
+	data := stream.Data
+	for x, f := range filters.(pdfArray) {
+		var param pdfDict
+		if x < len(paramList) {
+			param, _ = r.resolve(paramList[x]).(pdfDict)
+		}
+
+		var err error
+		switch r.resolve(f) {
+		case nil:
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			if data, err = flateDecode(data); err == nil {
+				data, err = r.unpredict(data, param)
+			}
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data, err = asciiHexDecode(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = ascii85Decode(data)
+		default:
+			err = fmt.Errorf("unsupported filter %v", r.resolve(f))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func flateDecode(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(zr)
+	// truncated streams are common, the data read so far is used
+	if err == io.ErrUnexpectedEOF && len(b) > 0 {
+		err = nil
+	}
+	return b, err
+}
+
+func asciiHexDecode(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isPdfWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	return hex.DecodeString(string(digits))
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	return ioutil.ReadAll(ascii85.NewDecoder(bytes.NewReader(data)))
+}
+
+// unpredict reverses the PNG predictors of flate encoded data
+func (r *pdfReader) unpredict(data []byte, param pdfDict) ([]byte, error) {
+	predictor, _ := r.resolve(param["Predictor"]).(int)
+	if predictor <= 1 || len(data) == 0 {
+		return data, nil
+	}
+	if predictor < 10 {
+		return nil, fmt.Errorf("unsupported predictor %d", predictor)
+	}
+
+	colors, bpc, columns := 1, 8, 1
+	if v, ok := r.resolve(param["Colors"]).(int); ok {
+		colors = v
+	}
+	if v, ok := r.resolve(param["BitsPerComponent"]).(int); ok {
+		bpc = v
+	}
+	if v, ok := r.resolve(param["Columns"]).(int); ok {
+		columns = v
+	}
+
+	if colors <= 0 || colors > 32 || bpc <= 0 || bpc > 16 || columns <= 0 || columns > 8*len(data) {
+		return nil, errors.New("invalid predictor parameters")
+	}
+
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (colors*bpc*columns + 7) / 8
+	prev := make([]byte, rowLen)
+	var out []byte
+	for pos := 0; pos < len(data); pos += rowLen + 1 {
+		end := pos + 1 + rowLen
+		if end > len(data) {
+			end = len(data)
+		}
+		row := append([]byte{}, data[pos+1:end]...)
+		for x := range row {
+			var left, upLeft byte
+			if x >= bpp {
+				left, upLeft = row[x-bpp], prev[x-bpp]
+			}
+			switch data[pos] {
+			case 1:
+				row[x] += left
+			case 2:
+				row[x] += prev[x]
+			case 3:
+				row[x] += byte((int(left) + int(prev[x])) / 2)
+			case 4:
+				row[x] += paeth(left, prev[x], upLeft)
+			}
+		}
+		out = append(out, row...)
+		copy(prev, row)
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// pages returns the pages of the document with the inherited attributes of
+// the page tree
+func (r *pdfReader) pages() ([]pdfDict, error) {
+	root, ok := r.resolve(r.trailer["Root"]).(pdfDict)
+	if !ok {
+		return nil, errors.New("document catalog not found")
+	}
+
+	var pages []pdfDict
+	visited := make(map[int]bool)
+	var walk func(node interface{}, inherited pdfDict) error
+	walk = func(node interface{}, inherited pdfDict) error {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.Num] {
+				return errors.New("the page tree contains a loop")
+			}
+			visited[ref.Num] = true
+		}
+
+		dict, ok := r.resolve(node).(pdfDict)
+		if !ok {
+			return errors.New("invalid page tree node")
+		}
+
+		attrs := pdfDict{}
+		for _, key := range []string{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if v, ok := dict[key]; ok {
+				attrs[key] = v
+			} else if v, ok := inherited[key]; ok {
+				attrs[key] = v
+			}
+		}
+
+		kids, ok := r.resolve(dict["Kids"]).(pdfArray)
+		if dict["Type"] == pdfName("Page") || !ok {
+			page := pdfDict{}
+			for k, v := range dict {
+				page[k] = v
+			}
+			for k, v := range attrs {
+				page[k] = v
+			}
+			pages = append(pages, page)
+			return nil
+		}
+
+		for _, kid := range kids {
+			if err := walk(kid, attrs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root["Pages"], nil); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// pageContent returns the decoded content streams of a page
+func (r *pdfReader) pageContent(page pdfDict) ([]byte, error) {
+	var streams pdfArray
+	switch contents := r.resolve(page["Contents"]).(type) {
+	case nil:
+		return nil, nil
+	case pdfArray:
+		streams = contents
+	default:
+		streams = pdfArray{contents}
+	}
+
+	var buff bytes.Buffer
+	for _, s := range streams {
+		stream, ok := r.resolve(s).(pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := r.decodeStream(stream)
+		if err != nil {
+			return nil, err
+		}
+		buff.Write(data)
+		buff.WriteByte('\n')
+	}
+	return buff.Bytes(), nil
+}
+
+// pageBox returns the page boundary t of a page as lower left and upper right
+// corners. A missing boundary defaults to the crop box, the crop box to the
+// media box.
+func (r *pdfReader) pageBox(page pdfDict, t int) ([4]float64, error) {
+	media, ok := r.rectangle(page["MediaBox"])
+	if !ok {
+		// US Letter is the default of most readers
+		media = [4]float64{0, 0, 612, 792}
+	}
+	if t == PageBoundaryMedia {
+		return media, nil
+	}
+
+	box, ok := r.rectangle(page[PageBoundaryType(t)])
+	if !ok && t != PageBoundaryCrop {
+		box, ok = r.rectangle(page["CropBox"])
+	}
+	if !ok {
+		return media, nil
+	}
+
+	// boundaries are clipped to the media box
+	box[0], box[1] = fmax(box[0], media[0]), fmax(box[1], media[1])
+	box[2], box[3] = fmin(box[2], media[2]), fmin(box[3], media[3])
+	if box[2] <= box[0] || box[3] <= box[1] {
+		return media, nil
+	}
+	return box, nil
+}
+
+// rectangle returns a normalized rectangle
+func (r *pdfReader) rectangle(v interface{}) ([4]float64, bool) {
+	var rect [4]float64
+	array, ok := r.resolve(v).(pdfArray)
+	if !ok || len(array) != 4 {
+		return rect, false
+	}
+	for x := range rect {
+		if rect[x], ok = pdfNumber(r.resolve(array[x])); !ok {
+			return rect, false
+		}
+	}
+	if rect[0] > rect[2] {
+		rect[0], rect[2] = rect[2], rect[0]
+	}
+	if rect[1] > rect[3] {
+		rect[1], rect[3] = rect[3], rect[1]
+	}
+	return rect, true
+}
+
+func pdfNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func fmin(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func fmax(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
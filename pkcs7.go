@@ -0,0 +1,148 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var (
+	oidData              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidAttrContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidDigestSHA256      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidEncryptionRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSignatureECDSA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	asn1Null             = asn1.RawValue{Tag: asn1.TagNull}
+)
+
+// pkcs7ContentInfo the outer structure of a PKCS#7 message, the content is
+// omitted for detached signatures
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []pkcs7SignerInfo `asn1:"set"`
+}
+
+type pkcs7SignerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     pkcs7IssuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+type pkcs7IssuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type pkcs7Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// signPKCS7 returns the DER encoded detached PKCS#7 signature of the SHA-256
+// digest of a document. The first certificate is the one of the signer.
+func signPKCS7(digest []byte, signer crypto.Signer, certs []*x509.Certificate, signingTime time.Time) ([]byte, error) {
+	attrs, err := pkcs7SignedAttributes(digest, signingTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// the signature covers the attributes encoded as a set
+	set, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(set)
+	signature, err := signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	encryption := pkix.AlgorithmIdentifier{Algorithm: oidEncryptionRSA, Parameters: asn1Null}
+	if _, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		encryption = pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSA256}
+	}
+	sha := pkix.AlgorithmIdentifier{Algorithm: oidDigestSHA256, Parameters: asn1Null}
+
+	var chain []byte
+	for _, cert := range certs {
+		chain = append(chain, cert.Raw...)
+	}
+
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha},
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: chain},
+		SignerInfos: []pkcs7SignerInfo{{
+			Version: 1,
+			IssuerAndSerialNumber: pkcs7IssuerAndSerial{
+				Issuer:       asn1.RawValue{FullBytes: certs[0].RawIssuer},
+				SerialNumber: certs[0].SerialNumber,
+			},
+			DigestAlgorithm:           sha,
+			AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+			DigestEncryptionAlgorithm: encryption,
+			EncryptedDigest:           signature,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// pkcs7SignedAttributes returns the encoded content type, signing time and
+// message digest attributes, sorted as required for a DER encoded set
+func pkcs7SignedAttributes(digest []byte, signingTime time.Time) ([]byte, error) {
+	values := []struct {
+		oid   asn1.ObjectIdentifier
+		value interface{}
+	}{
+		{oidAttrContentType, oidData},
+		{oidAttrSigningTime, signingTime.UTC()},
+		{oidAttrMessageDigest, digest},
+	}
+
+	attrs := make([][]byte, len(values))
+	for x, v := range values {
+		value, err := asn1.Marshal(v.value)
+		if err != nil {
+			return nil, fmt.Errorf("signed attribute %v: %s", v.oid, err)
+		}
+		attrs[x], err = asn1.Marshal(pkcs7Attribute{Type: v.oid, Values: []asn1.RawValue{{FullBytes: value}}})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return bytes.Compare(attrs[i], attrs[j]) < 0
+	})
+	return bytes.Join(attrs, nil), nil
+}
@@ -12,6 +12,10 @@ type ProcSetObj struct {
 	//Font
 	Realtes     RelateFonts
 	RealteXobjs RealteXobjects
+	ExtGStates  RealteXobjects
+	Shadings    RealteXobjects
+	ColorSpaces RealteXobjects
+	Properties  RealteXobjects
 	getRoot     func() *Fpdf
 }
 
@@ -43,6 +47,34 @@ func (pr *ProcSetObj) write(w io.Writer, objID int) error {
 		i++
 	}
 	io.WriteString(w, ">>\n")
+	if len(pr.ExtGStates) > 0 {
+		io.WriteString(w, "/ExtGState <<\n")
+		for x := 0; x < len(pr.ExtGStates); x++ {
+			fmt.Fprintf(w, "/%s %d 0 R\n", pr.ExtGStates[x].IdOfObj, pr.ExtGStates[x].IndexOfObj+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
+	if len(pr.ColorSpaces) > 0 {
+		io.WriteString(w, "/ColorSpace <<\n")
+		for x := 0; x < len(pr.ColorSpaces); x++ {
+			fmt.Fprintf(w, "/%s %d 0 R\n", pr.ColorSpaces[x].IdOfObj, pr.ColorSpaces[x].IndexOfObj+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
+	if len(pr.Properties) > 0 {
+		io.WriteString(w, "/Properties <<\n")
+		for x := 0; x < len(pr.Properties); x++ {
+			fmt.Fprintf(w, "/%s %d 0 R\n", pr.Properties[x].IdOfObj, pr.Properties[x].IndexOfObj+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
+	if len(pr.Shadings) > 0 {
+		io.WriteString(w, "/Shading <<\n")
+		for x := 0; x < len(pr.Shadings); x++ {
+			fmt.Fprintf(w, "/%s %d 0 R\n", pr.Shadings[x].IdOfObj, pr.Shadings[x].IndexOfObj+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
 	io.WriteString(w, ">>\n")
 	return nil
 }
@@ -0,0 +1,76 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+
+	"github.com/ISeeMe/gofpdf/geh"
+)
+
+const shadingType = "Shading"
+
+// shading types of the pdf specification
+const (
+	shadingTypeAxial  = 2
+	shadingTypeRadial = 3
+)
+
+// ShadingObj axial or radial shading painting a gradient
+type ShadingObj struct { //impl IObj, ProcsetIObj
+	shadingType   int
+	coords        []float64
+	function      *FunctionObj
+	indexFunction int
+}
+
+func newShadingObj(shadingType int, coords []float64, stops []GradientStop) *ShadingObj {
+	return &ShadingObj{
+		shadingType:   shadingType,
+		coords:        coords,
+		function:      newFunctionObj(stops),
+		indexFunction: -1,
+	}
+}
+
+func (s *ShadingObj) getType() string {
+	return shadingType
+}
+
+func (s *ShadingObj) write(w io.Writer, objID int) error {
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /ShadingType %d\n", s.shadingType)
+	io.WriteString(w, "  /ColorSpace /DeviceRGB\n")
+	io.WriteString(w, "  /Coords [")
+	for x := 0; x < len(s.coords); x++ {
+		if x > 0 {
+			io.WriteString(w, " ")
+		}
+		fmt.Fprintf(w, "%.5f", s.coords[x])
+	}
+	io.WriteString(w, "]\n")
+	fmt.Fprintf(w, "  /Function %d 0 R\n", s.indexFunction+1)
+	io.WriteString(w, "  /Extend [true true]\n")
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// procsetIdentifier is derived from the shading and its function, so equal
+// gradients share one object and keep their name when used through a
+// template
+func (s *ShadingObj) procsetIdentifier() string {
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%d %v ", s.shadingType, s.coords)
+	s.function.write(&buff, 0)
+	return fmt.Sprintf("Sh%x", sha1.Sum(buff.Bytes()))[:18]
+}
+
+func (s *ShadingObj) GobEncode() ([]byte, error) {
+	return geh.EncodeMany(s.shadingType, s.coords, s.function)
+}
+
+func (s *ShadingObj) GobDecode(buf []byte) error {
+	s.indexFunction = -1
+	return geh.DecodeMany(buf, &s.shadingType, &s.coords, &s.function)
+}
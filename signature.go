@@ -0,0 +1,194 @@
+package gofpdf
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const signatureType = "Signature"
+
+// byteRangePlaceholder reserves the space of the byte range of the signature,
+// the offsets are filled in once the document is written
+const byteRangePlaceholder = "0 0000000000 0000000000 0000000000"
+
+// SignatureOption options of the digital signature of a document
+type SignatureOption struct {
+	Signer       crypto.Signer       // the RSA or ECDSA private key of the signer
+	Certificates []*x509.Certificate // the certificate of the signer followed by its chain
+	Name         string              // the name of the signer
+	Reason       string              // the reason for signing
+	Location     string              // the location of signing
+	ContactInfo  string              // how to contact the signer
+	SigningTime  time.Time           // the time of signing, the time the document is written if zero
+	Appearance   Template            // drawn scaled to the field, the field is drawn empty if nil
+}
+
+// SignatureObj the signature dictionary of a signature field. The signature
+// covers the whole document except its own contents and is computed after
+// the document is written.
+type SignatureObj struct { //impl IObj
+	opt     SignatureOption
+	time    time.Time
+	getRoot func() *Fpdf
+
+	// byteRangeOffset is the offset of the byte range in the written
+	// document, contentsOffset the offset of the string holding the signature
+	byteRangeOffset int
+	contentsOffset  int
+}
+
+func (s *SignatureObj) init(funcGetRoot func() *Fpdf) {
+	s.getRoot = funcGetRoot
+}
+
+func (s *SignatureObj) getType() string {
+	return signatureType
+}
+
+func (s *SignatureObj) write(w io.Writer, objID int) error {
+	cw, ok := w.(*countingWriter)
+	if !ok {
+		return errors.New("a signature can only be written as part of its document")
+	}
+
+	s.time = s.opt.SigningTime
+	if s.time.IsZero() {
+		s.time = time.Now()
+	}
+	protection := s.getRoot().protection()
+
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /Type /Sig\n")
+	io.WriteString(w, "  /Filter /Adobe.PPKLite\n")
+	io.WriteString(w, "  /SubFilter /adbe.pkcs7.detached\n")
+	io.WriteString(w, "  /ByteRange [")
+	s.byteRangeOffset = cw.offset
+	io.WriteString(w, byteRangePlaceholder+"]\n")
+	// the contents are never encrypted
+	io.WriteString(w, "  /Contents ")
+	s.contentsOffset = cw.offset
+	fmt.Fprintf(w, "<%s>\n", strings.Repeat("0", s.size()*2))
+
+	date, err := encodeString([]byte("D:"+infodate(s.time)), protection, objID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "  /M %s\n", date)
+
+	entries := []struct {
+		key   string
+		value string
+	}{
+		{"Name", s.opt.Name},
+		{"Reason", s.opt.Reason},
+		{"Location", s.opt.Location},
+		{"ContactInfo", s.opt.ContactInfo},
+	}
+	for _, entry := range entries {
+		if entry.value == "" {
+			continue
+		}
+		value, err := encodeTextString(entry.value, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /%s %s\n", entry.key, value)
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// size returns the number of bytes reserved for the signature, enough for
+// the certificates and the signed attributes
+func (s *SignatureObj) size() int {
+	size := 4096
+	for _, cert := range s.opt.Certificates {
+		size += len(cert.Raw)
+	}
+	return size
+}
+
+// sign fills in the byte range and the signature of the written document
+func (s *SignatureObj) sign(b []byte) error {
+	end := s.contentsOffset + s.size()*2 + 2
+	byteRange := fmt.Sprintf("0 %d %d %d", s.contentsOffset, end, len(b)-end)
+	if len(byteRange) > len(byteRangePlaceholder) {
+		return errors.New("the document is too large to be signed")
+	}
+	copy(b[s.byteRangeOffset:], byteRange+strings.Repeat(" ", len(byteRangePlaceholder)-len(byteRange)))
+
+	h := sha256.New()
+	h.Write(b[:s.contentsOffset])
+	h.Write(b[end:])
+	signature, err := signPKCS7(h.Sum(nil), s.opt.Signer, s.opt.Certificates, s.time)
+	if err != nil {
+		return err
+	}
+	if len(signature) > s.size() {
+		return fmt.Errorf("the signature of %d bytes exceeds the reserved %d bytes", len(signature), s.size())
+	}
+	copy(b[s.contentsOffset+1:], fmt.Sprintf("%X", signature))
+	return nil
+}
+
+// AddSignatureField adds a signature field to the current page and signs the
+// document when it is written. x, y is the upper left corner of the field,
+// w and h its size, a field of size 0 is invisible. The signature is a
+// detached PKCS#7 signature of the SHA-256 digest of the document. A document
+// can only be signed once, it must not be changed after it is written.
+func (gp *Fpdf) AddSignatureField(name string, x, y, w, h float64, opt SignatureOption) error {
+	if gp.pdfObjs.indexOfFirst(signatureType) >= 0 {
+		return errors.New("the document is already signed")
+	}
+	if opt.Signer == nil || len(opt.Certificates) == 0 {
+		return errors.New("a signature needs a signer and its certificate")
+	}
+	switch opt.Signer.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return fmt.Errorf("unsupported signer key %T", opt.Signer.Public())
+	}
+
+	field, err := gp.newFormField(name, fieldTypeSignature, x, y, w, h, FormFieldOption{})
+	if err != nil {
+		return err
+	}
+	field.font = nil
+
+	if opt.Appearance == nil {
+		field.appearance = gp.addAppearance(field, nil)
+	} else {
+		id, err := gp.registerTplWithResources(opt.Appearance)
+		if err != nil {
+			return err
+		}
+		_, size := opt.Appearance.Size()
+		content := fmt.Sprintf("q %.4f 0 0 %.4f 0 0 cm\n/%s Do Q\n",
+			(field.rect[2]-field.rect[0])/size.W, (field.rect[3]-field.rect[1])/size.H, id)
+		field.appearance = gp.addAppearance(field, []byte(content), id)
+	}
+
+	signature := &SignatureObj{opt: opt}
+	signature.init(func() *Fpdf {
+		return gp
+	})
+	gp.addObj(signature)
+	gp.addWidget(field)
+	return nil
+}
+
+// signature returns the signature of the document, nil if it is not signed
+func (gp *Fpdf) signature() *SignatureObj {
+	if index := gp.pdfObjs.indexOfFirst(signatureType); index >= 0 {
+		return gp.pdfObjs.at(index).(*SignatureObj)
+	}
+	return nil
+}
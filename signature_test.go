@@ -0,0 +1,184 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignature(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, signer := range []crypto.Signer{ecKey, rsaKey} {
+		cert := selfSignedCertificate(t, signer)
+		pdf, err := New(PdfOptionPageSize(300, 400))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+			t.Fatal(err)
+		}
+		pdf.AddPage()
+		if err := pdf.SetFont("times", "", 12); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.Cell(200, 20, "Contract"); err != nil {
+			t.Fatal(err)
+		}
+
+		appearance, err := pdf.CreateTemplateCustom(Point{}, func(tpl *Fpdf) error {
+			if err := tpl.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+				return err
+			}
+			if err := tpl.SetFont("times", "", 12); err != nil {
+				return err
+			}
+			return tpl.Cell(100, 20, "Signed by Jane Doe")
+		}, PdfOptionPageSize(150, 40))
+		if err != nil {
+			t.Fatal(err)
+		}
+		opt := SignatureOption{
+			Signer:       signer,
+			Certificates: []*x509.Certificate{cert},
+			Name:         "Jane Doe",
+			Reason:       "Contract",
+			SigningTime:  time.Date(2020, 2, 3, 4, 5, 6, 0, time.UTC),
+			Appearance:   appearance,
+		}
+		if err := pdf.AddSignatureField("signature", 10, 300, 150, 40, SignatureOption{}); err == nil {
+			t.Error("a signature without a signer should fail")
+		}
+		if err := pdf.AddSignatureField("signature", 10, 300, 150, 40, opt); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.AddSignatureField("again", 0, 0, 0, 0, opt); err == nil {
+			t.Error("signing a document twice should fail")
+		}
+
+		var buff bytes.Buffer
+		if err := pdf.Write(&buff); err != nil {
+			t.Fatal(err)
+		}
+		b := buff.Bytes()
+		if !bytes.Contains(b, []byte("/SigFlags 3")) || !bytes.Contains(b, []byte("/FT /Sig")) {
+			t.Error("the signature field is missing")
+		}
+		if !bytes.Contains(b, []byte("/XObject << /TPL")) {
+			t.Error("the appearance should draw the template")
+		}
+
+		match := regexp.MustCompile(`/ByteRange \[0 (\d+) (\d+) (\d+) *\]`).FindSubmatch(b)
+		if match == nil {
+			t.Fatal("byte range not found")
+		}
+		var byteRange [3]int
+		for x := range byteRange {
+			byteRange[x], _ = strconv.Atoi(string(match[x+1]))
+		}
+		if byteRange[1]+byteRange[2] != len(b) || b[byteRange[0]] != '<' || b[byteRange[1]-1] != '>' {
+			t.Fatalf("the byte range %v does not cover the document", byteRange)
+		}
+
+		contents, err := hex.DecodeString(string(b[byteRange[0]+1 : byteRange[1]-1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.New()
+		digest.Write(b[:byteRange[0]])
+		digest.Write(b[byteRange[1]:])
+		verifyPKCS7(t, contents, digest.Sum(nil), cert)
+	}
+}
+
+// selfSignedCertificate returns a certificate of the public key of signer
+// signed by itself
+func selfSignedCertificate(t *testing.T, signer crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Jane Doe"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// verifyPKCS7 checks that the detached signature was made by the certificate
+// for a document with the digest
+func verifyPKCS7(t *testing.T, signature, digest []byte, cert *x509.Certificate) {
+	var info pkcs7ContentInfo
+	if _, err := asn1.Unmarshal(signature, &info); err != nil {
+		t.Fatal(err)
+	}
+	var signed pkcs7SignedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &signed); err != nil {
+		t.Fatal(err)
+	}
+	if !info.ContentType.Equal(oidSignedData) || len(signed.SignerInfos) != 1 {
+		t.Fatalf("unexpected signature %v", signed)
+	}
+	if !bytes.Equal(signed.Certificates.Bytes, cert.Raw) {
+		t.Error("the certificate is not embedded")
+	}
+
+	signer := signed.SignerInfos[0]
+	if signer.IssuerAndSerialNumber.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Error("the signer does not refer to the certificate")
+	}
+
+	var found bool
+	rest := signer.AuthenticatedAttributes.Bytes
+	for len(rest) > 0 {
+		var attr pkcs7Attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			t.Fatal(err)
+		}
+		if attr.Type.Equal(oidAttrMessageDigest) {
+			found = bytes.Equal(attr.Values[0].Bytes, digest)
+		}
+	}
+	if !found {
+		t.Error("the message digest does not match the document")
+	}
+
+	// the signature covers the attributes tagged as a set
+	attrs := append([]byte(nil), signer.AuthenticatedAttributes.FullBytes...)
+	attrs[0] = 0x31
+	algorithm := x509.SHA256WithRSA
+	if _, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+		algorithm = x509.ECDSAWithSHA256
+	}
+	if err := cert.CheckSignature(algorithm, attrs, signer.EncryptedDigest); err != nil {
+		t.Error(err)
+	}
+}
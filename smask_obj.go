@@ -36,18 +36,14 @@ func (s *SMask) write(w io.Writer, objID int) error {
 		return err
 	}
 
-	fmt.Fprintf(w, "/Length %d\n>>\n", len(s.data)) // /Length 62303>>\n
-	io.WriteString(w, "stream\n")
-	if s.protection() != nil {
-		tmp, err := rc4Cip(s.protection().objectkey(objID), s.data)
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		io.WriteString(w, "\n")
-	} else {
-		w.Write(s.data)
+	data, err := encryptStream(s.protection(), objID, s.data)
+	if err != nil {
+		return err
 	}
+
+	fmt.Fprintf(w, "/Length %d\n>>\n", len(data)) // /Length 62303>>\n
+	io.WriteString(w, "stream\n")
+	w.Write(data)
 	io.WriteString(w, "\nendstream\n")
 
 	return nil
@@ -0,0 +1,133 @@
+package gofpdf
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ISeeMe/gofpdf/geh"
+)
+
+const spotColorType = "SpotColor"
+
+// SpotColorObj Separation color space of an ink-based spot color, with a
+// CMYK alternate for devices that do not have the ink
+type SpotColorObj struct { //impl IObj, ProcsetIObj
+	name       string
+	c, m, y, k uint8
+}
+
+func newSpotColorObj(name string, c, m, y, k uint8) *SpotColorObj {
+	return &SpotColorObj{
+		name: name,
+		c:    percentBound(c),
+		m:    percentBound(m),
+		y:    percentBound(y),
+		k:    percentBound(k),
+	}
+}
+
+func (s *SpotColorObj) getType() string {
+	return spotColorType
+}
+
+func (s *SpotColorObj) write(w io.Writer, objID int) error {
+	fmt.Fprintf(w, "[/Separation /%s\n", escapeName(s.name))
+	io.WriteString(w, "/DeviceCMYK\n")
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "  /FunctionType 2\n")
+	io.WriteString(w, "  /Domain [0 1]\n")
+	io.WriteString(w, "  /Range [0 1 0 1 0 1 0 1]\n")
+	io.WriteString(w, "  /C0 [0 0 0 0]\n")
+	fmt.Fprintf(w, "  /C1 [%.3f %.3f %.3f %.3f]\n",
+		float64(s.c)/cmykManipulant, float64(s.m)/cmykManipulant,
+		float64(s.y)/cmykManipulant, float64(s.k)/cmykManipulant)
+	io.WriteString(w, "  /N 1\n")
+	io.WriteString(w, ">>]\n")
+	return nil
+}
+
+// procsetIdentifier is derived from the name and the alternate color, so the
+// color keeps its name when used through a template
+func (s *SpotColorObj) procsetIdentifier() string {
+	key := fmt.Sprintf("%s %d %d %d %d", s.name, s.c, s.m, s.y, s.k)
+	return fmt.Sprintf("CS%x", sha1.Sum([]byte(key)))[:18]
+}
+
+func (s *SpotColorObj) GobEncode() ([]byte, error) {
+	return geh.EncodeMany(s.name, s.c, s.m, s.y, s.k)
+}
+
+func (s *SpotColorObj) GobDecode(buf []byte) error {
+	return geh.DecodeMany(buf, &s.name, &s.c, &s.m, &s.y, &s.k)
+}
+
+// AddSpotColor adds an ink-based CMYK color to the document and associates it
+// with the specified name. The individual components specify percentages
+// ranging from 0 to 100. Values above this are quietly capped to 100. An
+// error is returned if the specified name is already associated with a color.
+func (gp *Fpdf) AddSpotColor(name string, c, m, y, k uint8) error {
+	if _, ok := gp.spotColors[name]; ok {
+		return fmt.Errorf("name %q is already associated with a spot color", name)
+	}
+
+	if gp.spotColors == nil {
+		gp.spotColors = make(map[string]*SpotColorObj)
+	}
+
+	spot := newSpotColorObj(name, c, m, y, k)
+	gp.addProcsetObj(spot)
+	gp.spotColors[name] = spot
+	return nil
+}
+
+// SetSpotFillColor sets the fill color to the spot color associated with
+// name. The value for tint ranges from 0 (no intensity) to 100 (full
+// intensity), it is quietly bounded to this range. An error is returned if
+// the name is not associated with a color.
+func (gp *Fpdf) SetSpotFillColor(name string, tint uint8) error {
+	return gp.SetFillColor(Spot{Name: name, Tint: tint})
+}
+
+// SetSpotStrokeColor sets the stroke color to the spot color associated with
+// name. See SetSpotFillColor for the tint.
+func (gp *Fpdf) SetSpotStrokeColor(name string, tint uint8) error {
+	return gp.SetStrokeColor(Spot{Name: name, Tint: tint})
+}
+
+// SetSpotTextColor sets the color of the text to the spot color associated
+// with name. See SetSpotFillColor for the tint.
+func (gp *Fpdf) SetSpotTextColor(name string, tint uint8) error {
+	return gp.SetTextColor(Spot{Name: name, Tint: tint})
+}
+
+func (gp *Fpdf) spotColor(name string) (*SpotColorObj, error) {
+	spot, ok := gp.spotColors[name]
+	if !ok {
+		return nil, fmt.Errorf("spot color name %q is not registered", name)
+	}
+	return spot, nil
+}
+
+// percentBound caps a percentage to 100
+func percentBound(v uint8) uint8 {
+	if v > 100 {
+		return 100
+	}
+	return v
+}
+
+// escapeName escapes the characters of a pdf name that are not regular
+// characters
+func escapeName(name string) string {
+	var buff strings.Builder
+	for _, b := range []byte(name) {
+		if b < '!' || b > '~' || strings.IndexByte("()<>[]{}/%#", b) >= 0 {
+			fmt.Fprintf(&buff, "#%02X", b)
+		} else {
+			buff.WriteByte(b)
+		}
+	}
+	return buff.String()
+}
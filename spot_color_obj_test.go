@@ -0,0 +1,121 @@
+package gofpdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSpotColor(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.AddPage()
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.AddSpotColor("PANTONE 185 C", 0, 92, 76, 0); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.AddSpotColor("PANTONE 185 C", 0, 0, 0, 0); err == nil {
+		t.Error("adding a spot color twice should fail")
+	}
+	if err := pdf.SetSpotFillColor("unknown", 100); err == nil {
+		t.Error("an unknown spot color should fail")
+	}
+
+	if err := pdf.SetSpotFillColor("PANTONE 185 C", 50); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+	if err := pdf.SetSpotStrokeColor("PANTONE 185 C", 200); err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.Line(10, 100, 100, 100)
+
+	if err := pdf.SetSpotTextColor("PANTONE 185 C", 100); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdf.Cell(50, 20, "spot"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := pdf.spotColors["PANTONE 185 C"].procsetIdentifier()
+	for _, s := range []string{
+		"/ColorSpace <<\n/" + id,
+		"[/Separation /PANTONE#20185#20C",
+		"/C1 [0.000 0.920 0.760 0.000]",
+		"/" + id + " cs 0.500 scn",
+		"/" + id + " CS 1.000 SCN",
+		"q\n/" + id + " cs 1.000 scn\nBT",
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("%q not found in the document", s)
+		}
+	}
+}
+
+func TestSpotColorInTemplate(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(200, 200))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdf.SetNoCompression()
+	pdf.AddPage()
+
+	if err := pdf.AddSpotColor("Gold", 0, 20, 60, 20); err != nil {
+		t.Error(err)
+		return
+	}
+
+	tpl, err := pdf.CreateTemplate(func(tp *Fpdf) error {
+		if err := tp.SetSpotFillColor("Gold", 100); err != nil {
+			return err
+		}
+		tp.RectFromUpperLeftWithStyle(10, 10, 50, 50, "F")
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := pdf.UseTemplate(tpl); err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if l := len(pdf.pdfObjs.allSpotColors()); l != 1 {
+		t.Errorf("expected 1 spot color, found %d", l)
+	}
+	if bytes.Count(b, []byte("/ColorSpace <<")) != 2 {
+		t.Error("the spot color should be a resource of the page and the template")
+	}
+}
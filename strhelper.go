@@ -1,8 +1,10 @@
 package gofpdf
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
+	"unicode/utf16"
 )
 
 //StrHelperGetStringWidth get string width
@@ -47,3 +49,31 @@ func ReadUShortFromByte(data []byte, offset int) (uint64, int) {
 	num.SetBytes(buff)
 	return num.Uint64(), 2
 }
+
+// encodeTextString encodes a text string as UTF-16BE hexadecimal string with a
+// byte order mark. When protection is enabled the string is encrypted with
+// the key of the object it is written in.
+func encodeTextString(str string, protection *PDFProtection, objID int) (string, error) {
+	units := utf16.Encode([]rune(str))
+	b := make([]byte, 2, 2+len(units)*2)
+	b[0], b[1] = 0xFE, 0xFF
+	for _, u := range units {
+		b = append(b, byte(u>>8), byte(u))
+	}
+
+	return encodeString(b, protection, objID)
+}
+
+// encodeString encodes a string as hexadecimal string, encrypted with the key
+// of the object it is written in when protection is enabled.
+func encodeString(b []byte, protection *PDFProtection, objID int) (string, error) {
+	if protection != nil {
+		var err error
+		b, err = protection.encrypt(objID, b)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("<%X>", b), nil
+}
@@ -0,0 +1,346 @@
+package gofpdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const structTreeRootType = "StructTreeRoot"
+const structElemType = "StructElem"
+
+// standard structure types of tagged documents
+const (
+	TagDocument = "Document"
+	TagSect     = "Sect"
+	TagH1       = "H1"
+	TagH2       = "H2"
+	TagH3       = "H3"
+	TagH4       = "H4"
+	TagH5       = "H5"
+	TagH6       = "H6"
+	TagP        = "P"
+	TagSpan     = "Span"
+	TagTable    = "Table"
+	TagTR       = "TR"
+	TagTH       = "TH"
+	TagTD       = "TD"
+	TagL        = "L"
+	TagLI       = "LI"
+	TagLbl      = "Lbl"
+	TagLBody    = "LBody"
+	TagFigure   = "Figure"
+	TagCaption  = "Caption"
+)
+
+var structureTypes = map[string]bool{
+	TagDocument: true, TagSect: true,
+	TagH1: true, TagH2: true, TagH3: true, TagH4: true, TagH5: true, TagH6: true,
+	TagP: true, TagSpan: true,
+	TagTable: true, TagTR: true, TagTH: true, TagTD: true,
+	TagL: true, TagLI: true, TagLbl: true, TagLBody: true,
+	TagFigure: true, TagCaption: true,
+}
+
+// TagOption option of a structure element
+type TagOption struct {
+	Alt        string // the alternate description, required for figures
+	ActualText string // the text replacing the content, e.g. of a ligature
+	Lang       string // the language of the content, e.g. "en-US"
+}
+
+// structKid a child of a structure element, either another element or the
+// marked content with the mcid on a page
+type structKid struct {
+	elem int // the index of the element, -1 for marked content
+	page int
+	mcid int
+}
+
+// StructTreeRootObj the root of the structure tree of a tagged document
+type StructTreeRootObj struct { //impl IObj
+	kids    []int
+	index   int
+	getRoot func() *Fpdf
+}
+
+func (s *StructTreeRootObj) init(funcGetRoot func() *Fpdf) {
+	s.getRoot = funcGetRoot
+}
+
+func (s *StructTreeRootObj) getType() string {
+	return structTreeRootType
+}
+
+func (s *StructTreeRootObj) write(w io.Writer, objID int) error {
+	gp := s.getRoot()
+
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Type /%s\n", s.getType())
+	io.WriteString(w, "  /K [")
+	for _, kid := range s.kids {
+		fmt.Fprintf(w, " %d 0 R", kid+1)
+	}
+	io.WriteString(w, " ]\n")
+
+	// the elements owning the marked content of the pages, by mcid
+	io.WriteString(w, "  /ParentTree << /Nums [")
+	for _, page := range gp.getAllPages() {
+		if page.structParents < 0 {
+			continue
+		}
+		fmt.Fprintf(w, " %d [", page.structParents)
+		for _, elem := range page.mcids {
+			fmt.Fprintf(w, " %d 0 R", elem.index+1)
+		}
+		io.WriteString(w, " ]")
+	}
+	io.WriteString(w, " ] >>\n")
+	fmt.Fprintf(w, "  /ParentTreeNextKey %d\n", gp.tags.parents)
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// StructElemObj an element of the structure tree
+type StructElemObj struct { //impl IObj
+	tag     string
+	option  TagOption
+	parent  int
+	kids    []structKid
+	index   int
+	getRoot func() *Fpdf
+}
+
+func (s *StructElemObj) init(funcGetRoot func() *Fpdf) {
+	s.getRoot = funcGetRoot
+}
+
+func (s *StructElemObj) getType() string {
+	return structElemType
+}
+
+func (s *StructElemObj) write(w io.Writer, objID int) error {
+	protection := s.getRoot().protection()
+
+	io.WriteString(w, "<<\n")
+	fmt.Fprintf(w, "  /Type /%s\n", s.getType())
+	fmt.Fprintf(w, "  /S /%s\n", s.tag)
+	fmt.Fprintf(w, "  /P %d 0 R\n", s.parent+1)
+	io.WriteString(w, "  /K [")
+	for _, kid := range s.kids {
+		if kid.elem >= 0 {
+			fmt.Fprintf(w, " %d 0 R", kid.elem+1)
+		} else {
+			fmt.Fprintf(w, " << /Type /MCR /Pg %d 0 R /MCID %d >>", kid.page+1, kid.mcid)
+		}
+	}
+	io.WriteString(w, " ]\n")
+
+	entries := []struct {
+		key, value string
+	}{
+		{"Alt", s.option.Alt},
+		{"ActualText", s.option.ActualText},
+		{"Lang", s.option.Lang},
+	}
+	for _, e := range entries {
+		if e.value == "" {
+			continue
+		}
+		value, err := encodeTextString(e.value, protection, objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /%s %s\n", e.key, value)
+	}
+	io.WriteString(w, ">>\n")
+	return nil
+}
+
+// tags the state of the structure tree of a tagged document
+type tags struct {
+	enabled   bool
+	root      *StructTreeRootObj
+	open      []*StructElemObj
+	artifacts int
+	// the next key of the parent tree
+	parents int
+}
+
+type taggedPdfOption struct{}
+
+func (t *taggedPdfOption) apply(gp *Fpdf) error {
+	gp.tags.enabled = true
+	return nil
+}
+
+// PdfOptionTagged creates a PdfOption that makes the document a tagged
+// document, its content is structured with BeginTag and EndTag. Headers and
+// footers are drawn as artifacts.
+func PdfOptionTagged() PdfOption {
+	return &taggedPdfOption{}
+}
+
+type languagePdfOption struct {
+	lang string
+}
+
+func (l *languagePdfOption) apply(gp *Fpdf) error {
+	gp.lang = l.lang
+	return nil
+}
+
+// PdfOptionLanguage creates a PdfOption that sets the natural language of
+// the document, e.g. "en-US"
+func PdfOptionLanguage(lang string) PdfOption {
+	return &languagePdfOption{lang: lang}
+}
+
+// BeginTag begins a structure element of the type tag, one of the Tag
+// constants, inside the element begun last. The text, images and templates
+// drawn until EndTag is called belong to the element, also when pages are
+// added. A figure needs the alternate description of the option.
+func (gp *Fpdf) BeginTag(tag string, opt TagOption) error {
+	if !gp.tags.enabled {
+		return errors.New("the document is not tagged, see PdfOptionTagged")
+	}
+	if !structureTypes[tag] {
+		return fmt.Errorf("unknown structure type %q", tag)
+	}
+	if tag == TagFigure && opt.Alt == "" {
+		return errors.New("a figure needs an alternate description")
+	}
+
+	gp.unmarkContent()
+	root := gp.structTreeRoot()
+	elem := &StructElemObj{
+		tag:    tag,
+		option: opt,
+		parent: root.index,
+	}
+	elem.init(func() *Fpdf {
+		return gp
+	})
+	elem.index = gp.addObj(elem)
+
+	if n := len(gp.tags.open); n > 0 {
+		parent := gp.tags.open[n-1]
+		elem.parent = parent.index
+		parent.kids = append(parent.kids, structKid{elem: elem.index})
+	} else {
+		root.kids = append(root.kids, elem.index)
+	}
+	gp.tags.open = append(gp.tags.open, elem)
+	return nil
+}
+
+// EndTag ends the structure element begun last
+func (gp *Fpdf) EndTag() error {
+	n := len(gp.tags.open)
+	if n == 0 {
+		return errors.New("no structure element has begun")
+	}
+
+	gp.unmarkContent()
+	gp.tags.open = gp.tags.open[:n-1]
+	return nil
+}
+
+// BeginArtifact marks the content drawn until EndArtifact is called as an
+// artifact of a tagged document, content that is not part of its structure
+// like page decorations.
+func (gp *Fpdf) BeginArtifact() {
+	gp.unmarkContent()
+	gp.tags.artifacts++
+}
+
+// EndArtifact ends the artifact begun last, see BeginArtifact
+func (gp *Fpdf) EndArtifact() {
+	if gp.tags.artifacts == 0 {
+		return
+	}
+	gp.unmarkContent()
+	gp.tags.artifacts--
+}
+
+// structTreeRoot returns the root of the structure tree, added to the
+// document on first use
+func (gp *Fpdf) structTreeRoot() *StructTreeRootObj {
+	if gp.tags.root == nil {
+		root := new(StructTreeRootObj)
+		root.init(func() *Fpdf {
+			return gp
+		})
+		root.index = gp.addObj(root)
+		gp.tags.root = root
+	}
+	return gp.tags.root
+}
+
+// markContent begins the marked content of the open structure element or
+// the artifact on the current page, unless it is marked already. Content
+// drawn outside of any element is not marked.
+func (gp *Fpdf) markContent() {
+	page := gp.currentPage()
+	if !gp.tags.enabled || page == nil {
+		return
+	}
+
+	var elem *StructElemObj
+	artifact := gp.tags.artifacts > 0 || gp.inHeader || gp.inFooter
+	if !artifact {
+		n := len(gp.tags.open)
+		if n == 0 {
+			gp.unmarkContent()
+			return
+		}
+		elem = gp.tags.open[n-1]
+	}
+	if page.marked && page.markedElem == elem {
+		return
+	}
+
+	gp.unmarkContent()
+	if artifact {
+		gp.currentContent().AppendStreamMarkedContentBegin("", -1)
+	} else {
+		if page.structParents < 0 {
+			page.structParents = gp.tags.parents
+			gp.tags.parents++
+		}
+		mcid := len(page.mcids)
+		page.mcids = append(page.mcids, elem)
+		elem.kids = append(elem.kids, structKid{elem: -1, page: gp.curr.IndexOfPageObj, mcid: mcid})
+		gp.currentContent().AppendStreamMarkedContentBegin(elem.tag, mcid)
+	}
+	page.marked = true
+	page.markedElem = elem
+}
+
+// unmarkContent ends the marked content on the current page
+func (gp *Fpdf) unmarkContent() {
+	page := gp.currentPage()
+	if page == nil || !page.marked {
+		return
+	}
+
+	gp.currentContent().AppendStreamMarkedContentEnd()
+	page.marked = false
+	page.markedElem = nil
+}
+
+// writeStructure writes the entries of the catalog of a tagged document
+func (gp *Fpdf) writeStructure(w io.Writer, objID int) error {
+	if gp.lang != "" {
+		lang, err := encodeTextString(gp.lang, gp.protection(), objID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /Lang %s\n", lang)
+	}
+	if gp.tags.root != nil {
+		io.WriteString(w, "  /MarkInfo << /Marked true >>\n")
+		fmt.Fprintf(w, "  /StructTreeRoot %d 0 R\n", gp.tags.root.index+1)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package gofpdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTaggedDocument(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 200), PdfOptionNoCompress(), PdfOptionTagged(), PdfOptionLanguage("en-US"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetHeaderFunc(func() {
+		pdf.SetY(10)
+		pdf.Cell(100, 10, "Annual report")
+	})
+	if err := pdf.EndTag(); err == nil {
+		t.Error("ending an element that has not begun should fail")
+	}
+	pdf.AddPage()
+
+	tag := func(tag string, opt TagOption, draw func() error) {
+		if err := pdf.BeginTag(tag, opt); err != nil {
+			t.Fatal(err)
+		}
+		if err := draw(); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.EndTag(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pdf.BeginTag(TagDocument, TagOption{}); err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetY(40)
+	tag(TagH1, TagOption{}, func() error { return pdf.Cell(200, 20, "Results") })
+	// the paragraph continues on the next page
+	tag(TagP, TagOption{Lang: "de-DE"}, func() error {
+		return pdf.MultiCell(200, 20, strings.Repeat("Umsatz und Ertrag. ", 30))
+	})
+	tag(TagFigure, TagOption{Alt: "A gopher"}, func() error {
+		return pdf.Image("test/res/gopher01.jpg", 10, 10, Rect{W: 50, H: 50})
+	})
+	if err := pdf.BeginTag(TagTable, TagOption{}); err != nil {
+		t.Fatal(err)
+	}
+	tag(TagTR, TagOption{}, func() error {
+		for _, cell := range []string{"Q1", "Q2"} {
+			tag(TagTD, TagOption{}, func() error { return pdf.Cell(50, 20, cell) })
+		}
+		return nil
+	})
+	if err := pdf.EndTag(); err != nil {
+		t.Fatal(err)
+	}
+	pdf.BeginArtifact()
+	pdf.Line(10, 190, 290, 190)
+	pdf.Cell(10, 10, "-")
+	pdf.EndArtifact()
+	if err := pdf.EndTag(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		tag string
+		opt TagOption
+	}{{"Blink", TagOption{}}, {TagFigure, TagOption{}}} {
+		if err := pdf.BeginTag(tt.tag, tt.opt); err == nil {
+			t.Errorf("%s should fail", tt.tag)
+		}
+	}
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	if string(textString(catalog["Lang"])) != "en-US" {
+		t.Errorf("unexpected language %v", catalog["Lang"])
+	}
+	if marked := reader.resolve(catalog["MarkInfo"]).(pdfDict)["Marked"]; marked != true {
+		t.Error("the document should be marked")
+	}
+
+	root := reader.resolve(catalog["StructTreeRoot"]).(pdfDict)
+	kids := reader.resolve(root["K"]).(pdfArray)
+	if len(kids) != 1 {
+		t.Fatalf("expected the document element, found %v", kids)
+	}
+	document := reader.resolve(kids[0]).(pdfDict)
+	elems := reader.resolve(document["K"]).(pdfArray)
+	expected := []string{TagH1, TagP, TagFigure, TagTable}
+	if len(elems) != len(expected) {
+		t.Fatalf("expected %d elements, found %d", len(expected), len(elems))
+	}
+	for x, ref := range elems {
+		if elem := reader.resolve(ref).(pdfDict); elem["S"] != pdfName(expected[x]) {
+			t.Errorf("expected %s, found %v", expected[x], elem["S"])
+		}
+	}
+	paragraph := reader.resolve(elems[1]).(pdfDict)
+	if mcrs := reader.resolve(paragraph["K"]).(pdfArray); len(mcrs) < 2 {
+		t.Errorf("the paragraph should be marked on several pages, found %v", mcrs)
+	}
+	if string(textString(paragraph["Lang"])) != "de-DE" {
+		t.Errorf("unexpected language of the paragraph %v", paragraph["Lang"])
+	}
+	figure := reader.resolve(elems[2]).(pdfDict)
+	if string(textString(figure["Alt"])) != "A gopher" {
+		t.Errorf("unexpected alternate description %v", figure["Alt"])
+	}
+
+	// every mcid of a page refers to its element in the parent tree
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nums := reader.resolve(reader.resolve(root["ParentTree"]).(pdfDict)["Nums"]).(pdfArray)
+	if len(nums) != 2*len(pages) {
+		t.Fatalf("expected %d pages in the parent tree, found %v", len(pages), nums)
+	}
+	for x, page := range pages {
+		key, _ := pdfNumber(page["StructParents"])
+		if k, _ := pdfNumber(nums[x*2]); k != key {
+			t.Errorf("page %d has key %v, expected %v", x+1, key, k)
+		}
+		content, err := reader.decodeStream(reader.resolve(page["Contents"]).(pdfStream))
+		if err != nil {
+			t.Fatal(err)
+		}
+		mcids := reader.resolve(nums[x*2+1]).(pdfArray)
+		if n := bytes.Count(content, []byte("<</MCID ")); n != len(mcids) {
+			t.Errorf("page %d has %d marked contents, the parent tree %d", x+1, n, len(mcids))
+		}
+		if bytes.Count(content, []byte("BDC"))+bytes.Count(content, []byte("BMC")) != bytes.Count(content, []byte("EMC")) {
+			t.Errorf("the marked content of page %d is not balanced", x+1)
+		}
+		if !bytes.Contains(content, []byte("/Artifact BMC")) {
+			t.Errorf("the header of page %d should be an artifact", x+1)
+		}
+	}
+}
+
+func TestUntaggedDocument(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 200), PdfOptionLanguage("fr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if err := pdf.BeginTag(TagP, TagOption{}); err == nil {
+		t.Error("tagging an untagged document should fail")
+	}
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("/StructTreeRoot")) || !bytes.Contains(b, []byte("/Lang")) {
+		t.Error("an untagged document should only have a language")
+	}
+}
+
+func TestTaggedShapes(t *testing.T) {
+	pdf, err := New(PdfOptionPageSize(300, 200), PdfOptionNoCompress(), PdfOptionTagged())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.AddTTFFont("times", "test/res/times.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetFont("times", "", 12); err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetHeaderFunc(func() {
+		pdf.Line(10, 5, 290, 5)
+	})
+	pdf.AddPage()
+
+	if err := pdf.BeginTag(TagP, TagOption{}); err != nil {
+		t.Fatal(err)
+	}
+	// the rectangle is drawn before the text of the paragraph
+	pdf.RectFromUpperLeftWithStyle(10, 40, 100, 20, "D")
+	pdf.SetY(40)
+	if err := pdf.Cell(100, 20, "Boxed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.EndTag(); err != nil {
+		t.Fatal(err)
+	}
+	pdf.BeginArtifact()
+	pdf.Line(10, 180, 290, 180)
+	pdf.EndArtifact()
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reader.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := reader.decodeStream(reader.resolve(pages[0]["Contents"]).(pdfStream))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the lines are artifacts, the rectangle is part of the paragraph
+	for _, s := range []string{
+		"/Artifact BMC\n10.00 195.00 m 290.00 195.00 l S\nEMC\n",
+		"/Artifact BMC\n10.00 20.00 m 290.00 20.00 l S\nEMC\n",
+	} {
+		if !bytes.Contains(content, []byte(s)) {
+			t.Errorf("%q not found in the content %q", s, content)
+		}
+	}
+	begin := bytes.Index(content, []byte("/P <</MCID 0>> BDC\n"))
+	rect := bytes.Index(content, []byte(" re S\n"))
+	if begin < 0 || rect < begin || bytes.Contains(content[begin:rect], []byte("EMC")) {
+		t.Errorf("the rectangle should be marked as the paragraph %q", content)
+	}
+}
@@ -44,6 +44,17 @@ type Template interface {
 	gob.GobEncoder
 }
 
+// templateResources is implemented by templates holding graphics states,
+// shadings or spot colors, they are added to the documents the template is
+// used in
+type templateResources interface {
+	ExtGStates() []*ExtGStateObj
+	Shadings() []*ShadingObj
+	SpotColors() []*SpotColorObj
+	// usesCMYK returns true if the content sets a DeviceCMYK color
+	usesCMYK() bool
+}
+
 func NewTemplateFont(sf *SubsetFontObj) *TemplateFont {
 	return &TemplateFont{
 		subsetFont: sf,
@@ -113,6 +124,7 @@ func (gp *Fpdf) Template(corner Point) (*FpdfTpl, error) {
 	numPages := len(pages)
 	bytes := make([][]byte, numPages)
 	sizes := make([]Rect, numPages)
+	cmyk := make([]bool, numPages)
 
 	for x := 0; x < numPages; x++ {
 		page := pages[x]
@@ -121,6 +133,7 @@ func (gp *Fpdf) Template(corner Point) (*FpdfTpl, error) {
 		if err != nil {
 			return nil, err
 		}
+		cmyk[x] = usesCMYK(content.listCache.caches)
 
 		if pb := page.pageOption.GetBoundary(PageBoundaryMedia); pb != nil {
 			sizes[x] = gp.GetBoundarySize(PageBoundaryMedia)
@@ -131,24 +144,33 @@ func (gp *Fpdf) Template(corner Point) (*FpdfTpl, error) {
 		corner: corner,
 		size:   sizes,
 		bytes:  bytes,
+		cmyk:   cmyk,
 		page:   len(bytes) - 1,
 	}
 
 	fpdf.fonts = gp.getAllSubsetFonts()
 	fpdf.images = gp.getAllImages()
+	fpdf.extGStates = gp.pdfObjs.allExtGStates()
+	fpdf.shadings = gp.pdfObjs.allShadings()
+	fpdf.spotColors = gp.pdfObjs.allSpotColors()
 	fpdf.templates, err = gp.getTemplates()
 	return fpdf, err
 }
 
 // FpdfTpl is a concrete implementation of the Template interface.
 type FpdfTpl struct {
-	corner    Point
-	size      []Rect
-	bytes     [][]byte
-	fonts     []*SubsetFontObj
-	images    []*ImageObj
-	templates []Template
-	page      int
+	corner     Point
+	size       []Rect
+	bytes      [][]byte
+	fonts      []*SubsetFontObj
+	images     []*ImageObj
+	extGStates []*ExtGStateObj
+	shadings   []*ShadingObj
+	spotColors []*SpotColorObj
+	templates  []Template
+	page       int
+	// cmyk tells for every page if its content sets a DeviceCMYK color
+	cmyk []bool
 }
 
 // ID returns the global template identifier
@@ -212,6 +234,25 @@ func (t *FpdfTpl) Fonts() []*SubsetFontObj {
 	return t.fonts
 }
 
+// ExtGStates returns a list of the graphics states used in the template
+func (t *FpdfTpl) ExtGStates() []*ExtGStateObj {
+	return t.extGStates
+}
+
+// Shadings returns a list of the shadings used in the template
+func (t *FpdfTpl) Shadings() []*ShadingObj {
+	return t.shadings
+}
+
+// SpotColors returns a list of the spot colors used in the template
+func (t *FpdfTpl) SpotColors() []*SpotColorObj {
+	return t.spotColors
+}
+
+func (t *FpdfTpl) usesCMYK() bool {
+	return t.page < len(t.cmyk) && t.cmyk[t.page]
+}
+
 // Templates returns a list of templates used in this template
 func (t *FpdfTpl) Templates() []Template {
 	return t.templates
@@ -246,14 +287,14 @@ func DeserializeTemplate(b []byte) (Template, error) {
 // GobEncode encodes the receiving template into a byte buffer. Use GobDecode
 // to decode the byte buffer back to a template.
 func (t *FpdfTpl) GobEncode() ([]byte, error) {
-	return geh.EncodeMany(t.templates, t.images, t.fonts, t.corner, t.size, t.bytes, t.page)
+	return geh.EncodeMany(t.templates, t.images, t.fonts, t.corner, t.size, t.bytes, t.page, t.extGStates, t.shadings, t.spotColors, t.cmyk)
 }
 
 // GobDecode decodes the specified byte buffer into the receiving template.
 func (t *FpdfTpl) GobDecode(buf []byte) error {
 	tpls := make([]*FpdfTpl, 0)
 
-	if err := geh.DecodeMany(buf, &tpls, &t.images, &t.fonts, &t.corner, &t.size, &t.bytes, &t.page); err != nil {
+	if err := geh.DecodeMany(buf, &tpls, &t.images, &t.fonts, &t.corner, &t.size, &t.bytes, &t.page, &t.extGStates, &t.shadings, &t.spotColors, &t.cmyk); err != nil {
 		return err
 	}
 
@@ -279,6 +320,14 @@ func (gp *Fpdf) loadParamsFromFpdf(f *Fpdf) {
 	gp.curr.Font_Size = f.curr.Font_Size
 	gp.curr.Font_Style = f.curr.Font_Style
 	gp.curr.Font_Type = f.curr.Font_Type
+
+	for alias, kind := range f.aliases {
+		gp.setAlias(alias, kind)
+	}
+
+	for name, spot := range f.spotColors {
+		gp.AddSpotColor(name, spot.c, spot.m, spot.y, spot.k)
+	}
 }
 
 func (gp *Fpdf) loadFontsFromFpdf(f *Fpdf) {
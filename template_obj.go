@@ -1,8 +1,11 @@
 package gofpdf
 
 import (
+	"compress/zlib"
 	"fmt"
 	"io"
+
+	"github.com/ISeeMe/gofpdf/bp"
 )
 
 const templateType = "Template"
@@ -15,11 +18,20 @@ type TemplateObj struct {
 	getRoot       func() *Fpdf
 	images        []*ImageObj
 	fonts         []*SubsetFontObj
+	extGStates    []*ExtGStateObj
+	shadings      []*ShadingObj
+	spotColors    []*SpotColorObj
+	cmyk          bool
 	templates     []Template
 	x, y          float64
 	w, h          float64
 	b             []byte
 	id            string
+	// pageIndex is the index of the page a template containing the page
+	// number alias is written for, -1 otherwise
+	pageIndex int
+	// imported is the page of an existing document the template holds
+	imported *ImportedTpl
 }
 
 func newTemplateObj(template Template, p *PDFProtection, funcGetRoot func() *Fpdf) *TemplateObj {
@@ -27,6 +39,12 @@ func newTemplateObj(template Template, p *PDFProtection, funcGetRoot func() *Fpd
 	tpl.fonts = template.Fonts()
 	tpl.templates = template.Templates()
 	tpl.images = template.Images()
+	if resources, ok := template.(templateResources); ok {
+		tpl.extGStates = resources.ExtGStates()
+		tpl.shadings = resources.Shadings()
+		tpl.spotColors = resources.SpotColors()
+		tpl.cmyk = resources.usesCMYK()
+	}
 	point, size := template.Size()
 	tpl.x, tpl.y = point.X, point.Y
 	tpl.w, tpl.h = size.W, size.H
@@ -35,6 +53,15 @@ func newTemplateObj(template Template, p *PDFProtection, funcGetRoot func() *Fpd
 	tpl.fonts = template.Fonts()
 	tpl.getRoot = funcGetRoot
 	tpl.pdfProtection = p
+	tpl.pageIndex = -1
+	tpl.imported, _ = template.(*ImportedTpl)
+	return tpl
+}
+
+// newTemplatePageObj creates the variant of a template that is used on the page at index
+func newTemplatePageObj(template Template, pageIndex int, p *PDFProtection, funcGetRoot func() *Fpdf) *TemplateObj {
+	tpl := newTemplateObj(template, p, funcGetRoot)
+	tpl.pageIndex = pageIndex
 	return tpl
 }
 
@@ -47,6 +74,10 @@ func (tpl *TemplateObj) protection() *PDFProtection {
 }
 
 func (tpl *TemplateObj) write(w io.Writer, objID int) error {
+	if tpl.imported != nil {
+		return tpl.writeImported(w, objID)
+	}
+
 	io.WriteString(w, "<</Type /XObject\n")
 	io.WriteString(w, "/Subtype /Form\n")
 	io.WriteString(w, "/Formtype 1\n")
@@ -66,7 +97,7 @@ func (tpl *TemplateObj) write(w io.Writer, objID int) error {
 	}
 	io.WriteString(w, ">>\n")
 
-	if len(tpl.images) > 0 { //|| len(tTemplates) > 0 {
+	if len(tpl.images) > 0 || len(tpl.templates) > 0 {
 		io.WriteString(w, "/XObject <<\n")
 		for x := 0; x < len(tpl.images); x++ {
 			id := tpl.images[x].procsetIdentifier()
@@ -74,25 +105,57 @@ func (tpl *TemplateObj) write(w io.Writer, objID int) error {
 		}
 		for x := 0; x < len(tpl.templates); x++ {
 			id := fmt.Sprintf("TPL%s", tpl.templates[x].ID())
+			pid := id
+			if tpl.pageIndex >= 0 && templateHasPageAlias(tpl.templates[x]) {
+				pid = templatePageID(tpl.templates[x].ID(), tpl.pageIndex)
+			}
+			fmt.Fprintf(w, "/%s %d 0 R\n", id, tpl.getProcsetIndex(pid)+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
+
+	if len(tpl.extGStates) > 0 {
+		io.WriteString(w, "/ExtGState <<\n")
+		for x := 0; x < len(tpl.extGStates); x++ {
+			id := tpl.extGStates[x].procsetIdentifier()
 			fmt.Fprintf(w, "/%s %d 0 R\n", id, tpl.getProcsetIndex(id)+1)
 		}
 		io.WriteString(w, ">>\n")
 	}
 
-	io.WriteString(w, ">>\n")
+	if len(tpl.spotColors) > 0 {
+		io.WriteString(w, "/ColorSpace <<\n")
+		for x := 0; x < len(tpl.spotColors); x++ {
+			id := tpl.spotColors[x].procsetIdentifier()
+			fmt.Fprintf(w, "/%s %d 0 R\n", id, tpl.getProcsetIndex(id)+1)
+		}
+		io.WriteString(w, ">>\n")
+	}
 
-	fmt.Fprintf(w, "/Length %d\n>>\n", len(tpl.b))
-	io.WriteString(w, "stream\n")
-	if tpl.protection() != nil {
-		tmp, err := rc4Cip(tpl.protection().objectkey(objID), tpl.b)
-		if err != nil {
-			return err
+	if len(tpl.shadings) > 0 {
+		io.WriteString(w, "/Shading <<\n")
+		for x := 0; x < len(tpl.shadings); x++ {
+			id := tpl.shadings[x].procsetIdentifier()
+			fmt.Fprintf(w, "/%s %d 0 R\n", id, tpl.getProcsetIndex(id)+1)
 		}
-		w.Write(tmp)
-		io.WriteString(w, "\n")
-	} else {
-		w.Write(tpl.b)
+		io.WriteString(w, ">>\n")
+	}
+
+	io.WriteString(w, ">>\n")
+
+	b, err := tpl.getRoot().resolveAliasPlaceholders(tpl.b, tpl.fonts, tpl.pageIndex)
+	if err != nil {
+		return err
 	}
+
+	b, err = encryptStream(tpl.protection(), objID, b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "/Length %d\n>>\n", len(b))
+	io.WriteString(w, "stream\n")
+	w.Write(b)
 	io.WriteString(w, "\nendstream\n")
 
 	return nil
@@ -102,22 +165,83 @@ func (tpl *TemplateObj) getType() string {
 	return templateType
 }
 
+// writeImported writes the form of a page of an existing document, its
+// resources refer to the objects copied from the document
+func (tpl *TemplateObj) writeImported(w io.Writer, objID int) error {
+	root := tpl.getRoot()
+	page := tpl.imported.pages[tpl.imported.page]
+
+	b := tpl.b
+	isFlate := root.compressLevel != zlib.NoCompression
+	if isFlate {
+		buff := bp.GetBuffer()
+		defer bp.PutBuffer(buff)
+		zw, err := zlib.NewWriterLevel(buff, root.compressLevel)
+		if err != nil {
+			return err
+		}
+		zw.Write(b)
+		zw.Close()
+		b = buff.Bytes()
+	}
+
+	b, err := encryptStream(tpl.protection(), objID, b)
+	if err != nil {
+		return err
+	}
+
+	m := page.matrix()
+	io.WriteString(w, "<</Type /XObject\n")
+	io.WriteString(w, "/Subtype /Form\n")
+	io.WriteString(w, "/FormType 1\n")
+	fmt.Fprintf(w, "/BBox [%.4f %.4f %.4f %.4f]\n", page.Box[0], page.Box[1], page.Box[2], page.Box[3])
+	fmt.Fprintf(w, "/Matrix [%.4f %.4f %.4f %.4f %.4f %.4f]\n", m[0], m[1], m[2], m[3], m[4], m[5])
+	io.WriteString(w, "/Resources ")
+	if err := root.writeImportedValue(w, tpl.imported.source, page.Resources, objID); err != nil {
+		return err
+	}
+	io.WriteString(w, "\n")
+	if isFlate {
+		io.WriteString(w, "/Filter /FlateDecode\n")
+	}
+	fmt.Fprintf(w, "/Length %d\n>>\n", len(b))
+	io.WriteString(w, "stream\n")
+	w.Write(b)
+	io.WriteString(w, "\nendstream\n")
+	return nil
+}
+
 func (tpl *TemplateObj) ToTemplate() Template {
+	if tpl.imported != nil {
+		return tpl.imported
+	}
+
 	return &FpdfTpl{
-		corner:    Point{X: tpl.x, Y: tpl.y},
-		size:      []Rect{Rect{W: tpl.w, H: tpl.h}},
-		bytes:     [][]byte{tpl.b},
-		fonts:     tpl.fonts,
-		images:    tpl.images,
-		templates: tpl.templates,
-		page:      0,
+		corner:     Point{X: tpl.x, Y: tpl.y},
+		size:       []Rect{Rect{W: tpl.w, H: tpl.h}},
+		bytes:      [][]byte{tpl.b},
+		fonts:      tpl.fonts,
+		images:     tpl.images,
+		extGStates: tpl.extGStates,
+		shadings:   tpl.shadings,
+		spotColors: tpl.spotColors,
+		templates:  tpl.templates,
+		page:       0,
 	}
 }
 
 func (tpl *TemplateObj) procsetIdentifier() string {
+	if tpl.pageIndex >= 0 {
+		return templatePageID(tpl.id, tpl.pageIndex)
+	}
 	return fmt.Sprintf("TPL%s", tpl.id)
 }
 
+// templatePageID returns the identifier of the variant of a template used on the page at index
+func templatePageID(id string, pageIndex int) string {
+	return fmt.Sprintf("TPL%sP%d", id, pageIndex)
+}
+
 func (tpl *TemplateObj) getProcsetIndex(pid string) int {
 	id, _ := tpl.getRoot().pdfObjs.hasProcsetID(pid)
 	return id
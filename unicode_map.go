@@ -81,21 +81,17 @@ func (u *UnicodeMap) write(w io.Writer, objID int) error {
 	buff.WriteString(suffix)
 	buff.WriteString("\n")
 
+	data, err := encryptStream(u.protection(), objID, buff.Bytes())
+	if err != nil {
+		return err
+	}
+
 	io.WriteString(w, "<<\n")
-	fmt.Fprintf(w, "/Length %d\n", buff.Len())
+	fmt.Fprintf(w, "/Length %d\n", len(data))
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
-	if u.protection() != nil {
-		tmp, err := rc4Cip(u.protection().objectkey(objID), buff.Bytes())
-		if err != nil {
-			return err
-		}
-		w.Write(tmp)
-		//streambuff.WriteString("\n")
-	} else {
-		buff.WriteTo(w)
-	}
-	io.WriteString(w, "endstream\n")
+	w.Write(data)
+	io.WriteString(w, "\nendstream\n")
 
 	return nil
 }
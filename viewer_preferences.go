@@ -0,0 +1,214 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+)
+
+// page layouts used by the viewer to show the document
+const (
+	// PageLayoutSinglePage shows one page at a time
+	PageLayoutSinglePage = iota + 1
+	// PageLayoutOneColumn shows the pages in a column
+	PageLayoutOneColumn
+	// PageLayoutTwoColumnLeft shows the pages in two columns, odd pages left
+	PageLayoutTwoColumnLeft
+	// PageLayoutTwoColumnRight shows the pages in two columns, odd pages right
+	PageLayoutTwoColumnRight
+	// PageLayoutTwoPageLeft shows two pages at a time, odd pages left
+	PageLayoutTwoPageLeft
+	// PageLayoutTwoPageRight shows two pages at a time, odd pages right
+	PageLayoutTwoPageRight
+)
+
+var pageLayoutNames = map[int]string{
+	PageLayoutSinglePage:     "SinglePage",
+	PageLayoutOneColumn:      "OneColumn",
+	PageLayoutTwoColumnLeft:  "TwoColumnLeft",
+	PageLayoutTwoColumnRight: "TwoColumnRight",
+	PageLayoutTwoPageLeft:    "TwoPageLeft",
+	PageLayoutTwoPageRight:   "TwoPageRight",
+}
+
+// page modes, the panel shown by the viewer when the document is opened
+const (
+	// PageModeUseNone shows no panel
+	PageModeUseNone = iota + 1
+	// PageModeUseOutlines shows the bookmarks
+	PageModeUseOutlines
+	// PageModeUseThumbs shows the page thumbnails
+	PageModeUseThumbs
+	// PageModeFullScreen opens the document in full screen
+	PageModeFullScreen
+	// PageModeUseOC shows the layers
+	PageModeUseOC
+	// PageModeUseAttachments shows the attached files
+	PageModeUseAttachments
+)
+
+var pageModeNames = map[int]string{
+	PageModeUseNone:        "UseNone",
+	PageModeUseOutlines:    "UseOutlines",
+	PageModeUseThumbs:      "UseThumbs",
+	PageModeFullScreen:     "FullScreen",
+	PageModeUseOC:          "UseOC",
+	PageModeUseAttachments: "UseAttachments",
+}
+
+// duplex modes of the print dialog
+const (
+	// DuplexSimplex prints on one side of the paper
+	DuplexSimplex = iota + 1
+	// DuplexFlipShortEdge prints on both sides, flipping on the short edge
+	DuplexFlipShortEdge
+	// DuplexFlipLongEdge prints on both sides, flipping on the long edge
+	DuplexFlipLongEdge
+)
+
+var duplexNames = map[int]string{
+	DuplexSimplex:       "Simplex",
+	DuplexFlipShortEdge: "DuplexFlipShortEdge",
+	DuplexFlipLongEdge:  "DuplexFlipLongEdge",
+}
+
+// ViewerPreferences controls how the viewer shows and prints the document
+type ViewerPreferences struct {
+	HideToolbar     bool // hide the tool bars
+	FitWindow       bool // resize the window to the first page
+	DisplayDocTitle bool // show the title instead of the file name
+	NoPrintScaling  bool // print the pages at their actual size
+	Duplex          int  // one of the Duplex constants, 0 for the default
+	NumCopies       int  // copies printed by default, 0 for the default
+}
+
+// viewerOptions how the document is shown when it is opened
+type viewerOptions struct {
+	pageLayout  int
+	pageMode    int
+	preferences *ViewerPreferences
+	openAction  *Destination
+}
+
+type pageLayoutPdfOption struct {
+	layout int
+}
+
+func (p *pageLayoutPdfOption) apply(gp *Fpdf) error {
+	if _, ok := pageLayoutNames[p.layout]; !ok {
+		return fmt.Errorf("page layout %d is not valid", p.layout)
+	}
+	gp.viewer.pageLayout = p.layout
+	return nil
+}
+
+// PdfOptionPageLayout creates a PdfOption that sets the page layout used by
+// the viewer, one of the PageLayout constants
+func PdfOptionPageLayout(layout int) PdfOption {
+	return &pageLayoutPdfOption{layout: layout}
+}
+
+type pageModePdfOption struct {
+	mode int
+}
+
+func (p *pageModePdfOption) apply(gp *Fpdf) error {
+	if _, ok := pageModeNames[p.mode]; !ok {
+		return fmt.Errorf("page mode %d is not valid", p.mode)
+	}
+	gp.viewer.pageMode = p.mode
+	return nil
+}
+
+// PdfOptionPageMode creates a PdfOption that sets the panel shown by the
+// viewer, one of the PageMode constants. It replaces the bookmarks or layers
+// panel opened by default.
+func PdfOptionPageMode(mode int) PdfOption {
+	return &pageModePdfOption{mode: mode}
+}
+
+type viewerPreferencesPdfOption struct {
+	preferences ViewerPreferences
+}
+
+func (v *viewerPreferencesPdfOption) apply(gp *Fpdf) error {
+	if _, ok := duplexNames[v.preferences.Duplex]; !ok && v.preferences.Duplex != 0 {
+		return fmt.Errorf("duplex mode %d is not valid", v.preferences.Duplex)
+	}
+	if v.preferences.NumCopies < 0 {
+		return fmt.Errorf("number of copies %d is not valid", v.preferences.NumCopies)
+	}
+	preferences := v.preferences
+	gp.viewer.preferences = &preferences
+	return nil
+}
+
+// PdfOptionViewerPreferences creates a PdfOption that sets the preferences of
+// the viewer
+func PdfOptionViewerPreferences(preferences ViewerPreferences) PdfOption {
+	return &viewerPreferencesPdfOption{preferences: preferences}
+}
+
+type openActionPdfOption struct {
+	dest Destination
+}
+
+func (o *openActionPdfOption) apply(gp *Fpdf) error {
+	if o.dest.Page < 1 {
+		return fmt.Errorf("page %d is not valid", o.dest.Page)
+	}
+	if err := o.dest.validate(); err != nil {
+		return err
+	}
+	gp.viewer.openAction = &o.dest
+	return nil
+}
+
+// PdfOptionOpenAction creates a PdfOption that opens the document at page,
+// starting at 1, fitted into the window by fit, one of the Fit constants
+func PdfOptionOpenAction(page int, fit int) PdfOption {
+	return &openActionPdfOption{dest: Destination{Page: page, Fit: fit}}
+}
+
+// writeViewerOptions writes the entries of the catalog setting how the
+// document is shown, the page mode is written if set explicitly
+func (gp *Fpdf) writeViewerOptions(w io.Writer) error {
+	v := gp.viewer
+	if v.pageLayout != 0 {
+		fmt.Fprintf(w, "  /PageLayout /%s\n", pageLayoutNames[v.pageLayout])
+	}
+	if v.pageMode != 0 {
+		fmt.Fprintf(w, "  /PageMode /%s\n", pageModeNames[v.pageMode])
+	}
+
+	if p := v.preferences; p != nil {
+		io.WriteString(w, "  /ViewerPreferences <<")
+		if p.HideToolbar {
+			io.WriteString(w, " /HideToolbar true")
+		}
+		if p.FitWindow {
+			io.WriteString(w, " /FitWindow true")
+		}
+		if p.DisplayDocTitle {
+			io.WriteString(w, " /DisplayDocTitle true")
+		}
+		if p.NoPrintScaling {
+			io.WriteString(w, " /PrintScaling /None")
+		}
+		if p.Duplex != 0 {
+			fmt.Fprintf(w, " /Duplex /%s", duplexNames[p.Duplex])
+		}
+		if p.NumCopies > 0 {
+			fmt.Fprintf(w, " /NumCopies %d", p.NumCopies)
+		}
+		io.WriteString(w, " >>\n")
+	}
+
+	if v.openAction != nil {
+		dest, err := gp.destinationArray(*v.openAction, 0)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  /OpenAction %s\n", dest)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gofpdf
+
+import (
+	"testing"
+)
+
+func TestViewerPreferences(t *testing.T) {
+	pdf, err := New(
+		PdfOptionPageSize(200, 200),
+		PdfOptionTitle("Report"),
+		PdfOptionPageLayout(PageLayoutTwoColumnRight),
+		PdfOptionPageMode(PageModeUseThumbs),
+		PdfOptionViewerPreferences(ViewerPreferences{
+			HideToolbar:     true,
+			DisplayDocTitle: true,
+			NoPrintScaling:  true,
+			Duplex:          DuplexFlipLongEdge,
+			NumCopies:       2,
+		}),
+		PdfOptionOpenAction(2, FitWidth),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	pdf.AddBookmark("Chapter 1", 0)
+	pdf.AddPage()
+
+	b, err := pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := newPdfReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := reader.resolve(reader.trailer["Root"]).(pdfDict)
+	// the explicit page mode replaces the bookmarks panel
+	if catalog["PageLayout"] != pdfName("TwoColumnRight") || catalog["PageMode"] != pdfName("UseThumbs") {
+		t.Errorf("unexpected layout %v or mode %v", catalog["PageLayout"], catalog["PageMode"])
+	}
+
+	prefs := reader.resolve(catalog["ViewerPreferences"]).(pdfDict)
+	if prefs["HideToolbar"] != true || prefs["DisplayDocTitle"] != true {
+		t.Errorf("unexpected preferences %v", prefs)
+	}
+	if _, ok := prefs["FitWindow"]; ok {
+		t.Error("FitWindow should not be written")
+	}
+	if prefs["PrintScaling"] != pdfName("None") || prefs["Duplex"] != pdfName("DuplexFlipLongEdge") {
+		t.Errorf("unexpected print preferences %v", prefs)
+	}
+	if n, _ := pdfNumber(prefs["NumCopies"]); n != 2 {
+		t.Errorf("unexpected number of copies %v", prefs["NumCopies"])
+	}
+
+	action := reader.resolve(catalog["OpenAction"]).(pdfArray)
+	second := pdfRef{Num: pdf.pdfObjs.typeMap[pageType][1] + 1}
+	if top, _ := pdfNumber(action[2]); len(action) != 3 || action[0] != second || action[1] != pdfName("FitH") || top != 200 {
+		t.Errorf("unexpected open action %v", action)
+	}
+}
+
+func TestViewerOptionErrors(t *testing.T) {
+	for _, opt := range []PdfOption{
+		PdfOptionPageLayout(0),
+		PdfOptionPageMode(42),
+		PdfOptionViewerPreferences(ViewerPreferences{Duplex: 7}),
+		PdfOptionViewerPreferences(ViewerPreferences{NumCopies: -1}),
+		PdfOptionOpenAction(0, FitPage),
+		PdfOptionOpenAction(1, 42),
+	} {
+		if _, err := New(opt); err == nil {
+			t.Errorf("option %#v should fail", opt)
+		}
+	}
+
+	pdf, err := New(PdfOptionOpenAction(3, FitPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	if _, err := pdf.GetBytesPdfReturnErr(); err == nil {
+		t.Error("opening a missing page should fail")
+	}
+}